@@ -0,0 +1,28 @@
+package account
+
+import (
+	"easy-quizy/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LinkCodeResponse struct {
+	Code      string    `json:"code"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type LinkRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type LinkResponse struct {
+	UserID uuid.UUID `json:"userId"`
+}
+
+func toLinkCodeResponse(code model.LinkCode) LinkCodeResponse {
+	return LinkCodeResponse{
+		Code:      code.Code,
+		ExpiresAt: code.ExpiresAt,
+	}
+}
@@ -0,0 +1,103 @@
+package account
+
+import (
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/middleware"
+	"easy-quizy/internal/model"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	accounts contracts.AccountUsecase
+}
+
+func NewHandler(accounts contracts.AccountUsecase) *Handler {
+	return &Handler{
+		accounts: accounts,
+	}
+}
+
+// Register эндпоинты привязки источников, router должен проходить через AuthMiddleware
+func (h *Handler) Register(router *gin.RouterGroup) {
+	meGroup := router.Group("/api/me")
+	meGroup.POST("/link-code", h.createLinkCode)
+	meGroup.POST("/link", h.link)
+	meGroup.DELETE("/sources/:source/:source_id", h.unlink)
+}
+
+// createLinkCode выдает одноразовый код для привязки другого источника к текущему игроку
+func (h *Handler) createLinkCode(c *gin.Context) {
+	playerID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user ID from context"})
+		return
+	}
+
+	code, err := h.accounts.CreateLinkCode(c.Request.Context(), playerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, toLinkCodeResponse(code))
+}
+
+// link привязывает текущий источник к владельцу кода, данные текущего игрока переходят владельцу
+func (h *Handler) link(c *gin.Context) {
+	playerID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user ID from context"})
+		return
+	}
+
+	var req LinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body: " + err.Error()})
+		return
+	}
+
+	user, err := h.accounts.Link(c.Request.Context(), playerID, req.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, contracts.ErrLinkCodeNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, contracts.ErrAccountsAlreadyLinked):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, LinkResponse{UserID: user.ID})
+}
+
+// unlink отделяет другой источник текущего игрока, его данные остаются у игрока
+func (h *Handler) unlink(c *gin.Context) {
+	playerID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user ID from context"})
+		return
+	}
+
+	current := model.UserSource{IDext: c.GetHeader("X-Player-ID"), Source: c.GetHeader("X-Source")}
+	target := model.UserSource{IDext: c.Param("source_id"), Source: c.Param("source")}
+
+	err := h.accounts.Unlink(c.Request.Context(), playerID, current, target)
+	if err != nil {
+		switch {
+		case errors.Is(err, contracts.ErrUserSourceNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, contracts.ErrCannotUnlinkCurrentSource):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
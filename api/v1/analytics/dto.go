@@ -0,0 +1,228 @@
+package analytics
+
+import (
+	"easy-quizy/internal/model"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	formatCSV = "csv"
+
+	// defaultFunnelDays длина ряда воронки, если не задан from
+	defaultFunnelDays = 30
+)
+
+type StatsQuery struct {
+	Format string `form:"format"`
+}
+
+// FunnelQuery даты в формате 2006-01-02 (UTC), по умолчанию последние 30 дней
+type FunnelQuery struct {
+	GameID string `form:"game_id"`
+	From   string `form:"from"`
+	To     string `form:"to"`
+}
+
+type RebuildFunnelQuery struct {
+	From string `form:"from" binding:"required"`
+}
+
+type FunnelDay struct {
+	Day       string `json:"day"`
+	Opened    int64  `json:"opened"`
+	Answered  int64  `json:"answered"`
+	Completed int64  `json:"completed"`
+	Resets    int64  `json:"resets"`
+}
+
+type FunnelResponse struct {
+	GameID *uuid.UUID  `json:"gameId,omitempty"`
+	From   string      `json:"from"`
+	To     string      `json:"to"`
+	Days   []FunnelDay `json:"days"`
+}
+
+type OptionStats struct {
+	OptionID  int64   `json:"optionId"`
+	Text      string  `json:"text"`
+	IsCorrect bool    `json:"isCorrect"`
+	Count     int64   `json:"count"`
+	Share     float64 `json:"share"`
+}
+
+type QuestionStats struct {
+	QuestionID          int64         `json:"questionId"`
+	Text                string        `json:"text"`
+	Options             []OptionStats `json:"options"`
+	Answers             int64         `json:"answers"`
+	Correct             int64         `json:"correct"`
+	CorrectRate         float64       `json:"correctRate"`
+	MedianAnswerSeconds *float64      `json:"medianAnswerSeconds,omitempty"`
+	Reached             int64         `json:"reached"`
+	Dropped             int64         `json:"dropped"`
+	DropOffRate         float64       `json:"dropOffRate"`
+}
+
+type GameStatsResponse struct {
+	GameID     uuid.UUID       `json:"gameId"`
+	Title      string          `json:"title"`
+	ComputedAt *time.Time      `json:"computedAt,omitempty"`
+	Questions  []QuestionStats `json:"questions"`
+}
+
+func toGameStatsResponse(stats model.GameQuestionStats) GameStatsResponse {
+	resp := GameStatsResponse{
+		GameID:     stats.GameID,
+		Title:      stats.Title,
+		ComputedAt: stats.ComputedAt,
+		Questions:  make([]QuestionStats, 0, len(stats.Questions)),
+	}
+
+	for _, question := range stats.Questions {
+		item := QuestionStats{
+			QuestionID:          question.QuestionID,
+			Text:                question.Text,
+			Options:             make([]OptionStats, 0, len(question.Options)),
+			Answers:             question.Answers,
+			Correct:             question.Correct,
+			CorrectRate:         question.CorrectRate,
+			MedianAnswerSeconds: medianSeconds(question),
+			Reached:             question.Reached,
+			Dropped:             question.Dropped,
+			DropOffRate:         question.DropOffRate,
+		}
+		for _, option := range question.Options {
+			item.Options = append(item.Options, OptionStats(option))
+		}
+
+		resp.Questions = append(resp.Questions, item)
+	}
+
+	return resp
+}
+
+// writeCSV пишет по строке на вариант ответа, показатели вопроса повторяются в каждой строке его вариантов
+func writeCSV(w io.Writer, stats model.GameQuestionStats) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{
+		"question_id",
+		"question",
+		"answers",
+		"correct",
+		"correct_rate",
+		"median_answer_seconds",
+		"reached",
+		"dropped",
+		"drop_off_rate",
+		"option_id",
+		"option",
+		"option_is_correct",
+		"option_count",
+		"option_share",
+	}); err != nil {
+		return err
+	}
+
+	for _, question := range stats.Questions {
+		median := ""
+		if seconds := medianSeconds(question); seconds != nil {
+			median = formatFloat(*seconds)
+		}
+
+		for _, option := range question.Options {
+			if err := writer.Write([]string{
+				strconv.FormatInt(question.QuestionID, 10),
+				question.Text,
+				strconv.FormatInt(question.Answers, 10),
+				strconv.FormatInt(question.Correct, 10),
+				formatFloat(question.CorrectRate),
+				median,
+				strconv.FormatInt(question.Reached, 10),
+				strconv.FormatInt(question.Dropped, 10),
+				formatFloat(question.DropOffRate),
+				strconv.FormatInt(option.OptionID, 10),
+				option.Text,
+				strconv.FormatBool(option.IsCorrect),
+				strconv.FormatInt(option.Count, 10),
+				formatFloat(option.Share),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func medianSeconds(question model.QuestionStats) *float64 {
+	if question.MedianAnswerTime == nil {
+		return nil
+	}
+
+	seconds := question.MedianAnswerTime.Seconds()
+	return &seconds
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', 4, 64)
+}
+
+func (q FunnelQuery) toFilter(now time.Time) (model.FunnelFilter, error) {
+	filter := model.FunnelFilter{
+		To: now,
+	}
+
+	if q.GameID != "" {
+		gameID, err := uuid.Parse(q.GameID)
+		if err != nil {
+			return model.FunnelFilter{}, err
+		}
+		filter.GameID = &gameID
+	}
+
+	if q.To != "" {
+		to, err := time.Parse(time.DateOnly, q.To)
+		if err != nil {
+			return model.FunnelFilter{}, err
+		}
+		filter.To = to
+	}
+
+	filter.From = filter.To.AddDate(0, 0, -(defaultFunnelDays - 1))
+	if q.From != "" {
+		from, err := time.Parse(time.DateOnly, q.From)
+		if err != nil {
+			return model.FunnelFilter{}, err
+		}
+		filter.From = from
+	}
+
+	return filter, nil
+}
+
+func toFunnelResponse(funnel model.Funnel) FunnelResponse {
+	resp := FunnelResponse{
+		GameID: funnel.GameID,
+		From:   funnel.From.Format(time.DateOnly),
+		To:     funnel.To.Format(time.DateOnly),
+		Days:   make([]FunnelDay, 0, len(funnel.Days)),
+	}
+
+	for _, day := range funnel.Days {
+		resp.Days = append(resp.Days, FunnelDay{
+			Day:       day.Day.Format(time.DateOnly),
+			Opened:    day.Opened,
+			Answered:  day.Answered,
+			Completed: day.Completed,
+			Resets:    day.Resets,
+		})
+	}
+
+	return resp
+}
@@ -0,0 +1,132 @@
+package analytics
+
+import (
+	"easy-quizy/internal/contracts"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type Handler struct {
+	usecase contracts.AnalyticsUsecase
+}
+
+func NewHandler(usecase contracts.AnalyticsUsecase) *Handler {
+	return &Handler{
+		usecase: usecase,
+	}
+}
+
+// RegisterAdmin эндпоинты контент-команды, router должен проходить через AdminMiddleware
+func (h *Handler) RegisterAdmin(router *gin.RouterGroup) {
+	router.GET("/games/:game_id/analytics", h.getQuestionStats)
+	router.POST("/analytics/refresh", h.refresh)
+	router.GET("/funnel", h.getFunnel)
+	router.POST("/funnel/rebuild", h.rebuildFunnel)
+}
+
+// getQuestionStats отдает JSON, с ?format=csv — файл для выгрузки
+func (h *Handler) getQuestionStats(c *gin.Context) {
+	gameID, err := uuid.Parse(c.Param("game_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game_id format"})
+		return
+	}
+
+	var query StatsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid query: " + err.Error()})
+		return
+	}
+
+	stats, err := h.usecase.GetQuestionStats(c.Request.Context(), gameID)
+	if err != nil {
+		if errors.Is(err, contracts.ErrGameNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if query.Format != formatCSV {
+		c.JSON(http.StatusOK, toGameStatsResponse(stats))
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="question-stats-%s.csv"`, gameID))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(http.StatusOK)
+	if err := writeCSV(c.Writer, stats); err != nil {
+		// Заголовки уже отправлены, остается только залогировать
+		logrus.Errorf("Failed to write question stats csv: %v", err)
+	}
+}
+
+// refresh пересчитывает статистику вне расписания
+func (h *Handler) refresh(c *gin.Context) {
+	if err := h.usecase.RefreshQuestionStats(c.Request.Context()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// getFunnel ряд воронки по дням, без game_id — сумма по всем играм
+func (h *Handler) getFunnel(c *gin.Context) {
+	var query FunnelQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid query: " + err.Error()})
+		return
+	}
+
+	filter, err := query.toFilter(time.Now().UTC())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid query: " + err.Error()})
+		return
+	}
+
+	funnel, err := h.usecase.GetFunnel(c.Request.Context(), filter)
+	if err != nil {
+		if errors.Is(err, contracts.ErrGameNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, contracts.ErrInvalidFunnelRange) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, toFunnelResponse(funnel))
+}
+
+// rebuildFunnel пересобирает воронку с указанного дня, нужен для первичного заполнения истории
+func (h *Handler) rebuildFunnel(c *gin.Context) {
+	var query RebuildFunnelQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid query: " + err.Error()})
+		return
+	}
+
+	from, err := time.Parse(time.DateOnly, query.From)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid query: " + err.Error()})
+		return
+	}
+
+	if err := h.usecase.RebuildFunnel(c.Request.Context(), from); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,52 @@
+package anticheat
+
+import (
+	"easy-quizy/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PageQuery struct {
+	Limit  int64 `form:"limit"`
+	Offset int64 `form:"offset"`
+}
+
+type FlagResponse struct {
+	ID        int64     `json:"id"`
+	SessionID uuid.UUID `json:"sessionId"`
+	UserID    uuid.UUID `json:"userId"`
+	GameID    uuid.UUID `json:"gameId"`
+	Reason    string    `json:"reason"`
+	Details   string    `json:"details"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type FlagsResponse struct {
+	Items  []FlagResponse `json:"items"`
+	Total  int64          `json:"total"`
+	Limit  int64          `json:"limit"`
+	Offset int64          `json:"offset"`
+}
+
+func toFlagsResponse(flags model.SessionFlags, page model.Page) FlagsResponse {
+	items := make([]FlagResponse, 0, len(flags.Items))
+	for _, flag := range flags.Items {
+		items = append(items, FlagResponse{
+			ID:        flag.ID,
+			SessionID: flag.SessionID,
+			UserID:    flag.UserID,
+			GameID:    flag.GameID,
+			Reason:    string(flag.Reason),
+			Details:   flag.Details,
+			CreatedAt: flag.CreatedAt,
+		})
+	}
+
+	return FlagsResponse{
+		Items:  items,
+		Total:  flags.Total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}
+}
@@ -0,0 +1,41 @@
+package anticheat
+
+import (
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	usecase contracts.AntiCheatUsecase
+}
+
+func NewHandler(usecase contracts.AntiCheatUsecase) *Handler {
+	return &Handler{
+		usecase: usecase,
+	}
+}
+
+// RegisterAdmin эндпоинты модерации, router должен проходить через AdminMiddleware
+func (h *Handler) RegisterAdmin(router *gin.RouterGroup) {
+	router.GET("/sessions/flags", h.getFlags)
+}
+
+func (h *Handler) getFlags(c *gin.Context) {
+	var query PageQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid query: " + err.Error()})
+		return
+	}
+
+	page := model.Page{Limit: query.Limit, Offset: query.Offset}.Normalize()
+	flags, err := h.usecase.GetFlags(c.Request.Context(), page)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, toFlagsResponse(flags, page))
+}
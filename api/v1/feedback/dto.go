@@ -0,0 +1,143 @@
+package feedback
+
+import (
+	"easy-quizy/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RateGameRequest struct {
+	Rating int64 `json:"rating"`
+}
+
+type RatingResponse struct {
+	GameID    uuid.UUID `json:"gameId"`
+	Rating    int64     `json:"rating"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type FlagQuestionRequest struct {
+	Reason  string  `json:"reason"`
+	Comment *string `json:"comment"`
+}
+
+type FlagResponse struct {
+	GameID     uuid.UUID `json:"gameId"`
+	QuestionID int64     `json:"questionId"`
+	Reason     string    `json:"reason"`
+	Comment    *string   `json:"comment,omitempty"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+type FlaggedQuestionsQuery struct {
+	GameID string `form:"game_id"`
+	Limit  int64  `form:"limit"`
+	Offset int64  `form:"offset"`
+}
+
+type FlaggedQuestion struct {
+	GameID         uuid.UUID `json:"gameId"`
+	GameTitle      string    `json:"gameTitle"`
+	QuestionID     int64     `json:"questionId"`
+	QuestionText   string    `json:"questionText"`
+	Total          int64     `json:"total"`
+	Wrong          int64     `json:"wrong"`
+	Ambiguous      int64     `json:"ambiguous"`
+	LastFlaggedAt  time.Time `json:"lastFlaggedAt"`
+	RecentComments []string  `json:"recentComments"`
+}
+
+type FlaggedQuestionsResponse struct {
+	Items  []FlaggedQuestion `json:"items"`
+	Total  int64             `json:"total"`
+	Limit  int64             `json:"limit"`
+	Offset int64             `json:"offset"`
+}
+
+type PageQuery struct {
+	Limit  int64 `form:"limit"`
+	Offset int64 `form:"offset"`
+}
+
+type GameRatingSummary struct {
+	GameID    uuid.UUID `json:"gameId"`
+	GameTitle string    `json:"gameTitle"`
+	Average   float64   `json:"average"`
+	Count     int64     `json:"count"`
+}
+
+type RatingsResponse struct {
+	Items  []GameRatingSummary `json:"items"`
+	Total  int64               `json:"total"`
+	Limit  int64               `json:"limit"`
+	Offset int64               `json:"offset"`
+}
+
+func toRatingResponse(rating model.GameRating) RatingResponse {
+	return RatingResponse{
+		GameID:    rating.GameID,
+		Rating:    rating.Rating,
+		UpdatedAt: rating.UpdatedAt,
+	}
+}
+
+func toFlagResponse(flag model.QuestionFlag) FlagResponse {
+	return FlagResponse{
+		GameID:     flag.GameID,
+		QuestionID: flag.QuestionID,
+		Reason:     string(flag.Reason),
+		Comment:    flag.Comment,
+		UpdatedAt:  flag.UpdatedAt,
+	}
+}
+
+func toFlaggedQuestionsResponse(flags model.FlaggedQuestions, page model.Page) FlaggedQuestionsResponse {
+	resp := FlaggedQuestionsResponse{
+		Items:  make([]FlaggedQuestion, 0, len(flags.Items)),
+		Total:  flags.Total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}
+
+	for _, item := range flags.Items {
+		comments := item.RecentComments
+		if comments == nil {
+			comments = []string{}
+		}
+
+		resp.Items = append(resp.Items, FlaggedQuestion{
+			GameID:         item.GameID,
+			GameTitle:      item.GameTitle,
+			QuestionID:     item.QuestionID,
+			QuestionText:   item.QuestionText,
+			Total:          item.Total,
+			Wrong:          item.Wrong,
+			Ambiguous:      item.Ambiguous,
+			LastFlaggedAt:  item.LastFlaggedAt,
+			RecentComments: comments,
+		})
+	}
+
+	return resp
+}
+
+func toRatingsResponse(ratings model.GameRatingSummaries, page model.Page) RatingsResponse {
+	resp := RatingsResponse{
+		Items:  make([]GameRatingSummary, 0, len(ratings.Items)),
+		Total:  ratings.Total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}
+
+	for _, item := range ratings.Items {
+		resp.Items = append(resp.Items, GameRatingSummary{
+			GameID:    item.GameID,
+			GameTitle: item.GameTitle,
+			Average:   item.Average,
+			Count:     item.Count,
+		})
+	}
+
+	return resp
+}
@@ -0,0 +1,168 @@
+package feedback
+
+import (
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/middleware"
+	"easy-quizy/internal/model"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type Handler struct {
+	usecase contracts.FeedbackUsecase
+}
+
+func NewHandler(usecase contracts.FeedbackUsecase) *Handler {
+	return &Handler{
+		usecase: usecase,
+	}
+}
+
+// Register эндпоинты игрока, router должен проходить через AuthMiddleware
+func (h *Handler) Register(router *gin.RouterGroup) {
+	gameGroup := router.Group("/api/game")
+	gameGroup.POST("/:game_id/rating", h.rateGame)
+	gameGroup.POST("/:game_id/questions/:question_id/flag", h.flagQuestion)
+}
+
+// RegisterAdmin эндпоинты редакторов, router должен проходить через AdminMiddleware
+func (h *Handler) RegisterAdmin(router *gin.RouterGroup) {
+	router.GET("/flags", h.getFlaggedQuestions)
+	router.GET("/ratings", h.getRatings)
+}
+
+func (h *Handler) rateGame(c *gin.Context) {
+	gameID, err := uuid.Parse(c.Param("game_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game_id format"})
+		return
+	}
+
+	playerID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user ID from context"})
+		return
+	}
+
+	var req RateGameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	rating, err := h.usecase.RateGame(c.Request.Context(), contracts.RateGameIn{
+		GameID:   gameID,
+		PlayerID: playerID,
+		Rating:   req.Rating,
+	})
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toRatingResponse(rating))
+}
+
+func (h *Handler) flagQuestion(c *gin.Context) {
+	gameID, err := uuid.Parse(c.Param("game_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game_id format"})
+		return
+	}
+
+	questionID, err := strconv.ParseInt(c.Param("question_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid question_id format"})
+		return
+	}
+
+	playerID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user ID from context"})
+		return
+	}
+
+	var req FlagQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	flag, err := h.usecase.FlagQuestion(c.Request.Context(), contracts.FlagQuestionIn{
+		GameID:     gameID,
+		PlayerID:   playerID,
+		QuestionID: questionID,
+		Reason:     model.FlagReason(req.Reason),
+		Comment:    req.Comment,
+	})
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toFlagResponse(flag))
+}
+
+func (h *Handler) getFlaggedQuestions(c *gin.Context) {
+	var query FlaggedQuestionsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid query: " + err.Error()})
+		return
+	}
+
+	filter := model.FlaggedQuestionsFilter{
+		Page: model.Page{Limit: query.Limit, Offset: query.Offset}.Normalize(),
+	}
+	if query.GameID != "" {
+		gameID, err := uuid.Parse(query.GameID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game_id format"})
+			return
+		}
+		filter.GameID = &gameID
+	}
+
+	flags, err := h.usecase.GetFlaggedQuestions(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, toFlaggedQuestionsResponse(flags, filter.Page))
+}
+
+func (h *Handler) getRatings(c *gin.Context) {
+	var query PageQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid query: " + err.Error()})
+		return
+	}
+
+	page := model.Page{Limit: query.Limit, Offset: query.Offset}.Normalize()
+	ratings, err := h.usecase.GetRatings(c.Request.Context(), page)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, toRatingsResponse(ratings, page))
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, contracts.ErrGameNotFound), errors.Is(err, contracts.ErrQuestionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, contracts.ErrInvalidRating),
+		errors.Is(err, contracts.ErrInvalidFlagReason),
+		errors.Is(err, contracts.ErrFlagCommentTooLong):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, contracts.ErrGameNotCompleted):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -1,7 +1,9 @@
 package game
 
 import (
+	"easy-quizy/internal/contracts"
 	"easy-quizy/internal/model"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -16,6 +18,8 @@ type Question struct {
 	Text          string         `json:"text"`
 	ImageID       *string        `json:"image_id,omitempty"`
 	AnswerOptions []AnswerOption `json:"answer_options"`
+	// Hint подсказка, открытая игроком
+	Hint *string `json:"hint,omitempty"`
 }
 
 type Result struct {
@@ -28,16 +32,28 @@ type Progress struct {
 	Total    int64 `json:"total"`
 }
 
+// Achievement только что полученное достижение, клиент показывает его игроку
+type Achievement struct {
+	Code        string    `json:"code"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	UnlockedAt  time.Time `json:"unlockedAt"`
+}
+
 type GameInfo struct {
 	ID    uuid.UUID `json:"id"`
 	Title string    `json:"title"`
 }
 
 type StateResponse struct {
-	Question *Question `json:"question,omitempty"`
-	Result   *Result   `json:"result,omitempty"`
-	Progress Progress  `json:"progress"`
-	GameInfo GameInfo  `json:"gameInfo"`
+	Question        *Question         `json:"question,omitempty"`
+	Result          *Result           `json:"result,omitempty"`
+	Progress        Progress          `json:"progress"`
+	GameInfo        GameInfo          `json:"gameInfo"`
+	Recommendations []RecommendedGame `json:"recommendations,omitempty"`
+	Achievements    []Achievement     `json:"achievements,omitempty"`
+	// Lifelines оставшиеся подсказки по типам, нет поля — в игре подсказок нет
+	Lifelines map[string]int64 `json:"lifelines,omitempty"`
 }
 
 type AcceptAnswerRequest struct {
@@ -46,8 +62,93 @@ type AcceptAnswerRequest struct {
 }
 
 type AcceptAnswerResponse struct {
-	IsCorrect   bool    `json:"isCorrect"`
-	Explanation *string `json:"explanation,omitempty"`
+	IsCorrect    bool          `json:"isCorrect"`
+	Explanation  *string       `json:"explanation,omitempty"`
+	XP           int64         `json:"xp,omitempty"`
+	Achievements []Achievement `json:"achievements,omitempty"`
+}
+
+type UseLifelineRequest struct {
+	QuestionID int64  `json:"questionId"`
+	Type       string `json:"type" binding:"required"`
+}
+
+type UseLifelineResponse struct {
+	Type         string           `json:"type"`
+	Question     Question         `json:"question"`
+	Lifelines    map[string]int64 `json:"lifelines"`
+	XP           int64            `json:"xp,omitempty"`
+	Achievements []Achievement    `json:"achievements,omitempty"`
+}
+
+type ReviewAnswerOption struct {
+	ID        int64  `json:"id"`
+	Answer    string `json:"answer"`
+	IsCorrect bool   `json:"isCorrect"`
+}
+
+type ReviewQuestion struct {
+	ID             int64                `json:"id"`
+	Text           string               `json:"text"`
+	ImageID        *string              `json:"image_id,omitempty"`
+	AnswerOptions  []ReviewAnswerOption `json:"answer_options"`
+	ChosenAnswerID *int64               `json:"chosenAnswerId,omitempty"`
+	IsCorrect      bool                 `json:"isCorrect"`
+	Skipped        bool                 `json:"skipped,omitempty"`
+	Explanation    *string              `json:"explanation,omitempty"`
+}
+
+type ReviewResponse struct {
+	GameInfo  GameInfo         `json:"gameInfo"`
+	Result    Result           `json:"result"`
+	Questions []ReviewQuestion `json:"questions"`
+}
+
+// CatalogQuery параметры GET /api/games, теги передаются повторяющимся параметром tag
+type CatalogQuery struct {
+	Query      string   `form:"q"`
+	Category   string   `form:"category"`
+	Difficulty string   `form:"difficulty"`
+	Language   string   `form:"language"`
+	Type       string   `form:"type"`
+	Tags       []string `form:"tag"`
+	Limit      int64    `form:"limit"`
+	Offset     int64    `form:"offset"`
+}
+
+type CatalogItem struct {
+	ID             uuid.UUID `json:"id"`
+	Type           string    `json:"type"`
+	Title          string    `json:"title"`
+	Description    *string   `json:"description,omitempty"`
+	Category       *string   `json:"category,omitempty"`
+	Tags           []string  `json:"tags"`
+	Difficulty     *string   `json:"difficulty,omitempty"`
+	Language       *string   `json:"language,omitempty"`
+	Cover          *string   `json:"cover,omitempty"`
+	QuestionsCount int64     `json:"questionsCount"`
+	Played         bool      `json:"played"`
+	Completed      bool      `json:"completed"`
+}
+
+type CatalogResponse struct {
+	Items  []CatalogItem `json:"items"`
+	Total  int64         `json:"total"`
+	Limit  int64         `json:"limit"`
+	Offset int64         `json:"offset"`
+}
+
+type RecommendedGame struct {
+	CatalogItem
+	Score float64 `json:"score"`
+}
+
+type RecommendationsResponse struct {
+	Items []RecommendedGame `json:"items"`
+}
+
+type RecommendationsQuery struct {
+	Limit int64 `form:"limit"`
 }
 
 type GetDailyGameResponse struct {
@@ -67,18 +168,8 @@ func toStateResponse(state model.State) StateResponse {
 	}
 
 	if state.Question != nil {
-		resp.Question = &Question{
-			ID:            state.Question.ID,
-			Text:          state.Question.Text,
-			ImageID:       state.Question.ImageID,
-			AnswerOptions: make([]AnswerOption, len(state.Question.AnswerOptions)),
-		}
-		for i, opt := range state.Question.AnswerOptions {
-			resp.Question.AnswerOptions[i] = AnswerOption{
-				ID:     opt.ID,
-				Answer: opt.Answer,
-			}
-		}
+		question := toQuestion(*state.Question, state.Hint)
+		resp.Question = &question
 	}
 
 	if state.Result != nil {
@@ -88,5 +179,181 @@ func toStateResponse(state model.State) StateResponse {
 		}
 	}
 
+	for _, item := range state.Recommendations {
+		resp.Recommendations = append(resp.Recommendations, toRecommendedGame(item))
+	}
+
+	resp.Achievements = toAchievements(state.Achievements)
+	if len(state.Lifelines) > 0 {
+		resp.Lifelines = toLifelines(state.Lifelines)
+	}
+
+	return resp
+}
+
+func toQuestion(question model.Question, hint *string) Question {
+	result := Question{
+		ID:            question.ID,
+		Text:          question.Text,
+		ImageID:       question.ImageID,
+		AnswerOptions: make([]AnswerOption, len(question.AnswerOptions)),
+		Hint:          hint,
+	}
+	for i, opt := range question.AnswerOptions {
+		result.AnswerOptions[i] = AnswerOption{
+			ID:     opt.ID,
+			Answer: opt.Answer,
+		}
+	}
+
+	return result
+}
+
+func toLifelines(remaining map[model.LifelineType]int64) map[string]int64 {
+	result := make(map[string]int64, len(remaining))
+	for lifelineType, count := range remaining {
+		result[string(lifelineType)] = count
+	}
+
+	return result
+}
+
+func toUseLifelineResponse(out contracts.UseLifelineOut) UseLifelineResponse {
+	return UseLifelineResponse{
+		Type:         string(out.Type),
+		Question:     toQuestion(out.Question, out.Hint),
+		Lifelines:    toLifelines(out.Remaining),
+		XP:           out.XP,
+		Achievements: toAchievements(out.Achievements),
+	}
+}
+
+func toAchievements(achievements []model.Achievement) []Achievement {
+	var result []Achievement
+	for _, item := range achievements {
+		result = append(result, Achievement{
+			Code:        string(item.Code),
+			Title:       item.Title,
+			Description: item.Description,
+			UnlockedAt:  item.UnlockedAt,
+		})
+	}
+
+	return result
+}
+
+func toReviewResponse(review model.Review) ReviewResponse {
+	resp := ReviewResponse{
+		GameInfo: GameInfo{
+			ID:    review.GameInfo.ID,
+			Title: review.GameInfo.Title,
+		},
+		Result: Result{
+			TotalScore: review.Result.TotalScore,
+			ResultText: review.Result.ResultText,
+		},
+		Questions: make([]ReviewQuestion, 0, len(review.Items)),
+	}
+
+	for _, item := range review.Items {
+		question := ReviewQuestion{
+			ID:             item.Question.ID,
+			Text:           item.Question.Text,
+			ImageID:        item.Question.ImageID,
+			AnswerOptions:  make([]ReviewAnswerOption, len(item.Question.AnswerOptions)),
+			ChosenAnswerID: item.ChosenAnswerID,
+			IsCorrect:      item.IsCorrect,
+			Skipped:        item.Skipped,
+			Explanation:    item.Question.Explanation,
+		}
+		for i, opt := range item.Question.AnswerOptions {
+			question.AnswerOptions[i] = ReviewAnswerOption{
+				ID:        opt.ID,
+				Answer:    opt.Answer,
+				IsCorrect: opt.IsCorrect,
+			}
+		}
+
+		resp.Questions = append(resp.Questions, question)
+	}
+
+	return resp
+}
+
+func (q CatalogQuery) toFilter() model.CatalogFilter {
+	filter := model.CatalogFilter{
+		Query: q.Query,
+		Tags:  q.Tags,
+		Page:  model.Page{Limit: q.Limit, Offset: q.Offset}.Normalize(),
+	}
+	if q.Category != "" {
+		filter.Category = &q.Category
+	}
+	if q.Difficulty != "" {
+		filter.Difficulty = &q.Difficulty
+	}
+	if q.Language != "" {
+		filter.Language = &q.Language
+	}
+	if q.Type != "" {
+		gameType := model.GameType(q.Type)
+		filter.Type = &gameType
+	}
+
+	return filter
+}
+
+func toCatalogResponse(catalog model.Catalog, page model.Page) CatalogResponse {
+	resp := CatalogResponse{
+		Items:  make([]CatalogItem, 0, len(catalog.Items)),
+		Total:  catalog.Total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}
+
+	for _, item := range catalog.Items {
+		resp.Items = append(resp.Items, toCatalogItem(item))
+	}
+
+	return resp
+}
+
+func toCatalogItem(item model.CatalogItem) CatalogItem {
+	tags := item.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return CatalogItem{
+		ID:             item.ID,
+		Type:           string(item.Type),
+		Title:          item.Title,
+		Description:    item.Description,
+		Category:       item.Category,
+		Tags:           tags,
+		Difficulty:     item.Difficulty,
+		Language:       item.Language,
+		Cover:          item.Cover,
+		QuestionsCount: item.QuestionsCount,
+		Played:         item.Played,
+		Completed:      item.Completed,
+	}
+}
+
+func toRecommendedGame(item model.Recommendation) RecommendedGame {
+	return RecommendedGame{
+		CatalogItem: toCatalogItem(item.CatalogItem),
+		Score:       item.Score,
+	}
+}
+
+func toRecommendationsResponse(items []model.Recommendation) RecommendationsResponse {
+	resp := RecommendationsResponse{
+		Items: make([]RecommendedGame, 0, len(items)),
+	}
+	for _, item := range items {
+		resp.Items = append(resp.Items, toRecommendedGame(item))
+	}
+
 	return resp
 }
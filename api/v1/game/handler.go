@@ -3,6 +3,7 @@ package game
 import (
 	"easy-quizy/internal/contracts"
 	"easy-quizy/internal/middleware"
+	"easy-quizy/internal/model"
 	"errors"
 	"fmt"
 	"net/http"
@@ -11,6 +12,9 @@ import (
 	"github.com/google/uuid"
 )
 
+// IdempotencyKeyHeader необязательный ключ повторной отправки ответа
+const IdempotencyKeyHeader = "Idempotency-Key"
+
 type Handler struct {
 	usecase contracts.GameUsecase
 }
@@ -26,8 +30,13 @@ func (h *Handler) Register(router *gin.RouterGroup) {
 	gameGroup.GET("/:game_id", h.getCurrentState)
 	gameGroup.POST("/:game_id/accept-answer", h.acceptAnswer)
 	gameGroup.OPTIONS("/:game_id/accept-answer", h.acceptAnswer)
-	gameGroup.GET("/:game_id/reset", h.resetGame)
+	gameGroup.POST("/:game_id/lifeline", h.useLifeline)
+	gameGroup.POST("/:game_id/reset", h.resetGame)
+	gameGroup.GET("/:game_id/review", h.getReview)
 	gameGroup.GET("/daily", h.getDailyGame)
+
+	router.GET("/api/games", h.getCatalog)
+	router.GET("/api/games/recommended", h.getRecommendations)
 }
 
 func (h *Handler) getCurrentState(c *gin.Context) {
@@ -79,25 +88,82 @@ func (h *Handler) acceptAnswer(c *gin.Context) {
 		return
 	}
 
+	var idempotencyKey *string
+	if key := c.GetHeader(IdempotencyKeyHeader); key != "" {
+		idempotencyKey = &key
+	}
+
 	out, err := h.usecase.AcceptAnswer(c.Request.Context(), &contracts.AcceptAnswersIn{
-		GameID:     gameID,
-		PlayerID:   playerID,
-		QuestionID: req.QuestionID,
-		Answer:     req.AnswerID,
+		GameID:         gameID,
+		PlayerID:       playerID,
+		QuestionID:     req.QuestionID,
+		Answer:         req.AnswerID,
+		IdempotencyKey: idempotencyKey,
 	})
 	if err != nil {
 		if errors.Is(err, contracts.ErrGameNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		}
+		if errors.Is(err, contracts.ErrIdempotencyKeyReused) || errors.Is(err, contracts.ErrQuestionSkipped) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, AcceptAnswerResponse{
-		IsCorrect:   out.IsCorrect,
-		Explanation: out.Explanation,
+		IsCorrect:    out.IsCorrect,
+		Explanation:  out.Explanation,
+		XP:           out.XP,
+		Achievements: toAchievements(out.Achievements),
+	})
+}
+
+func (h *Handler) useLifeline(c *gin.Context) {
+	gameID, err := uuid.Parse(c.Param("game_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game_id format"})
+		return
+	}
+
+	playerID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user ID from context"})
+		return
+	}
+
+	var req UseLifelineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body: " + err.Error()})
+		return
+	}
+
+	out, err := h.usecase.UseLifeline(c.Request.Context(), contracts.UseLifelineIn{
+		GameID:     gameID,
+		PlayerID:   playerID,
+		QuestionID: req.QuestionID,
+		Type:       model.LifelineType(req.Type),
 	})
+	if err != nil {
+		switch {
+		case errors.Is(err, contracts.ErrInvalidLifeline):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, contracts.ErrGameNotFound), errors.Is(err, contracts.ErrQuestionNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, contracts.ErrLifelineNotAvailable),
+			errors.Is(err, contracts.ErrLifelineLimitReached),
+			errors.Is(err, contracts.ErrQuestionAlreadyAnswered),
+			errors.Is(err, contracts.ErrNotActiveSessionNotFound):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, toUseLifelineResponse(out))
 }
 
 func (h *Handler) resetGame(c *gin.Context) {
@@ -116,15 +182,98 @@ func (h *Handler) resetGame(c *gin.Context) {
 
 	err = h.usecase.Reset(c.Request.Context(), gameID, playerID)
 	if err != nil {
-		if errors.Is(err, contracts.ErrGameNotFound) {
+		switch {
+		case errors.Is(err, contracts.ErrGameNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, contracts.ErrReplayNotAllowed):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "code": "replay_not_allowed"})
+		case errors.Is(err, contracts.ErrReplayAttemptsExhausted):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "code": "replay_attempts_exhausted"})
+		case errors.Is(err, contracts.ErrReplayCooldown):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "code": "replay_cooldown"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func (h *Handler) getReview(c *gin.Context) {
+	gameIDStr := c.Param("game_id")
+	gameID, err := uuid.Parse(gameIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game_id format"})
+		return
+	}
+
+	playerID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user ID from context"})
+		return
+	}
+
+	review, err := h.usecase.GetReview(c.Request.Context(), gameID, playerID)
+	if err != nil {
+		if errors.Is(err, contracts.ErrGameNotFound) || errors.Is(err, contracts.ErrSessionNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		}
+		if errors.Is(err, contracts.ErrSessionNotFinished) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"success": true})
+	c.JSON(http.StatusOK, toReviewResponse(review))
+}
+
+func (h *Handler) getCatalog(c *gin.Context) {
+	playerID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user ID from context"})
+		return
+	}
+
+	var query CatalogQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid query: " + err.Error()})
+		return
+	}
+
+	filter := query.toFilter()
+	catalog, err := h.usecase.GetCatalog(c.Request.Context(), playerID, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, toCatalogResponse(catalog, filter.Page))
+}
+
+func (h *Handler) getRecommendations(c *gin.Context) {
+	playerID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user ID from context"})
+		return
+	}
+
+	var query RecommendationsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid query: " + err.Error()})
+		return
+	}
+
+	items, err := h.usecase.GetRecommendations(c.Request.Context(), playerID, query.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, toRecommendationsResponse(items))
 }
 
 func (h *Handler) getDailyGame(c *gin.Context) {
@@ -0,0 +1,34 @@
+package health
+
+import "easy-quizy/internal/contracts"
+
+const (
+	statusOK    = "ok"
+	statusError = "error"
+)
+
+type ReadinessResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+func toReadinessResponse(readiness contracts.Readiness) ReadinessResponse {
+	resp := ReadinessResponse{
+		Status: statusOK,
+		Checks: make(map[string]string, len(readiness.Checks)),
+	}
+	if !readiness.Ready {
+		resp.Status = statusError
+	}
+
+	for _, check := range readiness.Checks {
+		if check.Err != nil {
+			resp.Checks[check.Name] = check.Err.Error()
+			continue
+		}
+
+		resp.Checks[check.Name] = statusOK
+	}
+
+	return resp
+}
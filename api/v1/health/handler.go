@@ -0,0 +1,39 @@
+package health
+
+import (
+	"easy-quizy/internal/contracts"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	usecase contracts.HealthUsecase
+}
+
+func NewHandler(usecase contracts.HealthUsecase) *Handler {
+	return &Handler{
+		usecase: usecase,
+	}
+}
+
+// Register регистрирует эндпоинты проб; группа не должна содержать AuthMiddleware
+func (h *Handler) Register(router *gin.RouterGroup) {
+	router.GET("/healthz", h.liveness)
+	router.GET("/readyz", h.readiness)
+}
+
+func (h *Handler) liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": statusOK})
+}
+
+func (h *Handler) readiness(c *gin.Context) {
+	readiness := h.usecase.Ready(c.Request.Context())
+
+	status := http.StatusOK
+	if !readiness.Ready {
+		status = http.StatusServiceUnavailable
+	}
+
+	c.JSON(status, toReadinessResponse(readiness))
+}
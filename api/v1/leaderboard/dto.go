@@ -0,0 +1,48 @@
+package leaderboard
+
+import (
+	"easy-quizy/internal/model"
+
+	"github.com/google/uuid"
+)
+
+type PageQuery struct {
+	Limit  int64 `form:"limit"`
+	Offset int64 `form:"offset"`
+}
+
+type Entry struct {
+	Rank   int64     `json:"rank"`
+	UserID uuid.UUID `json:"userId"`
+	Level  int64     `json:"level"`
+	XP     int64     `json:"xp"`
+}
+
+type LeaderboardResponse struct {
+	ChatID int64   `json:"chatId"`
+	Items  []Entry `json:"items"`
+	Total  int64   `json:"total"`
+	Limit  int64   `json:"limit"`
+	Offset int64   `json:"offset"`
+}
+
+func toLeaderboardResponse(chatID int64, leaderboard model.Leaderboard, page model.Page) LeaderboardResponse {
+	resp := LeaderboardResponse{
+		ChatID: chatID,
+		Items:  make([]Entry, 0, len(leaderboard.Items)),
+		Total:  leaderboard.Total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}
+
+	for _, item := range leaderboard.Items {
+		resp.Items = append(resp.Items, Entry{
+			Rank:   item.Rank,
+			UserID: item.UserID,
+			Level:  item.Level,
+			XP:     item.XP,
+		})
+	}
+
+	return resp
+}
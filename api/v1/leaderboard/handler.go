@@ -0,0 +1,61 @@
+package leaderboard
+
+import (
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/middleware"
+	"easy-quizy/internal/model"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	progression contracts.ProgressionUsecase
+}
+
+func NewHandler(progression contracts.ProgressionUsecase) *Handler {
+	return &Handler{
+		progression: progression,
+	}
+}
+
+// Register эндпоинты игрока, router должен проходить через AuthMiddleware
+func (h *Handler) Register(router *gin.RouterGroup) {
+	router.GET("/api/chats/:chat_id/leaderboard", h.getChatLeaderboard)
+}
+
+// getChatLeaderboard участники чата по опыту, доступен только участникам этого чата
+func (h *Handler) getChatLeaderboard(c *gin.Context) {
+	chatID, err := strconv.ParseInt(c.Param("chat_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid chat_id format"})
+		return
+	}
+
+	playerID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user ID from context"})
+		return
+	}
+
+	var query PageQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid query: " + err.Error()})
+		return
+	}
+
+	page := model.Page{Limit: query.Limit, Offset: query.Offset}.Normalize()
+	leaderboard, err := h.progression.GetChatLeaderboard(c.Request.Context(), playerID, chatID, page)
+	if err != nil {
+		if errors.Is(err, contracts.ErrNotChatMember) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, toLeaderboardResponse(chatID, leaderboard, page))
+}
@@ -0,0 +1,157 @@
+package profile
+
+import (
+	"easy-quizy/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Source struct {
+	ID     string `json:"id"`
+	Source string `json:"source"`
+}
+
+type Stats struct {
+	GamesPlayed            int64    `json:"gamesPlayed"`
+	GamesCompleted         int64    `json:"gamesCompleted"`
+	FinishedAttempts       int64    `json:"finishedAttempts"`
+	TotalAnswers           int64    `json:"totalAnswers"`
+	CorrectAnswers         int64    `json:"correctAnswers"`
+	BestScore              *int64   `json:"bestScore,omitempty"`
+	AverageDurationSeconds *float64 `json:"averageDurationSeconds,omitempty"`
+}
+
+type Level struct {
+	Level       int64 `json:"level"`
+	XP          int64 `json:"xp"`
+	LevelXP     int64 `json:"levelXp"`
+	NextLevelXP int64 `json:"nextLevelXp"`
+}
+
+type MeResponse struct {
+	ID      uuid.UUID `json:"id"`
+	Sources []Source  `json:"sources"`
+	Stats   Stats     `json:"stats"`
+	Level   Level     `json:"level"`
+}
+
+type HistoryItem struct {
+	GameID     uuid.UUID `json:"gameId"`
+	Title      string    `json:"title"`
+	Score      int64     `json:"score"`
+	ResultText string    `json:"resultText"`
+	FinishedAt time.Time `json:"finishedAt"`
+	Attempts   int64     `json:"attempts"`
+}
+
+type HistoryResponse struct {
+	Items  []HistoryItem `json:"items"`
+	Total  int64         `json:"total"`
+	Limit  int64         `json:"limit"`
+	Offset int64         `json:"offset"`
+}
+
+type Achievement struct {
+	Code        string     `json:"code"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Unlocked    bool       `json:"unlocked"`
+	UnlockedAt  *time.Time `json:"unlockedAt,omitempty"`
+}
+
+type AchievementsResponse struct {
+	Items    []Achievement `json:"items"`
+	Unlocked int64         `json:"unlocked"`
+	Total    int64         `json:"total"`
+}
+
+type PageQuery struct {
+	Limit  int64 `form:"limit"`
+	Offset int64 `form:"offset"`
+}
+
+func toMeResponse(profile model.Profile, stats model.PlayerStats, level model.PlayerLevel) MeResponse {
+	resp := MeResponse{
+		ID:      profile.ID,
+		Sources: make([]Source, 0, len(profile.Sources)),
+		Stats: Stats{
+			GamesPlayed:      stats.GamesPlayed,
+			GamesCompleted:   stats.GamesCompleted,
+			FinishedAttempts: stats.FinishedAttempts,
+			TotalAnswers:     stats.TotalAnswers,
+			CorrectAnswers:   stats.CorrectAnswers,
+			BestScore:        stats.BestScore,
+		},
+		Level: Level{
+			Level:       level.Level,
+			XP:          level.XP,
+			LevelXP:     level.LevelXP,
+			NextLevelXP: level.NextLevelXP,
+		},
+	}
+
+	for _, source := range profile.Sources {
+		resp.Sources = append(resp.Sources, Source{
+			ID:     source.IDext,
+			Source: source.Source,
+		})
+	}
+
+	if stats.AverageDuration != nil {
+		seconds := stats.AverageDuration.Seconds()
+		resp.Stats.AverageDurationSeconds = &seconds
+	}
+
+	return resp
+}
+
+func toHistoryResponse(history model.GameHistory, page model.Page) HistoryResponse {
+	resp := HistoryResponse{
+		Items:  make([]HistoryItem, 0, len(history.Items)),
+		Total:  history.Total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}
+
+	for _, item := range history.Items {
+		resultText := ""
+		if item.ResultText != nil {
+			resultText = *item.ResultText
+		}
+
+		resp.Items = append(resp.Items, HistoryItem{
+			GameID:     item.GameID,
+			Title:      item.Title,
+			Score:      item.Score,
+			ResultText: resultText,
+			FinishedAt: item.FinishedAt,
+			Attempts:   item.Attempts,
+		})
+	}
+
+	return resp
+}
+
+func toAchievementsResponse(achievements []model.AchievementStatus) AchievementsResponse {
+	resp := AchievementsResponse{
+		Items: make([]Achievement, 0, len(achievements)),
+		Total: int64(len(achievements)),
+	}
+
+	for _, item := range achievements {
+		if item.UnlockedAt != nil {
+			resp.Unlocked++
+		}
+
+		resp.Items = append(resp.Items, Achievement{
+			Code:        string(item.Code),
+			Title:       item.Title,
+			Description: item.Description,
+			Unlocked:    item.UnlockedAt != nil,
+			UnlockedAt:  item.UnlockedAt,
+		})
+	}
+
+	return resp
+}
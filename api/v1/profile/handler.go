@@ -0,0 +1,110 @@
+package profile
+
+import (
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/middleware"
+	"easy-quizy/internal/model"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	users        contracts.UserUsecase
+	games        contracts.GameUsecase
+	achievements contracts.AchievementUsecase
+	progression  contracts.ProgressionUsecase
+}
+
+func NewHandler(
+	users contracts.UserUsecase,
+	games contracts.GameUsecase,
+	achievements contracts.AchievementUsecase,
+	progression contracts.ProgressionUsecase,
+) *Handler {
+	return &Handler{
+		users:        users,
+		games:        games,
+		achievements: achievements,
+		progression:  progression,
+	}
+}
+
+func (h *Handler) Register(router *gin.RouterGroup) {
+	meGroup := router.Group("/api/me")
+	meGroup.GET("", h.getMe)
+	meGroup.GET("/games", h.getGames)
+	meGroup.GET("/achievements", h.getAchievements)
+}
+
+func (h *Handler) getMe(c *gin.Context) {
+	playerID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user ID from context"})
+		return
+	}
+
+	profile, err := h.users.GetProfile(c.Request.Context(), playerID)
+	if err != nil {
+		if errors.Is(err, contracts.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	stats, err := h.games.GetPlayerStats(c.Request.Context(), playerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	level, err := h.progression.GetLevel(c.Request.Context(), playerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, toMeResponse(profile, stats, level))
+}
+
+func (h *Handler) getGames(c *gin.Context) {
+	playerID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user ID from context"})
+		return
+	}
+
+	var query PageQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid query: " + err.Error()})
+		return
+	}
+
+	page := model.Page{Limit: query.Limit, Offset: query.Offset}.Normalize()
+	history, err := h.games.GetHistory(c.Request.Context(), playerID, page)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, toHistoryResponse(history, page))
+}
+
+func (h *Handler) getAchievements(c *gin.Context) {
+	playerID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user ID from context"})
+		return
+	}
+
+	achievements, err := h.achievements.GetAchievements(c.Request.Context(), playerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, toAchievementsResponse(achievements))
+}
@@ -0,0 +1,121 @@
+package webhook
+
+import (
+	"easy-quizy/internal/model"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateWebhookRequest struct {
+	URL        string   `json:"url"`
+	Secret     *string  `json:"secret"`
+	EventTypes []string `json:"eventTypes"`
+}
+
+type WebhookResponse struct {
+	ID         uuid.UUID `json:"id"`
+	GameID     uuid.UUID `json:"gameId"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"eventTypes"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// CreatedWebhookResponse секрет отдается только при создании
+type CreatedWebhookResponse struct {
+	WebhookResponse
+	Secret string `json:"secret"`
+}
+
+type WebhooksResponse struct {
+	Items []WebhookResponse `json:"items"`
+}
+
+type PageQuery struct {
+	Limit  int64 `form:"limit"`
+	Offset int64 `form:"offset"`
+}
+
+type DeliveryResponse struct {
+	ID             int64           `json:"id"`
+	WebhookID      uuid.UUID       `json:"webhookId"`
+	EventID        int64           `json:"eventId"`
+	EventType      string          `json:"eventType"`
+	Body           json.RawMessage `json:"body"`
+	Status         string          `json:"status"`
+	Attempts       int64           `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"nextAttemptAt,omitempty"`
+	LastStatusCode *int64          `json:"lastStatusCode,omitempty"`
+	LastError      *string         `json:"lastError,omitempty"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	DeliveredAt    *time.Time      `json:"deliveredAt,omitempty"`
+}
+
+type DeliveriesResponse struct {
+	Items  []DeliveryResponse `json:"items"`
+	Total  int64              `json:"total"`
+	Limit  int64              `json:"limit"`
+	Offset int64              `json:"offset"`
+}
+
+func toWebhookResponse(webhook model.Webhook) WebhookResponse {
+	eventTypes := make([]string, 0, len(webhook.EventTypes))
+	for _, eventType := range webhook.EventTypes {
+		eventTypes = append(eventTypes, string(eventType))
+	}
+
+	return WebhookResponse{
+		ID:         webhook.ID,
+		GameID:     webhook.GameID,
+		URL:        webhook.URL,
+		EventTypes: eventTypes,
+		Active:     webhook.Active,
+		CreatedAt:  webhook.CreatedAt,
+	}
+}
+
+func toWebhooksResponse(webhooks []model.Webhook) WebhooksResponse {
+	items := make([]WebhookResponse, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		items = append(items, toWebhookResponse(webhook))
+	}
+
+	return WebhooksResponse{Items: items}
+}
+
+func toDeliveryResponse(delivery model.WebhookDelivery) DeliveryResponse {
+	result := DeliveryResponse{
+		ID:             delivery.ID,
+		WebhookID:      delivery.WebhookID,
+		EventID:        delivery.EventID,
+		EventType:      string(delivery.EventType),
+		Body:           delivery.Body,
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		CreatedAt:      delivery.CreatedAt,
+		DeliveredAt:    delivery.DeliveredAt,
+	}
+	if delivery.Status == model.DeliveryStatusPending {
+		result.NextAttemptAt = &delivery.NextAttemptAt
+	}
+
+	return result
+}
+
+func toDeliveriesResponse(deliveries model.WebhookDeliveries, page model.Page) DeliveriesResponse {
+	items := make([]DeliveryResponse, 0, len(deliveries.Items))
+	for _, delivery := range deliveries.Items {
+		items = append(items, toDeliveryResponse(delivery))
+	}
+
+	return DeliveriesResponse{
+		Items:  items,
+		Total:  deliveries.Total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}
+}
@@ -0,0 +1,151 @@
+package webhook
+
+import (
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type Handler struct {
+	usecase contracts.WebhookUsecase
+}
+
+func NewHandler(usecase contracts.WebhookUsecase) *Handler {
+	return &Handler{
+		usecase: usecase,
+	}
+}
+
+// RegisterAdmin эндпоинты управления вебхуками, router должен проходить через AdminMiddleware
+func (h *Handler) RegisterAdmin(router *gin.RouterGroup) {
+	router.POST("/games/:game_id/webhooks", h.createWebhook)
+	router.GET("/games/:game_id/webhooks", h.getWebhooks)
+	router.DELETE("/webhooks/:webhook_id", h.disableWebhook)
+	router.GET("/webhooks/:webhook_id/deliveries", h.getDeliveries)
+	router.POST("/webhooks/deliveries/:delivery_id/replay", h.replayDelivery)
+}
+
+func (h *Handler) createWebhook(c *gin.Context) {
+	gameID, err := uuid.Parse(c.Param("game_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game_id format"})
+		return
+	}
+
+	var req CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	eventTypes := make([]model.EventType, 0, len(req.EventTypes))
+	for _, eventType := range req.EventTypes {
+		eventTypes = append(eventTypes, model.EventType(eventType))
+	}
+
+	webhook, err := h.usecase.CreateWebhook(c.Request.Context(), contracts.CreateWebhookIn{
+		GameID:     gameID,
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: eventTypes,
+	})
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, CreatedWebhookResponse{
+		WebhookResponse: toWebhookResponse(webhook),
+		Secret:          webhook.Secret,
+	})
+}
+
+func (h *Handler) getWebhooks(c *gin.Context) {
+	gameID, err := uuid.Parse(c.Param("game_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid game_id format"})
+		return
+	}
+
+	webhooks, err := h.usecase.GetWebhooks(c.Request.Context(), gameID)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toWebhooksResponse(webhooks))
+}
+
+// disableWebhook отключает вебхук, журнал доставок остается доступен
+func (h *Handler) disableWebhook(c *gin.Context) {
+	webhookID, err := uuid.Parse(c.Param("webhook_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook_id format"})
+		return
+	}
+
+	if err := h.usecase.DisableWebhook(c.Request.Context(), webhookID); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) getDeliveries(c *gin.Context) {
+	webhookID, err := uuid.Parse(c.Param("webhook_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook_id format"})
+		return
+	}
+
+	var query PageQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid query: " + err.Error()})
+		return
+	}
+
+	page := model.Page{Limit: query.Limit, Offset: query.Offset}.Normalize()
+	deliveries, err := h.usecase.GetDeliveries(c.Request.Context(), webhookID, page)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toDeliveriesResponse(deliveries, page))
+}
+
+// replayDelivery повторно отправляет доставку с тем же телом, в том числе уже успешную
+func (h *Handler) replayDelivery(c *gin.Context) {
+	deliveryID, err := strconv.ParseInt(c.Param("delivery_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid delivery_id format"})
+		return
+	}
+
+	delivery, err := h.usecase.ReplayDelivery(c.Request.Context(), deliveryID)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, toDeliveryResponse(delivery))
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, contracts.ErrGameNotFound),
+		errors.Is(err, contracts.ErrWebhookNotFound),
+		errors.Is(err, contracts.ErrWebhookDeliveryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, contracts.ErrInvalidWebhookURL), errors.Is(err, contracts.ErrInvalidWebhookEvent):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"easy-quizy/internal/model"
+	accountUC "easy-quizy/internal/usecase/account"
+	antiCheatUC "easy-quizy/internal/usecase/anticheat"
+	outboxUC "easy-quizy/internal/usecase/outbox"
+	progressionUC "easy-quizy/internal/usecase/progression"
+	userUC "easy-quizy/internal/usecase/user"
+	webhookUC "easy-quizy/internal/usecase/webhook"
+	"easy-quizy/pkg/variables"
+)
+
+type (
+	config struct {
+		server    serverConfig
+		db        dbConfig
+		cors      corsConfig
+		admin     adminConfig
+		analytics analyticsConfig
+		outbox    outboxConfig
+		webhook   webhookConfig
+		xp        progressionUC.Config
+		antiCheat antiCheatUC.Config
+		rateLimit rateLimitConfig
+		users     userUC.Config
+		accounts  accountUC.Config
+	}
+
+	serverConfig struct {
+		port            int64
+		readTimeout     time.Duration
+		writeTimeout    time.Duration
+		idleTimeout     time.Duration
+		shutdownTimeout time.Duration
+		drainDelay      time.Duration
+		// trustedProxies пусто — адрес клиента берется из соединения, заголовки прокси игнорируются
+		trustedProxies []string
+	}
+
+	dbConfig struct {
+		source          string
+		maxOpenConns    int64
+		maxIdleConns    int64
+		connMaxLifetime time.Duration
+		connectTimeout  time.Duration
+		migrateOnStart  bool
+	}
+
+	corsConfig struct {
+		allowedOrigins []string
+	}
+
+	adminConfig struct {
+		token string
+	}
+
+	outboxConfig struct {
+		pollInterval time.Duration
+		maxAttempts  int64
+		retention    time.Duration
+	}
+
+	webhookConfig struct {
+		pollInterval time.Duration
+		maxAttempts  int64
+		timeout      time.Duration
+	}
+
+	rateLimitConfig struct {
+		store string
+		// ip лимиты по адресу, общие для всех маршрутов
+		ip model.RateLimitRules
+		// player лимиты по игроку с правилами маршрутов
+		player model.RateLimitRules
+	}
+
+	analyticsConfig struct {
+		interval       time.Duration
+		abandonAfter   time.Duration
+		funnelLookback time.Duration
+	}
+)
+
+func newConfig(vars variables.Repository) config {
+	return config{
+		server: serverConfig{
+			port:            vars.GetInt64(variables.ServerPort),
+			readTimeout:     vars.GetDuration(variables.ServerReadTimeout),
+			writeTimeout:    vars.GetDuration(variables.ServerWriteTimeout),
+			idleTimeout:     vars.GetDuration(variables.ServerIdleTimeout),
+			shutdownTimeout: vars.GetDuration(variables.ServerShutdownTimeout),
+			drainDelay:      vars.GetDuration(variables.ServerDrainDelay),
+			trustedProxies:  vars.GetStrings(variables.ServerTrustedProxies),
+		},
+		db: dbConfig{
+			source: fmt.Sprintf(
+				"user=%s password=%s host=%s port=%d dbname=%s sslmode=%s",
+				vars.GetString(variables.DBUser),
+				vars.GetString(variables.DBPassword),
+				vars.GetString(variables.DBHost),
+				vars.GetInt64(variables.DBPort),
+				vars.GetString(variables.DBName),
+				vars.GetString(variables.DBSSL),
+			),
+			maxOpenConns:    vars.GetInt64(variables.DBMaxOpenConns),
+			maxIdleConns:    vars.GetInt64(variables.DBMaxIdleConns),
+			connMaxLifetime: vars.GetDuration(variables.DBConnMaxLifetime),
+			connectTimeout:  vars.GetDuration(variables.DBConnectTimeout),
+			migrateOnStart:  vars.GetBool(variables.MigrateOnStart),
+		},
+		cors: corsConfig{
+			allowedOrigins: vars.GetStrings(variables.CORSAllowedOrigins),
+		},
+		admin: adminConfig{
+			token: vars.GetString(variables.AdminToken),
+		},
+		analytics: analyticsConfig{
+			interval:       vars.GetDuration(variables.AnalyticsInterval),
+			abandonAfter:   vars.GetDuration(variables.AnalyticsAbandonAfter),
+			funnelLookback: vars.GetDuration(variables.AnalyticsFunnelLookback),
+		},
+		outbox: outboxConfig{
+			pollInterval: vars.GetDuration(variables.OutboxPollInterval),
+			maxAttempts:  vars.GetInt64(variables.OutboxMaxAttempts),
+			retention:    vars.GetDuration(variables.OutboxRetention),
+		},
+		webhook: webhookConfig{
+			pollInterval: vars.GetDuration(variables.WebhookPollInterval),
+			maxAttempts:  vars.GetInt64(variables.WebhookMaxAttempts),
+			timeout:      vars.GetDuration(variables.WebhookTimeout),
+		},
+		xp: progressionUC.Config{
+			CorrectAnswerXP: vars.GetInt64(variables.XPCorrectAnswer),
+			CompletionXP:    vars.GetInt64(variables.XPCompletion),
+			DailyMultiplier: vars.GetInt64(variables.XPDailyMultiplier),
+			StreakXP:        vars.GetInt64(variables.XPStreakBonus),
+			StreakCap:       vars.GetInt64(variables.XPStreakCap),
+			LevelCurve:      model.LevelCurve(vars.GetInt64s(variables.XPLevelCurve)),
+		},
+		antiCheat: antiCheatUC.Config{
+			RateWindow:          vars.GetDuration(variables.AntiCheatRateWindow),
+			MaxAnswersPerWindow: vars.GetInt64(variables.AntiCheatMaxAnswers),
+			MinAnswerTime:       vars.GetDuration(variables.AntiCheatMinAnswerTime),
+			ResetWindow:         vars.GetDuration(variables.AntiCheatResetWindow),
+			MaxResets:           vars.GetInt64(variables.AntiCheatMaxResets),
+			SharedWindow:        vars.GetDuration(variables.AntiCheatSharedWindow),
+			MinSharedAnswers:    vars.GetInt64(variables.AntiCheatMinSharedAnswers),
+		},
+		rateLimit: rateLimitConfig{
+			store: vars.GetString(variables.RateLimitStore),
+			ip: model.RateLimitRules{
+				Default: rateLimit(vars.GetRateLimit(variables.RateLimitIP)),
+			},
+			player: model.RateLimitRules{
+				Default: rateLimit(vars.GetRateLimit(variables.RateLimitPlayer)),
+				Routes:  rateLimitRoutes(vars.GetRateLimitRoutes(variables.RateLimitRoutes)),
+			},
+		},
+		users: userUC.Config{
+			CacheSize: vars.GetInt64(variables.UserCacheSize),
+			CacheTTL:  vars.GetDuration(variables.UserCacheTTL),
+		},
+		accounts: accountUC.Config{
+			LinkCodeTTL: vars.GetDuration(variables.LinkCodeTTL),
+		},
+	}
+}
+
+func (c config) outboxDispatcherConfig() outboxUC.Config {
+	return outboxUC.Config{
+		MaxAttempts: c.outbox.maxAttempts,
+		Retention:   c.outbox.retention,
+	}
+}
+
+func (c config) webhookUsecaseConfig() webhookUC.Config {
+	return webhookUC.Config{
+		MaxAttempts: c.webhook.maxAttempts,
+		Timeout:     c.webhook.timeout,
+	}
+}
+
+func rateLimit(v variables.RateLimitValue) model.RateLimit {
+	return model.RateLimit{Requests: v.Requests, Period: v.Period}
+}
+
+func rateLimitRoutes(values map[string]variables.RateLimitValue) map[string]model.RateLimit {
+	result := make(map[string]model.RateLimit, len(values))
+	for route, v := range values {
+		result[route] = rateLimit(v)
+	}
+
+	return result
+}
+
+// printConfig выводит эффективную конфигурацию, секреты замаскированы
+func printConfig(w io.Writer) {
+	for _, item := range variables.Effective() {
+		_, _ = fmt.Fprintf(w, "%s=%s\n", item.Left, item.Right)
+	}
+}
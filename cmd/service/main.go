@@ -2,29 +2,44 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"net/http"
 	"os"
-	"strconv"
+	"os/signal"
+	"syscall"
 	"time"
 
-	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
-	txmanager "github.com/avito-tech/go-transaction-manager/trm/v2/manager"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-	"github.com/jmoiron/sqlx"
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
 	"github.com/sirupsen/logrus"
 
+	accountAPI "easy-quizy/api/v1/account"
+	analyticsAPI "easy-quizy/api/v1/analytics"
+	antiCheatAPI "easy-quizy/api/v1/anticheat"
+	feedbackAPI "easy-quizy/api/v1/feedback"
 	gameAPI "easy-quizy/api/v1/game"
+	healthAPI "easy-quizy/api/v1/health"
+	leaderboardAPI "easy-quizy/api/v1/leaderboard"
+	profileAPI "easy-quizy/api/v1/profile"
+	webhookAPI "easy-quizy/api/v1/webhook"
 	"easy-quizy/internal/middleware"
-	gameRepo "easy-quizy/internal/repositories/game"
-	userRepo "easy-quizy/internal/repositories/user"
-	gameUC "easy-quizy/internal/usecase/game"
-	userUC "easy-quizy/internal/usecase/user"
+	"easy-quizy/internal/model"
+	schemaRepo "easy-quizy/internal/repositories/schema"
+	"easy-quizy/migrations"
+	"easy-quizy/pkg/variables"
+	"easy-quizy/pkg/worker"
 )
 
 func main() {
+	printConfigFlag := flag.Bool("print-config", false, "print effective configuration with secrets redacted and exit")
+	storageFlag := flag.String("storage", storagePostgres, "storage backend: postgres or memory")
+	quizesDirFlag := flag.String("quizes", "quizes", "directory with quiz json files loaded in memory storage")
+	flag.Parse()
+
 	if _, err := os.Stat(".env"); err == nil {
 		// path/to/whatever exists
 		err := godotenv.Load(".env")
@@ -33,46 +48,116 @@ func main() {
 		}
 	}
 
-	ctx := context.Background()
+	var excludedGroups []string
+	if *storageFlag == storageMemory {
+		excludedGroups = append(excludedGroups, variables.GroupDatabase)
+	}
 
-	// Get SERVER_PORT from env, fallback to 8080
-	port := os.Getenv("SERVER_PORT")
-	if _, err := strconv.Atoi(port); err != nil {
-		port = "8080"
+	configuration, err := variables.NewConfiguration(excludedGroups...)
+	if *printConfigFlag {
+		printConfig(os.Stdout)
+		if err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		return
+	}
+	if err != nil {
+		logrus.Fatal(err)
 	}
 
-	source := fmt.Sprintf(
-		"user=%s password=%s host=%s port=%s dbname=%s sslmode=%s",
-		loadEnvValue("DB_USER"),
-		loadEnvValue("DB_PASSWORD"),
-		loadEnvValue("DB_HOST"),
-		loadEnvValue("DB_PORT"),
-		loadEnvValue("DB_NAME"),
-		loadEnvValue("DB_SSL"),
-	)
+	cfg := newConfig(configuration.Repository.MustGet())
 
-	db, err := sqlx.ConnectContext(
-		ctx,
-		"postgres",
-		source,
-	)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if flag.Arg(0) == "migrate" {
+		if *storageFlag != storagePostgres {
+			logrus.Fatalf("migrate requires --storage=%s", storagePostgres)
+		}
+
+		db, err := connectDB(ctx, cfg.db)
+		if err != nil {
+			logrus.Fatal(err)
+		}
+		defer db.Close()
+
+		if err := runMigrate(ctx, schemaRepo.NewMigrator(db, migrations.FS), flag.Args()[1:], os.Stdout); err != nil {
+			logrus.Fatal(err)
+		}
+
+		return
+	}
+
+	repairOrphans := flag.Arg(0) == "repair-orphans"
+	if repairOrphans && *storageFlag != storagePostgres {
+		logrus.Fatalf("repair-orphans requires --storage=%s", storagePostgres)
+	}
+
+	var deps *dependencies
+	switch *storageFlag {
+	case storagePostgres:
+		deps, err = newPostgresDependencies(ctx, cfg)
+	case storageMemory:
+		deps, err = newMemoryDependencies(cfg, *quizesDirFlag)
+	default:
+		err = fmt.Errorf("unknown storage '%s'", *storageFlag)
+	}
 	if err != nil {
-		panic(err)
+		logrus.Fatal(err)
+	}
+
+	if repairOrphans {
+		err := runRepairOrphans(ctx, deps.accounts, flag.Args()[1:], os.Stdout)
+		_ = deps.close()
+		if err != nil {
+			logrus.Fatal(err)
+		}
+
+		return
 	}
 
-	trm := txmanager.Must(trmsqlx.NewDefaultFactory(db))
-	trmsqlxGetter := trmsqlx.DefaultCtxGetter
+	// Outbox subscribers are registered before the dispatcher starts
+	deps.events.Subscribe(model.EventGameCompleted, "webhooks", deps.webhooks.HandleEvent)
+	deps.events.Subscribe(model.EventDailyCompleted, "webhooks", deps.webhooks.HandleEvent)
+	deps.events.Subscribe(model.EventAnswerAccepted, "anticheat", deps.antiCheat.HandleEvent)
+	deps.events.Subscribe(model.EventGameCompleted, "anticheat", deps.antiCheat.HandleEvent)
 
-	gameRepository := gameRepo.NewRepository(db, trmsqlxGetter)
-	userRepository := userRepo.NewRepository(db, trmsqlxGetter)
-	gameUsecase := gameUC.NewUsecase(gameRepository, trm)
-	userUsecase := userUC.NewUsecase(userRepository, trm)
+	// Background workers share the signal context and are stopped after the server drains
+	workers := worker.NewGroup(ctx)
+
+	workers.Go("question-stats", worker.Every(cfg.analytics.interval, deps.analytics.RefreshQuestionStats, func(err error) {
+		logrus.Errorf("Failed to refresh question stats: %v", err)
+	}))
+	workers.Go("outbox-dispatcher", worker.Every(cfg.outbox.pollInterval, deps.events.DispatchPending, func(err error) {
+		logrus.Errorf("Failed to dispatch outbox events: %v", err)
+	}))
+	workers.Go("outbox-cleanup", worker.Every(time.Hour, deps.events.Cleanup, func(err error) {
+		logrus.Errorf("Failed to clean up outbox: %v", err)
+	}))
+	workers.Go("webhook-delivery", worker.Every(cfg.webhook.pollInterval, deps.webhooks.DeliverPending, func(err error) {
+		logrus.Errorf("Failed to deliver webhooks: %v", err)
+	}))
+	workers.Go("rate-limit-cleanup", worker.Every(time.Hour, deps.rateLimit.Cleanup, func(err error) {
+		logrus.Errorf("Failed to clean up rate limit buckets: %v", err)
+	}))
+	workers.Go("funnel-rollup", worker.Every(cfg.analytics.interval, deps.analytics.RefreshFunnel, func(err error) {
+		logrus.Errorf("Failed to refresh funnel rollup: %v", err)
+	}))
+	if deps.userCacheSync != nil {
+		workers.Go("user-cache-sync", deps.userCacheSync)
+	}
 
 	r := gin.Default()
+	// ClientIP feeds the per-IP rate limit, so forwarded headers are honoured only from known proxies
+	if err := r.SetTrustedProxies(cfg.server.trustedProxies); err != nil {
+		logrus.Fatalf("Invalid trusted proxies: %v", err)
+	}
 
 	// Configure CORS for development and production
 	corsConfig := cors.Config{
-		AllowMethods: []string{"GET", "POST", "OPTIONS"},
+		AllowMethods: []string{"GET", "POST", "DELETE", "OPTIONS"},
 		AllowHeaders: []string{
 			"Origin",
 			"Content-Type",
@@ -82,14 +167,17 @@ func main() {
 			"X-Source",
 			"X-Chat-ID",
 			"X-Chat-Type",
+			gameAPI.IdempotencyKeyHeader,
 		},
-		ExposeHeaders:    []string{"Content-Length"},
+		ExposeHeaders:    []string{"Content-Length", "Retry-After"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}
 
 	// Set allowed origins based on environment
-	if gin.Mode() == gin.DebugMode {
+	if len(cfg.cors.allowedOrigins) > 0 {
+		corsConfig.AllowOrigins = cfg.cors.allowedOrigins
+	} else if gin.Mode() == gin.DebugMode {
 		// Development: Allow Vite dev server and SSR server
 		corsConfig.AllowOrigins = []string{
 			"http://localhost:5173",           // Vite dev server
@@ -105,23 +193,88 @@ func main() {
 
 	r.Use(cors.New(corsConfig))
 
-	// Apply auth middleware to all routes
-	r.Use(middleware.AuthMiddleware(userUsecase))
+	// Probes are registered outside of the auth group
+	healthHandler := healthAPI.NewHandler(deps.health)
+	healthHandler.Register(&r.RouterGroup)
+
+	// Apply auth middleware to all API routes. The IP limit runs first so that floods never reach
+	// the user lookup; the per-player limit needs the user id resolved by auth.
+	api := r.Group("",
+		middleware.RateLimitMiddleware(deps.rateLimit, "ip", cfg.rateLimit.ip, middleware.ClientIPKey),
+		middleware.AuthMiddleware(deps.users),
+		middleware.RateLimitMiddleware(deps.rateLimit, "player", cfg.rateLimit.player, middleware.PlayerKey),
+	)
+
+	gameHandler := gameAPI.NewHandler(deps.games)
+	gameHandler.Register(api)
+
+	profileHandler := profileAPI.NewHandler(deps.users, deps.games, deps.achievements, deps.progression)
+	profileHandler.Register(api)
+
+	accountHandler := accountAPI.NewHandler(deps.accounts)
+	accountHandler.Register(api)
+
+	leaderboardHandler := leaderboardAPI.NewHandler(deps.progression)
+	leaderboardHandler.Register(api)
+
+	feedbackHandler := feedbackAPI.NewHandler(deps.feedback)
+	feedbackHandler.Register(api)
+
+	// Admin endpoints use a bearer token instead of player headers
+	admin := r.Group("/api/admin", middleware.AdminMiddleware(cfg.admin.token))
+	feedbackHandler.RegisterAdmin(admin)
 
-	gameHandler := gameAPI.NewHandler(gameUsecase)
-	gameHandler.Register(&r.RouterGroup)
+	analyticsHandler := analyticsAPI.NewHandler(deps.analytics)
+	analyticsHandler.RegisterAdmin(admin)
 
-	runErr := r.Run(":" + port)
-	if runErr != nil {
-		logrus.Fatalf("Failed to start server: %v", runErr)
+	webhookHandler := webhookAPI.NewHandler(deps.webhooks)
+	webhookHandler.RegisterAdmin(admin)
+
+	antiCheatHandler := antiCheatAPI.NewHandler(deps.antiCheat)
+	antiCheatHandler.RegisterAdmin(admin)
+
+	server := &http.Server{
+		Addr:         fmt.Sprintf(":%d", cfg.server.port),
+		Handler:      r,
+		ReadTimeout:  cfg.server.readTimeout,
+		WriteTimeout: cfg.server.writeTimeout,
+		IdleTimeout:  cfg.server.idleTimeout,
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+		close(serverErr)
+	}()
+
+	select {
+	case <-ctx.Done():
+		logrus.Info("Shutdown signal received, draining in-flight requests")
+	case err := <-serverErr:
+		logrus.Fatalf("Failed to start server: %v", err)
+	}
+
+	// /readyz starts failing first; keep serving until the load balancer stops routing here
+	deps.health.Shutdown()
+	if cfg.server.drainDelay > 0 {
+		logrus.Infof("Readiness withdrawn, waiting %s before closing listeners", cfg.server.drainDelay)
+		time.Sleep(cfg.server.drainDelay)
 	}
-}
 
-func loadEnvValue(key string) string {
-	value, exists := os.LookupEnv(key)
-	if !exists {
-		panic("env value doesn't exists")
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), cfg.server.shutdownTimeout)
+	defer cancelShutdown()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logrus.Errorf("Failed to shutdown server gracefully: %v", err)
+	}
+	if err := workers.Stop(shutdownCtx); err != nil {
+		logrus.Errorf("Failed to stop workers: %v", err)
+	}
+	if err := deps.close(); err != nil {
+		logrus.Errorf("Failed to close database: %v", err)
 	}
 
-	return value
+	logrus.Info("Server stopped")
 }
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"easy-quizy/internal/contracts"
+	schemaRepo "easy-quizy/internal/repositories/schema"
+)
+
+const usageMigrate = "usage: service migrate up | down [steps] | status"
+
+// runMigrate выполняет подкоманду `migrate up|down [N]|status`
+func runMigrate(ctx context.Context, migrator *schemaRepo.Migrator, args []string, w io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(usageMigrate)
+	}
+
+	switch args[0] {
+	case "up":
+		if err := migrator.Up(ctx); err != nil {
+			return err
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			parsed, err := strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid steps '%s': %w", args[1], err)
+			}
+			steps = parsed
+		}
+
+		if err := migrator.Down(ctx, steps); err != nil {
+			return err
+		}
+	case "status":
+	default:
+		return errors.New(usageMigrate)
+	}
+
+	return printSchemaStatus(ctx, migrator, w)
+}
+
+func printSchemaStatus(ctx context.Context, migrator *schemaRepo.Migrator, w io.Writer) error {
+	status, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range status.Migrations {
+		mark := " "
+		if item.Applied {
+			mark = "x"
+		}
+		_, _ = fmt.Fprintf(w, "[%s] %06d %s\n", mark, item.Version, item.Name)
+	}
+	_, _ = fmt.Fprintf(w, "version: %d, latest: %d, dirty: %t\n", status.Version, status.Latest, status.Dirty)
+
+	return nil
+}
+
+// ensureSchema не дает сервису стартовать на отстающей схеме, при migrateOnStart сначала применяет миграции
+func ensureSchema(ctx context.Context, migrator *schemaRepo.Migrator, migrateOnStart bool) error {
+	if migrateOnStart {
+		if err := migrator.Up(ctx); err != nil {
+			return fmt.Errorf("failed to apply migrations: %w", err)
+		}
+	}
+
+	status, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+	if status.Dirty {
+		return fmt.Errorf("%w: %d, fix it and run `service migrate up`", contracts.ErrSchemaVersionIsDirty, status.Version)
+	}
+	if status.IsBehind() {
+		return fmt.Errorf(
+			"%w: %d < %d, run `service migrate up` or set MIGRATE_ON_START=true",
+			contracts.ErrSchemaIsBehind,
+			status.Version,
+			status.Latest,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"easy-quizy/internal/contracts"
+)
+
+const usageRepairOrphans = "usage: service repair-orphans plan | apply"
+
+// runRepairOrphans выполняет подкоманду `repair-orphans plan|apply`: plan только показывает найденных владельцев
+func runRepairOrphans(ctx context.Context, accounts contracts.AccountUsecase, args []string, w io.Writer) error {
+	if len(args) != 1 || (args[0] != "plan" && args[0] != "apply") {
+		return errors.New(usageRepairOrphans)
+	}
+
+	apply := args[0] == "apply"
+	repairs, err := accounts.RepairOrphans(ctx, apply)
+	if err != nil {
+		return err
+	}
+
+	var resolved, moved int64
+	for _, repair := range repairs {
+		if repair.UserID == nil {
+			_, _ = fmt.Fprintf(w, "[ ] %s first seen %s: %d candidates\n",
+				repair.PlayerID, repair.FirstSeenAt.UTC().Format("2006-01-02 15:04:05"), repair.Candidates)
+			continue
+		}
+
+		resolved++
+		moved += repair.Sessions
+		_, _ = fmt.Fprintf(w, "[x] %s first seen %s -> %s, sessions moved: %d\n",
+			repair.PlayerID, repair.FirstSeenAt.UTC().Format("2006-01-02 15:04:05"), *repair.UserID, repair.Sessions)
+	}
+	_, _ = fmt.Fprintf(w, "orphans: %d, resolved: %d, sessions moved: %d, applied: %t\n", len(repairs), resolved, moved, apply)
+
+	return nil
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	txmanager "github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"github.com/jmoiron/sqlx"
+
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	accountRepo "easy-quizy/internal/repositories/account"
+	achievementRepo "easy-quizy/internal/repositories/achievement"
+	antiCheatRepo "easy-quizy/internal/repositories/anticheat"
+	feedbackRepo "easy-quizy/internal/repositories/feedback"
+	gameRepo "easy-quizy/internal/repositories/game"
+	outboxRepo "easy-quizy/internal/repositories/outbox"
+	progressionRepo "easy-quizy/internal/repositories/progression"
+	rateLimitRepo "easy-quizy/internal/repositories/ratelimit"
+	schemaRepo "easy-quizy/internal/repositories/schema"
+	userRepo "easy-quizy/internal/repositories/user"
+	webhookRepo "easy-quizy/internal/repositories/webhook"
+	accountUC "easy-quizy/internal/usecase/account"
+	achievementUC "easy-quizy/internal/usecase/achievement"
+	analyticsUC "easy-quizy/internal/usecase/analytics"
+	antiCheatUC "easy-quizy/internal/usecase/anticheat"
+	feedbackUC "easy-quizy/internal/usecase/feedback"
+	gameUC "easy-quizy/internal/usecase/game"
+	healthUC "easy-quizy/internal/usecase/health"
+	outboxUC "easy-quizy/internal/usecase/outbox"
+	progressionUC "easy-quizy/internal/usecase/progression"
+	rateLimitUC "easy-quizy/internal/usecase/ratelimit"
+	userUC "easy-quizy/internal/usecase/user"
+	webhookUC "easy-quizy/internal/usecase/webhook"
+	"easy-quizy/migrations"
+	"easy-quizy/pkg/transaction"
+	"easy-quizy/pkg/worker"
+)
+
+const (
+	storagePostgres = "postgres"
+	storageMemory   = "memory"
+)
+
+type (
+	dependencies struct {
+		games        contracts.GameUsecase
+		achievements contracts.AchievementUsecase
+		progression  contracts.ProgressionUsecase
+		feedback     contracts.FeedbackUsecase
+		analytics    contracts.AnalyticsUsecase
+		events       contracts.EventDispatcher
+		webhooks     contracts.WebhookUsecase
+		antiCheat    contracts.AntiCheatUsecase
+		rateLimit    contracts.RateLimitUsecase
+		users        contracts.UserUsecase
+		accounts     contracts.AccountUsecase
+		health       contracts.HealthUsecase
+		// userCacheSync чистит кэш пользователей, когда источники переносит другая реплика; без базы не нужен
+		userCacheSync worker.Func
+		close         func() error
+	}
+)
+
+func connectDB(ctx context.Context, cfg dbConfig) (*sqlx.DB, error) {
+	connectCtx, cancel := context.WithTimeout(ctx, cfg.connectTimeout)
+	defer cancel()
+
+	db, err := sqlx.ConnectContext(
+		connectCtx,
+		"postgres",
+		cfg.source,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	db.SetMaxOpenConns(int(cfg.maxOpenConns))
+	db.SetMaxIdleConns(int(cfg.maxIdleConns))
+	db.SetConnMaxLifetime(cfg.connMaxLifetime)
+
+	return db, nil
+}
+
+func newPostgresDependencies(ctx context.Context, cfg config) (*dependencies, error) {
+	db, err := connectDB(ctx, cfg.db)
+	if err != nil {
+		return nil, err
+	}
+
+	migrator := schemaRepo.NewMigrator(db, migrations.FS)
+	if err := ensureSchema(ctx, migrator, cfg.db.migrateOnStart); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	latestSchemaVersion, err := migrator.Latest()
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	trm := txmanager.Must(trmsqlx.NewDefaultFactory(db))
+	trmsqlxGetter := trmsqlx.DefaultCtxGetter
+
+	gameRepository := gameRepo.NewRepository(db, trmsqlxGetter)
+	userRepository := userRepo.NewRepository(db, trmsqlxGetter)
+	outboxRepository := outboxRepo.NewRepository(db, trmsqlxGetter)
+	antiCheatRepository := antiCheatRepo.NewRepository(db, trmsqlxGetter)
+	achievementRepository := achievementRepo.NewRepository(db, trmsqlxGetter)
+	progressionRepository := progressionRepo.NewRepository(db, trmsqlxGetter)
+	achievementUsecase := achievementUC.NewUsecase(achievementRepository, gameRepository, userRepository, trm)
+	progressionUsecase := progressionUC.NewUsecase(progressionRepository, gameRepository, userRepository, antiCheatRepository, trm, cfg.xp)
+	gameUsecase := gameUC.NewUsecase(gameRepository, userRepository, outboxRepository, achievementUsecase, progressionUsecase, trm)
+	userUsecase := userUC.NewUsecase(userRepository, trm, cfg.users)
+	userSourceListener := userRepo.NewSourceListener(cfg.db.source)
+	accountUsecase := accountUC.NewUsecase(
+		accountRepo.NewRepository(db, trmsqlxGetter),
+		userRepository,
+		userUsecase,
+		gameRepository,
+		progressionRepository,
+		achievementRepository,
+		antiCheatRepository,
+		trm,
+		cfg.accounts,
+	)
+
+	// Бакеты в Postgres общие для всех реплик, в памяти — у каждой реплики свои
+	var rateLimitUsecase contracts.RateLimitUsecase
+	switch cfg.rateLimit.store {
+	case storagePostgres:
+		rateLimitUsecase = rateLimitUC.NewUsecase(rateLimitRepo.NewRepository(db, trmsqlxGetter))
+	case storageMemory:
+		rateLimitUsecase = rateLimitUC.NewUsecase(rateLimitRepo.NewMemoryRepository())
+	default:
+		_ = db.Close()
+		return nil, fmt.Errorf("unknown rate limit store '%s'", cfg.rateLimit.store)
+	}
+
+	return &dependencies{
+		games:        gameUsecase,
+		achievements: achievementUsecase,
+		progression:  progressionUsecase,
+		feedback:     feedbackUC.NewUsecase(feedbackRepo.NewRepository(db, trmsqlxGetter), gameRepository, trm),
+		analytics:    analyticsUC.NewUsecase(gameRepository, trm, cfg.analytics.abandonAfter, cfg.analytics.funnelLookback),
+		events:       outboxUC.NewDispatcher(outboxRepository, trm, cfg.outboxDispatcherConfig()),
+		webhooks:     webhookUC.NewUsecase(webhookRepo.NewRepository(db, trmsqlxGetter), gameRepository, trm, cfg.webhookUsecaseConfig()),
+		antiCheat:    antiCheatUC.NewUsecase(antiCheatRepository, gameRepository, userRepository, trm, cfg.antiCheat),
+		rateLimit:    rateLimitUsecase,
+		users:        userUsecase,
+		accounts:     accountUsecase,
+		health:       healthUC.NewUsecase(schemaRepo.NewRepository(db), gameUsecase, latestSchemaVersion),
+		userCacheSync: func(ctx context.Context) error {
+			return userSourceListener.Listen(ctx, userUsecase.Forget, userUsecase.ForgetAll)
+		},
+		close: db.Close,
+	}, nil
+}
+
+// newMemoryDependencies демо-режим без базы: квизы читаются из quizesDir, состояние живет до перезапуска
+func newMemoryDependencies(cfg config, quizesDir string) (*dependencies, error) {
+	games, err := gameRepo.LoadGamesFromDir(quizesDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(games) == 0 {
+		return nil, fmt.Errorf("no quizes found in %s", quizesDir)
+	}
+
+	gameRepository := gameRepo.NewMemoryRepository()
+	var hasDaily bool
+	for _, game := range games {
+		gameRepository.AddGame(game)
+		if game.Type == model.GameTypeDaily {
+			gameRepository.AddDailyGame(game.ID)
+			hasDaily = true
+		}
+	}
+	// Без ежедневных квизов ежедневным считается первый загруженный
+	if !hasDaily {
+		gameRepository.AddDailyGame(games[0].ID)
+	}
+
+	trm := transaction.NewNoopManager()
+	userRepository := userRepo.NewMemoryRepository()
+	outboxRepository := outboxRepo.NewMemoryRepository()
+	antiCheatRepository := antiCheatRepo.NewMemoryRepository()
+	achievementRepository := achievementRepo.NewMemoryRepository()
+	progressionRepository := progressionRepo.NewMemoryRepository()
+	achievementUsecase := achievementUC.NewUsecase(achievementRepository, gameRepository, userRepository, trm)
+	progressionUsecase := progressionUC.NewUsecase(progressionRepository, gameRepository, userRepository, antiCheatRepository, trm, cfg.xp)
+	gameUsecase := gameUC.NewUsecase(gameRepository, userRepository, outboxRepository, achievementUsecase, progressionUsecase, trm)
+	userUsecase := userUC.NewUsecase(userRepository, trm, cfg.users)
+	accountUsecase := accountUC.NewUsecase(
+		accountRepo.NewMemoryRepository(),
+		userRepository,
+		userUsecase,
+		gameRepository,
+		progressionRepository,
+		achievementRepository,
+		antiCheatRepository,
+		trm,
+		cfg.accounts,
+	)
+
+	// Без базы бакеты можно держать только в памяти
+	if cfg.rateLimit.store != storageMemory {
+		return nil, fmt.Errorf("rate limit store '%s' requires --storage=%s", cfg.rateLimit.store, storagePostgres)
+	}
+
+	return &dependencies{
+		games:        gameUsecase,
+		achievements: achievementUsecase,
+		progression:  progressionUsecase,
+		feedback:     feedbackUC.NewUsecase(feedbackRepo.NewMemoryRepository(), gameRepository, trm),
+		analytics:    analyticsUC.NewUsecase(gameRepository, trm, cfg.analytics.abandonAfter, cfg.analytics.funnelLookback),
+		events:       outboxUC.NewDispatcher(outboxRepository, trm, cfg.outboxDispatcherConfig()),
+		webhooks:     webhookUC.NewUsecase(webhookRepo.NewMemoryRepository(), gameRepository, trm, cfg.webhookUsecaseConfig()),
+		antiCheat:    antiCheatUC.NewUsecase(antiCheatRepository, gameRepository, userRepository, trm, cfg.antiCheat),
+		rateLimit:    rateLimitUC.NewUsecase(rateLimitRepo.NewMemoryRepository()),
+		users:        userUsecase,
+		accounts:     accountUsecase,
+		health:       healthUC.NewUsecase(schemaRepo.NewMemoryRepository(0), gameUsecase, 0),
+		close:        func() error { return nil },
+	}, nil
+}
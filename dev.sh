@@ -229,7 +229,7 @@ start_backend() {
     fi
     
     # Start Go server in background
-    go run cmd/service/main.go &
+    go run ./cmd/service &
     BACKEND_PID=$!
     
     # Wait a moment and check if the process is still running
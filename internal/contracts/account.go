@@ -0,0 +1,30 @@
+package contracts
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrLinkCodeNotFound          = errors.New("link code is invalid, expired or already used")
+	ErrAccountsAlreadyLinked     = errors.New("accounts are already linked")
+	ErrUserSourceNotFound        = errors.New("user source not found")
+	ErrCannotUnlinkCurrentSource = errors.New("can't unlink the source of the current request")
+	ErrUserMergeNotFound         = errors.New("user merge not found")
+)
+
+type (
+	AccountUsecase interface {
+		// CreateLinkCode выдает одноразовый код, которым другой источник привязывается к пользователю
+		CreateLinkCode(ctx context.Context, userID uuid.UUID) (model.LinkCode, error)
+		// Link сливает пользователя userID в владельца кода и возвращает итогового пользователя
+		Link(ctx context.Context, userID uuid.UUID, code string) (model.User, error)
+		// Unlink отделяет источник target от пользователя в нового пользователя, данные остаются у userID
+		Unlink(ctx context.Context, userID uuid.UUID, current model.UserSource, target model.UserSource) error
+		// RepairOrphans находит попытки игроков без источника и, если apply, переносит их однозначно найденному владельцу
+		RepairOrphans(ctx context.Context, apply bool) ([]model.OrphanRepair, error)
+	}
+)
@@ -0,0 +1,21 @@
+package contracts
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// AchievementUsecase правила достижений и выданные игрокам награды
+	AchievementUsecase interface {
+		// GetAchievements все достижения с отметкой, какие игрок уже получил
+		GetAchievements(ctx context.Context, userID uuid.UUID) ([]model.AchievementStatus, error)
+		// Evaluate проверяет правила после события в игре и возвращает только что полученные достижения,
+		// вызывается в транзакции, записавшей ответ или завершившей попытку
+		Evaluate(ctx context.Context, trigger model.PlayTrigger) ([]model.Achievement, error)
+		// GetSessionAchievements достижения, полученные в попытке
+		GetSessionAchievements(ctx context.Context, sessionID uuid.UUID) ([]model.Achievement, error)
+	}
+)
@@ -0,0 +1,28 @@
+package contracts
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidFunnelRange = errors.New("funnel range is invalid or too long")
+)
+
+type (
+	// AnalyticsUsecase статистика по вопросам для контент-команды
+	AnalyticsUsecase interface {
+		// RefreshQuestionStats пересчитывает статистику, вызывается задачей по расписанию
+		RefreshQuestionStats(ctx context.Context) error
+		GetQuestionStats(ctx context.Context, gameID uuid.UUID) (model.GameQuestionStats, error)
+		// RefreshFunnel пересобирает дневную воронку за последние дни, вызывается задачей по расписанию
+		RefreshFunnel(ctx context.Context) error
+		// RebuildFunnel пересобирает дневную воронку начиная с дня from
+		RebuildFunnel(ctx context.Context, from time.Time) error
+		GetFunnel(ctx context.Context, filter model.FunnelFilter) (model.Funnel, error)
+	}
+)
@@ -0,0 +1,15 @@
+package contracts
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+)
+
+type (
+	// AntiCheatUsecase помечает подозрительные попытки по событиям outbox
+	AntiCheatUsecase interface {
+		// HandleEvent обработчик outbox для answer.accepted и game.completed
+		HandleEvent(ctx context.Context, event model.Event) error
+		GetFlags(ctx context.Context, page model.Page) (model.SessionFlags, error)
+	}
+)
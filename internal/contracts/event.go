@@ -0,0 +1,27 @@
+package contracts
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"errors"
+)
+
+var (
+	ErrEventNotFound = errors.New("event not found")
+)
+
+type (
+	// EventHandler обработчик события из outbox. Доставка at-least-once: событие может прийти повторно,
+	// в том числе после успешной обработки, если упал другой обработчик того же события.
+	EventHandler func(ctx context.Context, event model.Event) error
+
+	// EventDispatcher доставляет события из outbox подписанным обработчикам внутри процесса
+	EventDispatcher interface {
+		// Subscribe регистрирует обработчик, вызывается до запуска доставки
+		Subscribe(eventType model.EventType, name string, handler EventHandler)
+		// DispatchPending доставляет готовые события, вызывается задачей по расписанию
+		DispatchPending(ctx context.Context) error
+		// Cleanup удаляет давно доставленные события
+		Cleanup(ctx context.Context) error
+	}
+)
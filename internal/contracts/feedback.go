@@ -0,0 +1,41 @@
+package contracts
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrGameNotCompleted   = errors.New("game is not completed by player")
+	ErrInvalidRating      = errors.New("rating must be between 1 and 5")
+	ErrInvalidFlagReason  = errors.New("flag reason must be 'wrong' or 'ambiguous'")
+	ErrFlagCommentTooLong = errors.New("flag comment is too long")
+	ErrQuestionNotFound   = errors.New("question not found")
+)
+
+type (
+	RateGameIn struct {
+		GameID   uuid.UUID
+		PlayerID uuid.UUID
+		Rating   int64
+	}
+
+	FlagQuestionIn struct {
+		GameID     uuid.UUID
+		PlayerID   uuid.UUID
+		QuestionID int64
+		Reason     model.FlagReason
+		Comment    *string
+	}
+
+	// FeedbackUsecase оценки игр и жалобы на вопросы, оставить их можно только после прохождения игры
+	FeedbackUsecase interface {
+		RateGame(ctx context.Context, in RateGameIn) (model.GameRating, error)
+		FlagQuestion(ctx context.Context, in FlagQuestionIn) (model.QuestionFlag, error)
+		GetFlaggedQuestions(ctx context.Context, filter model.FlaggedQuestionsFilter) (model.FlaggedQuestions, error)
+		GetRatings(ctx context.Context, page model.Page) (model.GameRatingSummaries, error)
+	}
+)
@@ -16,6 +16,16 @@ var (
 	ErrGameNotFound             = errors.New("game not found")
 	ErrEmptyQuestions           = errors.New("empty questions")
 	ErrEmptyAnswerOptions       = errors.New("empty answer options")
+	ErrAnswerNotFound           = errors.New("answer not found")
+	ErrIdempotencyKeyReused     = errors.New("idempotency key is already used for another answer")
+	ErrQuestionSkipped          = errors.New("question is skipped")
+	ErrQuestionAlreadyAnswered  = errors.New("question is already answered")
+	ErrInvalidLifeline          = errors.New("invalid lifeline type")
+	ErrLifelineNotAvailable     = errors.New("lifeline is not available for this question")
+	ErrLifelineLimitReached     = errors.New("lifeline limit reached")
+	ErrReplayNotAllowed         = errors.New("game replay is not allowed")
+	ErrReplayAttemptsExhausted  = errors.New("game replay attempts are exhausted")
+	ErrReplayCooldown           = errors.New("game replay is on cooldown")
 )
 
 type (
@@ -24,18 +34,51 @@ type (
 		PlayerID   uuid.UUID
 		QuestionID int64
 		Answer     int64
+		// IdempotencyKey ключ повторной отправки от клиента, повтор с тем же ключом возвращает записанный вердикт
+		IdempotencyKey *string
 	}
 
 	AcceptAnswersOut struct {
 		IsCorrect   bool
 		Explanation *string
+		// XP опыт, начисленный за этот ответ и завершение попытки
+		XP int64
+		// Achievements достижения, полученные этим ответом или завершением попытки
+		Achievements []model.Achievement
+	}
+
+	UseLifelineIn struct {
+		GameID     uuid.UUID
+		PlayerID   uuid.UUID
+		QuestionID int64
+		Type       model.LifelineType
+	}
+
+	UseLifelineOut struct {
+		Type       model.LifelineType
+		QuestionID int64
+		// Question вопрос с вариантами, оставшимися после 50/50
+		Question model.Question
+		// Hint подсказка, если использована hint
+		Hint *string
+		// Remaining оставшиеся в попытке подсказки
+		Remaining map[model.LifelineType]int64
+		// XP и Achievements награды за завершение попытки, если пропущен последний вопрос
+		XP           int64
+		Achievements []model.Achievement
 	}
 
 	GameUsecase interface {
 		Get(ctx context.Context, id uuid.UUID) (model.Game, error)
 		GetDaily(ctx context.Context) (model.Game, error)
 		AcceptAnswer(ctx context.Context, in *AcceptAnswersIn) (*AcceptAnswersOut, error)
+		UseLifeline(ctx context.Context, in UseLifelineIn) (UseLifelineOut, error)
 		GetCurrentState(ctx context.Context, gameID uuid.UUID, playerID uuid.UUID) (model.State, error)
 		Reset(ctx context.Context, gameID uuid.UUID, playerID uuid.UUID) error
+		GetReview(ctx context.Context, gameID uuid.UUID, playerID uuid.UUID) (model.Review, error)
+		GetCatalog(ctx context.Context, playerID uuid.UUID, filter model.CatalogFilter) (model.Catalog, error)
+		GetRecommendations(ctx context.Context, playerID uuid.UUID, limit int64) ([]model.Recommendation, error)
+		GetPlayerStats(ctx context.Context, playerID uuid.UUID) (model.PlayerStats, error)
+		GetHistory(ctx context.Context, playerID uuid.UUID, page model.Page) (model.GameHistory, error)
 	}
 )
@@ -0,0 +1,29 @@
+package contracts
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrShuttingDown         = errors.New("service is shutting down")
+	ErrSchemaVersionIsDirty = errors.New("schema version is dirty")
+	ErrSchemaIsBehind       = errors.New("schema is behind expected version")
+)
+
+type (
+	ReadinessCheck struct {
+		Name string
+		Err  error
+	}
+
+	Readiness struct {
+		Ready  bool
+		Checks []ReadinessCheck
+	}
+
+	HealthUsecase interface {
+		Ready(ctx context.Context) Readiness
+		Shutdown()
+	}
+)
@@ -0,0 +1,25 @@
+package contracts
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNotChatMember = errors.New("player is not a member of the chat")
+)
+
+type (
+	// ProgressionUsecase опыт и уровни игроков по всем играм
+	ProgressionUsecase interface {
+		// Award начисляет опыт за событие в игре и возвращает начисленное количество,
+		// вызывается в транзакции, записавшей ответ или завершившей попытку
+		Award(ctx context.Context, trigger model.PlayTrigger) (int64, error)
+		GetLevel(ctx context.Context, userID uuid.UUID) (model.PlayerLevel, error)
+		// GetChatLeaderboard участники чата по убыванию опыта, доступен только участнику чата
+		GetChatLeaderboard(ctx context.Context, userID uuid.UUID, chatID int64, page model.Page) (model.Leaderboard, error)
+	}
+)
@@ -0,0 +1,16 @@
+package contracts
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+)
+
+type (
+	// RateLimitUsecase токен-бакеты ограничения частоты запросов
+	RateLimitUsecase interface {
+		// Allow забирает токен из бакета key; нулевой лимит пропускает всегда
+		Allow(ctx context.Context, key string, limit model.RateLimit) (model.RateDecision, error)
+		// Cleanup удаляет восстановившиеся бакеты, вызывается задачей по расписанию
+		Cleanup(ctx context.Context) error
+	}
+)
@@ -4,6 +4,8 @@ import (
 	"context"
 	"easy-quizy/internal/model"
 	"errors"
+
+	"github.com/google/uuid"
 )
 
 var (
@@ -21,5 +23,6 @@ type (
 
 	UserUsecase interface {
 		RetrieveUser(ctx context.Context, data UserData) (model.User, error)
+		GetProfile(ctx context.Context, userID uuid.UUID) (model.Profile, error)
 	}
 )
@@ -0,0 +1,43 @@
+package contracts
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrWebhookNotFound         = errors.New("webhook not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrInvalidWebhookURL       = errors.New("webhook url must be an absolute http or https url")
+	ErrInvalidWebhookEvent     = errors.New("webhook event type is not supported")
+	ErrNoPendingDelivery       = errors.New("no pending webhook delivery")
+)
+
+type (
+	CreateWebhookIn struct {
+		GameID uuid.UUID
+		URL    string
+		// Secret ключ подписи HMAC, если не задан — генерируется
+		Secret *string
+		// EventTypes по умолчанию game.completed
+		EventTypes []model.EventType
+	}
+
+	// WebhookUsecase вебхуки партнеров: подписки, доставка с повторами и журнал доставок
+	WebhookUsecase interface {
+		CreateWebhook(ctx context.Context, in CreateWebhookIn) (model.Webhook, error)
+		GetWebhooks(ctx context.Context, gameID uuid.UUID) ([]model.Webhook, error)
+		DisableWebhook(ctx context.Context, webhookID uuid.UUID) error
+		GetDeliveries(ctx context.Context, webhookID uuid.UUID, page model.Page) (model.WebhookDeliveries, error)
+		// ReplayDelivery ставит доставку в очередь заново с тем же телом запроса
+		ReplayDelivery(ctx context.Context, deliveryID int64) (model.WebhookDelivery, error)
+
+		// HandleEvent обработчик outbox: создает доставки для подписанных вебхуков
+		HandleEvent(ctx context.Context, event model.Event) error
+		// DeliverPending отправляет готовые доставки, вызывается задачей по расписанию
+		DeliverPending(ctx context.Context) error
+	}
+)
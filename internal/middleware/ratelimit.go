@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// RateLimitKey выбирает, чей бакет расходует запрос; false — запрос не ограничивается
+type RateLimitKey func(c *gin.Context) (string, bool)
+
+// RateLimitMiddleware ограничивает частоту запросов токен-бакетом и отвечает 429 с Retry-After.
+// scope разделяет бакеты разных ключей (ip, player). Если хранилище бакетов недоступно, запрос пропускается.
+func RateLimitMiddleware(limiter contracts.RateLimitUsecase, scope string, rules model.RateLimitRules, key RateLimitKey) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := key(c)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		limit, route := rules.For(c.Request.Method, c.FullPath())
+		bucket := scope + ":" + id
+		if route != "" {
+			bucket += ":" + route
+		}
+
+		decision, err := limiter.Allow(c.Request.Context(), bucket, limit)
+		if err != nil {
+			logrus.Warnf("Rate limiter is unavailable, request is let through: %v", err)
+			c.Next()
+			return
+		}
+
+		if !decision.Allowed {
+			retryAfter := max(int64(math.Ceil(decision.RetryAfter.Seconds())), 1)
+			c.Header("Retry-After", strconv.FormatInt(retryAfter, 10))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "too many requests"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ClientIPKey бакет по адресу клиента, работает до авторизации
+func ClientIPKey(c *gin.Context) (string, bool) {
+	ip := c.ClientIP()
+	return ip, ip != ""
+}
+
+// PlayerKey бакет по внутреннему идентификатору игрока, ставится после AuthMiddleware
+func PlayerKey(c *gin.Context) (string, bool) {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return "", false
+	}
+
+	return userID.String(), true
+}
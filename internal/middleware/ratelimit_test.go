@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	rateLimitRepo "easy-quizy/internal/repositories/ratelimit"
+	rateLimitUC "easy-quizy/internal/usecase/ratelimit"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(context.Context, string, model.RateLimit) (model.RateDecision, error) {
+	return model.RateDecision{}, errors.New("store is down")
+}
+
+func (failingLimiter) Cleanup(context.Context) error {
+	return nil
+}
+
+// newRateLimitedRouter роутер с лимитом по адресу клиента, X-Forwarded-For берется только от trustedProxies
+func newRateLimitedRouter(t *testing.T, trustedProxies []string, handler gin.HandlerFunc) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		t.Fatalf("SetTrustedProxies: %v", err)
+	}
+	r.GET("/ping", handler, func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	return r
+}
+
+func request(r *gin.Engine, remoteAddr string, forwardedFor string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.RemoteAddr = remoteAddr
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	rules := model.RateLimitRules{Default: model.RateLimit{Requests: 2, Period: time.Minute}}
+
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		remoteAddr     string
+		forwardedFor   []string
+		wantStatus     []int
+		wantRetryAfter string
+	}{
+		{
+			name:           "over the limit gets 429 with Retry-After",
+			remoteAddr:     "192.0.2.1:1234",
+			forwardedFor:   []string{"", "", ""},
+			wantStatus:     []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+			wantRetryAfter: "30",
+		},
+		{
+			name:           "forwarded header from an untrusted peer does not reset the bucket",
+			remoteAddr:     "192.0.2.1:1234",
+			forwardedFor:   []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"},
+			wantStatus:     []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+			wantRetryAfter: "30",
+		},
+		{
+			name:           "clients behind a trusted proxy get their own buckets",
+			trustedProxies: []string{"127.0.0.1", "::1"},
+			remoteAddr:     "127.0.0.1:5000",
+			forwardedFor:   []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"},
+			wantStatus:     []int{http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+		{
+			name:           "one client behind a trusted proxy is limited",
+			trustedProxies: []string{"127.0.0.1", "::1"},
+			remoteAddr:     "[::1]:5000",
+			forwardedFor:   []string{"10.0.0.1", "10.0.0.1", "10.0.0.1"},
+			wantStatus:     []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+			wantRetryAfter: "30",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := rateLimitUC.NewUsecase(rateLimitRepo.NewMemoryRepository())
+			r := newRateLimitedRouter(t, tt.trustedProxies, RateLimitMiddleware(limiter, "ip", rules, ClientIPKey))
+
+			var last *httptest.ResponseRecorder
+			for i, forwardedFor := range tt.forwardedFor {
+				last = request(r, tt.remoteAddr, forwardedFor)
+				if last.Code != tt.wantStatus[i] {
+					t.Fatalf("request %d status = %d, want %d", i+1, last.Code, tt.wantStatus[i])
+				}
+			}
+			if got := last.Header().Get("Retry-After"); got != tt.wantRetryAfter {
+				t.Errorf("Retry-After = %q, want %q", got, tt.wantRetryAfter)
+			}
+		})
+	}
+}
+
+func TestRateLimitMiddlewareStoreDown(t *testing.T) {
+	rules := model.RateLimitRules{Default: model.RateLimit{Requests: 1, Period: time.Minute}}
+	r := newRateLimitedRouter(t, nil, RateLimitMiddleware(failingLimiter{}, "ip", rules, ClientIPKey))
+
+	for i := range 3 {
+		if w := request(r, "192.0.2.1:1234", ""); w.Code != http.StatusOK {
+			t.Fatalf("request %d status = %d, want %d while the store is down", i+1, w.Code, http.StatusOK)
+		}
+	}
+}
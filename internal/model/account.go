@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// OrphanPlayer игрок, у попыток которого нет источника: так выглядел проигравший гонку первых запросов
+	OrphanPlayer struct {
+		PlayerID    uuid.UUID
+		FirstSeenAt time.Time
+		FirstGameID uuid.UUID
+	}
+
+	// OrphanRepair результат разбора одного осиротевшего игрока
+	OrphanRepair struct {
+		OrphanPlayer
+		// UserID найденный владелец, nil если его не удалось определить однозначно
+		UserID     *uuid.UUID
+		Candidates int64
+		// Sessions сколько попыток перенесено, при плане без применения всегда 0
+		Sessions int64
+	}
+
+	// LinkCode одноразовый код, которым другой источник привязывается к пользователю
+	LinkCode struct {
+		Code      string
+		UserID    uuid.UUID
+		CreatedAt time.Time
+		ExpiresAt time.Time
+	}
+)
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AchievementPerfectScore   AchievementCode = "perfect_score"
+	AchievementDailyStreak    AchievementCode = "daily_streak_10"
+	AchievementCorrectAnswers AchievementCode = "correct_answers_100"
+	AchievementFirstInChat    AchievementCode = "first_in_chat"
+)
+
+type (
+	AchievementCode string
+
+	// AchievementDefinition описание достижения для клиента, тексты показываются игроку
+	AchievementDefinition struct {
+		Code        AchievementCode
+		Title       string
+		Description string
+	}
+
+	// Achievement выданное игроку достижение; GameID и SessionID указывают попытку, в которой оно получено
+	Achievement struct {
+		AchievementDefinition
+		UserID     uuid.UUID
+		GameID     *uuid.UUID
+		SessionID  *uuid.UUID
+		UnlockedAt time.Time
+	}
+
+	// AchievementStatus достижение в списке игрока, UnlockedAt nil — еще не получено
+	AchievementStatus struct {
+		AchievementDefinition
+		UnlockedAt *time.Time
+	}
+)
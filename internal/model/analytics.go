@@ -0,0 +1,92 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// QuestionStatsRow агрегаты по вопросу, как их сохраняет задача аналитики
+	QuestionStatsRow struct {
+		GameID     uuid.UUID
+		QuestionID int64
+		// OptionCounts число ответов по id варианта
+		OptionCounts map[int64]int64
+		Answers      int64
+		Correct      int64
+		// MedianAnswerTime медиана времени от показа вопроса (предыдущего ответа или начала попытки) до ответа
+		MedianAnswerTime *time.Duration
+		// Reached попытки, дошедшие до вопроса: ответившие на него и бросившие игру на нем
+		Reached int64
+		// Dropped незавершенные попытки без активности дольше порога, остановившиеся на этом вопросе
+		Dropped    int64
+		ComputedAt time.Time
+	}
+
+	OptionStats struct {
+		OptionID  int64
+		Text      string
+		IsCorrect bool
+		Count     int64
+		// Share доля ответов с этим вариантом среди всех ответов на вопрос
+		Share float64
+	}
+
+	QuestionStats struct {
+		QuestionID       int64
+		Text             string
+		Options          []OptionStats
+		Answers          int64
+		Correct          int64
+		CorrectRate      float64
+		MedianAnswerTime *time.Duration
+		Reached          int64
+		Dropped          int64
+		DropOffRate      float64
+	}
+
+	GameQuestionStats struct {
+		GameID uuid.UUID
+		Title  string
+		// ComputedAt время последнего пересчета, nil — статистика еще не считалась
+		ComputedAt *time.Time
+		Questions  []QuestionStats
+	}
+)
+
+const (
+	// MaxFunnelDays максимальная длина запрашиваемого ряда воронки
+	MaxFunnelDays = 366
+)
+
+type (
+	// FunnelDay воронка за день (UTC): каждый игрок учитывается на каждом шаге один раз — в день,
+	// когда впервые его прошел; сбросы считаются все
+	FunnelDay struct {
+		Day time.Time
+		// Opened игроки, впервые открывшие игру
+		Opened int64
+		// Answered игроки, впервые ответившие хотя бы на один вопрос
+		Answered int64
+		// Completed игроки, впервые завершившие игру
+		Completed int64
+		// Resets начатые повторные попытки
+		Resets int64
+	}
+
+	FunnelFilter struct {
+		// GameID nil — сумма по всем играм
+		GameID *uuid.UUID
+		From   time.Time
+		To     time.Time
+	}
+
+	// Funnel ряд по дням от From до To включительно, дни без событий заполнены нулями
+	Funnel struct {
+		GameID *uuid.UUID
+		From   time.Time
+		To     time.Time
+		Days   []FunnelDay
+	}
+)
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// CheatAnswerRate игрок отвечает чаще, чем возможно вручную
+	CheatAnswerRate CheatReason = "answer_rate"
+	// CheatFastAnswer ответ пришел быстрее, чем можно прочитать вопрос
+	CheatFastAnswer CheatReason = "fast_answer"
+	// CheatResetThenPerfect идеальная попытка после серии сбросов той же игры
+	CheatResetThenPerfect CheatReason = "reset_then_perfect"
+	// CheatSharedAnswers та же последовательность ответов, что у соседа по чату незадолго до этого
+	CheatSharedAnswers CheatReason = "shared_answers"
+)
+
+type (
+	CheatReason string
+
+	// SessionFlag подозрение на нечестную игру; помеченные попытки не учитываются в рейтингах
+	SessionFlag struct {
+		ID        int64
+		SessionID uuid.UUID
+		UserID    uuid.UUID
+		GameID    uuid.UUID
+		Reason    CheatReason
+		// Details человекочитаемое объяснение для модератора
+		Details   string
+		CreatedAt time.Time
+	}
+
+	SessionFlags struct {
+		Items []SessionFlag
+		Total int64
+	}
+)
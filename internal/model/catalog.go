@@ -0,0 +1,60 @@
+package model
+
+import (
+	"github.com/google/uuid"
+)
+
+type (
+	// CatalogFilter фильтры каталога игр, пустые поля не ограничивают выборку
+	CatalogFilter struct {
+		// Query полнотекстовый поиск по названию и описанию
+		Query      string
+		Category   *string
+		Difficulty *string
+		Language   *string
+		// Tags игра должна содержать все перечисленные теги
+		Tags []string
+		// Type по умолчанию в каталог не попадают ежедневные игры, они доступны через /api/game/daily.
+		// С типом daily показываются только уже вышедшие ежедневные игры, без очереди на будущие дни.
+		Type *GameType
+		Page Page
+	}
+
+	CatalogItem struct {
+		ID             uuid.UUID
+		Type           GameType
+		Title          string
+		Description    *string
+		Category       *string
+		Tags           []string
+		Difficulty     *string
+		Language       *string
+		Cover          *string
+		QuestionsCount int64
+		// Played игрок ответил хотя бы на один вопрос в любой попытке
+		Played bool
+		// Completed у игрока есть завершенная попытка
+		Completed bool
+	}
+
+	Catalog struct {
+		Items []CatalogItem
+		Total int64
+	}
+)
+
+// CatalogItemFromGame заполняет описание игры, флаги игрока выставляет вызывающий
+func CatalogItemFromGame(game Game) CatalogItem {
+	return CatalogItem{
+		ID:             game.ID,
+		Type:           game.Type,
+		Title:          game.Title,
+		Description:    game.Description,
+		Category:       game.Category,
+		Tags:           game.Tags,
+		Difficulty:     game.Difficulty,
+		Language:       game.Language,
+		Cover:          game.Cover,
+		QuestionsCount: int64(len(game.Questions)),
+	}
+}
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	EventAnswerAccepted EventType = "answer.accepted"
+	EventGameCompleted  EventType = "game.completed"
+	// EventDailyCompleted публикуется вместе с EventGameCompleted для ежедневных игр
+	EventDailyCompleted EventType = "daily.completed"
+)
+
+type (
+	EventType string
+
+	// Event доменное событие из outbox, Payload — JSON одной из структур *Payload ниже
+	Event struct {
+		ID        int64
+		Type      EventType
+		Payload   json.RawMessage
+		CreatedAt time.Time
+		// Attempts сколько раз доставка уже завершалась ошибкой
+		Attempts int64
+	}
+
+	// SubscriberDelivery состояние доставки события одному подписчику; повторы и отказ у каждого подписчика свои
+	SubscriberDelivery struct {
+		EventID     int64
+		Subscriber  string
+		Attempts    int64
+		AvailableAt time.Time
+		LastError   *string
+		ProcessedAt *time.Time
+		FailedAt    *time.Time
+	}
+
+	AnswerAcceptedPayload struct {
+		GameID     uuid.UUID `json:"gameId"`
+		PlayerID   uuid.UUID `json:"playerId"`
+		SessionID  uuid.UUID `json:"sessionId"`
+		QuestionID int64     `json:"questionId"`
+		AnswerID   int64     `json:"answerId"`
+		IsCorrect  bool      `json:"isCorrect"`
+		AnsweredAt time.Time `json:"answeredAt"`
+	}
+
+	GameCompletedPayload struct {
+		GameID     uuid.UUID `json:"gameId"`
+		GameType   GameType  `json:"gameType"`
+		PlayerID   uuid.UUID `json:"playerId"`
+		SessionID  uuid.UUID `json:"sessionId"`
+		Attempt    int64     `json:"attempt"`
+		Score      int64     `json:"score"`
+		ResultText string    `json:"resultText"`
+		StartedAt  time.Time `json:"startedAt"`
+		FinishedAt time.Time `json:"finishedAt"`
+	}
+)
+
+func NewEvent(eventType EventType, payload any) (Event, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, err
+	}
+
+	return Event{
+		Type:    eventType,
+		Payload: raw,
+	}, nil
+}
+
+// Decode разбирает Payload в структуру, соответствующую типу события
+func (e Event) Decode(target any) error {
+	return json.Unmarshal(e.Payload, target)
+}
@@ -0,0 +1,77 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	MinGameRating = 1
+	MaxGameRating = 5
+
+	FlagReasonWrong     FlagReason = "wrong"
+	FlagReasonAmbiguous FlagReason = "ambiguous"
+)
+
+type (
+	FlagReason string
+
+	// GameRating оценка игры игроком, повторная оценка заменяет предыдущую
+	GameRating struct {
+		GameID    uuid.UUID
+		PlayerID  uuid.UUID
+		Rating    int64
+		UpdatedAt time.Time
+	}
+
+	// QuestionFlag жалоба игрока на вопрос, повторная жалоба на тот же вопрос заменяет предыдущую
+	QuestionFlag struct {
+		GameID     uuid.UUID
+		PlayerID   uuid.UUID
+		QuestionID int64
+		Reason     FlagReason
+		Comment    *string
+		UpdatedAt  time.Time
+	}
+
+	// FlaggedQuestion жалобы на вопрос, сгруппированные по вопросу
+	FlaggedQuestion struct {
+		GameID         uuid.UUID
+		GameTitle      string
+		QuestionID     int64
+		QuestionText   string
+		Total          int64
+		Wrong          int64
+		Ambiguous      int64
+		LastFlaggedAt  time.Time
+		RecentComments []string
+	}
+
+	FlaggedQuestions struct {
+		Items []FlaggedQuestion
+		Total int64
+	}
+
+	FlaggedQuestionsFilter struct {
+		GameID *uuid.UUID
+		Page   Page
+	}
+
+	// GameRatingSummary средняя оценка игры
+	GameRatingSummary struct {
+		GameID    uuid.UUID
+		GameTitle string
+		Average   float64
+		Count     int64
+	}
+
+	GameRatingSummaries struct {
+		Items []GameRatingSummary
+		Total int64
+	}
+)
+
+func (r FlagReason) IsValid() bool {
+	return r == FlagReasonWrong || r == FlagReasonAmbiguous
+}
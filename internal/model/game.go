@@ -1,22 +1,41 @@
 package model
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 const (
 	GameTypeClassic = "classic"
 	GameTypeDaily   = "daily"
+
+	// TriggerAnswerAccepted игрок дал новый ответ
+	TriggerAnswerAccepted PlayTriggerType = "answer_accepted"
+	// TriggerGameCompleted попытка только что завершена
+	TriggerGameCompleted PlayTriggerType = "game_completed"
 )
 
 type (
-	GameType string
+	GameType        string
+	PlayTriggerType string
 
 	Game struct {
 		ID           uuid.UUID
 		Type         GameType
 		Title        string
 		Description  *string
+		Category     *string
+		Tags         []string
+		Difficulty   *string
+		Language     *string
+		Cover        *string
 		Questions    []Question
 		ScoreResults []ScoreResult
+		Lifelines    LifelineRules
+		// Replay правило из квиза, пустое — правило по умолчанию, см. Game.ReplayPolicy
+		Replay    ReplayPolicy
+		CreatedAt time.Time
 	}
 
 	GameInfo struct {
@@ -30,23 +49,35 @@ type (
 		Text string
 	}
 
+	// GameSession попытка прохождения игры игроком, сброс игры начинает новую попытку
 	GameSession struct {
-		GameID   uuid.UUID
-		PlayerID uuid.UUID
-		Answers  []GameSessionAnswer
+		ID         uuid.UUID
+		GameID     uuid.UUID
+		PlayerID   uuid.UUID
+		Attempt    int64
+		StartedAt  time.Time
+		FinishedAt *time.Time
+		Score      *int64
+		ResultText *string
+		Answers    []GameSessionAnswer
+		Lifelines  []LifelineUsage
 	}
 
 	GameSessionAnswer struct {
-		QuestionID int64
-		AnswerID   int64
-		IsCorrect  bool
+		QuestionID     int64
+		AnswerID       int64
+		IsCorrect      bool
+		IdempotencyKey *string
+		CreatedAt      time.Time
 	}
 
 	Question struct {
-		ID            int64
-		Text          string
-		ImageID       *string
-		Explanation   *string
+		ID          int64
+		Text        string
+		ImageID     *string
+		Explanation *string
+		// Hint открывается игроку только подсказкой hint
+		Hint          *string
 		AnswerOptions []AnswerOption
 	}
 
@@ -58,10 +89,19 @@ type (
 	}
 
 	State struct {
+		// Question текущий вопрос; после 50/50 — без убранных вариантов
 		Question *Question
+		// Hint подсказка к текущему вопросу, если игрок ее открыл
+		Hint     *string
 		Result   *Result
 		Progress Progress
 		GameInfo GameInfo
+		// Lifelines оставшиеся в попытке подсказки, пусто — в игре подсказок нет
+		Lifelines map[LifelineType]int64
+		// Recommendations что сыграть дальше, заполняется только для завершенной попытки
+		Recommendations []Recommendation
+		// Achievements достижения, полученные в текущей попытке
+		Achievements []Achievement
 	}
 
 	Result struct {
@@ -73,6 +113,31 @@ type (
 		Answered int64
 		Total    int64
 	}
+
+	// Review разбор завершенной попытки: выбранные игроком и правильные варианты по каждому вопросу
+	Review struct {
+		GameInfo GameInfo
+		Result   Result
+		Items    []ReviewItem
+	}
+
+	ReviewItem struct {
+		Question Question
+		// ChosenAnswerID nil, если на вопрос нет ответа
+		ChosenAnswerID *int64
+		IsCorrect      bool
+		// Skipped вопрос пропущен подсказкой skip
+		Skipped bool
+	}
+
+	// PlayTrigger событие в игре, по которому в той же транзакции выдаются достижения и опыт
+	PlayTrigger struct {
+		Type    PlayTriggerType
+		Game    Game
+		Session GameSession
+		// Answer только для TriggerAnswerAccepted
+		Answer *GameSessionAnswer
+	}
 )
 
 func (q Question) GetCorrectAnswers() []AnswerOption {
@@ -88,3 +153,39 @@ func (q Question) GetCorrectAnswers() []AnswerOption {
 
 	return result
 }
+
+// ResultText текст результата для набранных очков, пустая строка — если диапазон не найден
+func (g Game) ResultText(score int64) string {
+	for _, res := range g.ScoreResults {
+		if score >= res.From && score <= res.To {
+			return res.Text
+		}
+	}
+
+	return ""
+}
+
+func (s GameSession) IsFinished() bool {
+	return s.FinishedAt != nil
+}
+
+// Duration время прохождения, для незавершенной попытки — nil
+func (s GameSession) Duration() *time.Duration {
+	if s.FinishedAt == nil {
+		return nil
+	}
+
+	duration := s.FinishedAt.Sub(s.StartedAt)
+	return &duration
+}
+
+// IsAnswered возвращает ответ игрока на вопрос в этой попытке
+func (s GameSession) IsAnswered(questionID int64) (GameSessionAnswer, bool) {
+	for _, answer := range s.Answers {
+		if answer.QuestionID == questionID {
+			return answer, true
+		}
+	}
+
+	return GameSessionAnswer{}, false
+}
@@ -0,0 +1,126 @@
+package model
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	// LifelineFiftyFifty убирает два неправильных варианта ответа
+	LifelineFiftyFifty LifelineType = "fifty_fifty"
+	// LifelineSkip засчитывает вопрос пройденным без ответа и без очка
+	LifelineSkip LifelineType = "skip"
+	// LifelineHint показывает подсказку к вопросу из квиза
+	LifelineHint LifelineType = "hint"
+)
+
+// LifelineTypes все подсказки в порядке показа клиенту
+var LifelineTypes = []LifelineType{LifelineFiftyFifty, LifelineSkip, LifelineHint}
+
+type (
+	LifelineType string
+
+	// LifelineRules подсказки игры: сколько раз каждую можно использовать за попытку
+	LifelineRules struct {
+		Allowance map[LifelineType]int64
+		// ReduceScore правильный ответ после 50/50 или подсказки не приносит очка
+		ReduceScore bool
+	}
+
+	// LifelineUsage использование подсказки в попытке
+	LifelineUsage struct {
+		QuestionID int64
+		Type       LifelineType
+		// RemovedAnswerIDs варианты, убранные 50/50; сохраняются, чтобы после перезагрузки остались те же
+		RemovedAnswerIDs []int64
+		CreatedAt        time.Time
+	}
+)
+
+func (t LifelineType) IsValid() bool {
+	return slices.Contains(LifelineTypes, t)
+}
+
+// Remaining сколько использований каждой подсказки осталось в попытке; подсказки без лимита не попадают
+func (r LifelineRules) Remaining(session GameSession) map[LifelineType]int64 {
+	result := make(map[LifelineType]int64, len(r.Allowance))
+	for lifelineType, allowance := range r.Allowance {
+		if allowance <= 0 {
+			continue
+		}
+
+		result[lifelineType] = max(allowance-session.LifelinesUsed(lifelineType), 0)
+	}
+
+	return result
+}
+
+// LifelinesUsed сколько раз подсказка использована в попытке
+func (s GameSession) LifelinesUsed(lifelineType LifelineType) int64 {
+	var result int64
+	for _, usage := range s.Lifelines {
+		if usage.Type == lifelineType {
+			result++
+		}
+	}
+
+	return result
+}
+
+// LifelineUsed использование подсказки на вопросе
+func (s GameSession) LifelineUsed(questionID int64, lifelineType LifelineType) (LifelineUsage, bool) {
+	for _, usage := range s.Lifelines {
+		if usage.QuestionID == questionID && usage.Type == lifelineType {
+			return usage, true
+		}
+	}
+
+	return LifelineUsage{}, false
+}
+
+// IsSkipped пропущен ли вопрос подсказкой skip
+func (s GameSession) IsSkipped(questionID int64) bool {
+	_, ok := s.LifelineUsed(questionID, LifelineSkip)
+	return ok
+}
+
+// IsDone вопрос отвечен или пропущен
+func (s GameSession) IsDone(questionID int64) bool {
+	if _, ok := s.IsAnswered(questionID); ok {
+		return true
+	}
+
+	return s.IsSkipped(questionID)
+}
+
+// DoneCount число отвеченных и пропущенных вопросов
+func (s GameSession) DoneCount() int64 {
+	result := int64(len(s.Answers))
+	for _, usage := range s.Lifelines {
+		if usage.Type != LifelineSkip {
+			continue
+		}
+		if _, answered := s.IsAnswered(usage.QuestionID); !answered {
+			result++
+		}
+	}
+
+	return result
+}
+
+// WithoutAnswers копия вопроса без перечисленных вариантов
+func (q Question) WithoutAnswers(removed []int64) Question {
+	if len(removed) == 0 {
+		return q
+	}
+
+	options := make([]AnswerOption, 0, len(q.AnswerOptions))
+	for _, option := range q.AnswerOptions {
+		if !slices.Contains(removed, option.ID) {
+			options = append(options, option)
+		}
+	}
+
+	q.AnswerOptions = options
+	return q
+}
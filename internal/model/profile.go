@@ -0,0 +1,66 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+type (
+	Profile struct {
+		User
+		Sources []UserSource
+		Chats   []UserChat
+	}
+
+	PlayerStats struct {
+		// GamesPlayed игры, в которых игрок ответил хотя бы на один вопрос
+		GamesPlayed      int64
+		GamesCompleted   int64
+		FinishedAttempts int64
+		TotalAnswers     int64
+		CorrectAnswers   int64
+		BestScore        *int64
+		AverageDuration  *time.Duration
+	}
+
+	// GameHistoryItem завершенная игра игрока, данные последней завершенной попытки
+	GameHistoryItem struct {
+		GameID     uuid.UUID
+		Title      string
+		Score      int64
+		ResultText *string
+		FinishedAt time.Time
+		Attempts   int64
+	}
+
+	GameHistory struct {
+		Items []GameHistoryItem
+		Total int64
+	}
+
+	Page struct {
+		Limit  int64
+		Offset int64
+	}
+)
+
+// Normalize ограничивает размер страницы и убирает отрицательное смещение
+func (p Page) Normalize() Page {
+	if p.Limit <= 0 {
+		p.Limit = DefaultPageLimit
+	}
+	if p.Limit > MaxPageLimit {
+		p.Limit = MaxPageLimit
+	}
+	if p.Offset < 0 {
+		p.Offset = 0
+	}
+
+	return p
+}
@@ -0,0 +1,106 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	XPReasonCorrectAnswer XPReason = "correct_answer"
+	XPReasonCompletion    XPReason = "completion"
+	XPReasonDailyStreak   XPReason = "daily_streak"
+)
+
+type (
+	XPReason string
+
+	// XPEntry запись журнала опыта; опыт игрока — сумма его записей
+	XPEntry struct {
+		ID        int64
+		UserID    uuid.UUID
+		Amount    int64
+		Reason    XPReason
+		GameID    uuid.UUID
+		SessionID uuid.UUID
+		// QuestionID только для XPReasonCorrectAnswer
+		QuestionID *int64
+		CreatedAt  time.Time
+	}
+
+	// LevelCurve накопленный опыт, с которого начинаются уровни 2, 3, ...;
+	// после последнего порога каждый следующий уровень стоит столько же, сколько последний шаг кривой
+	LevelCurve []int64
+
+	PlayerLevel struct {
+		Level int64
+		XP    int64
+		// LevelXP опыт, с которого начался текущий уровень
+		LevelXP int64
+		// NextLevelXP опыт, с которого начнется следующий уровень
+		NextLevelXP int64
+	}
+
+	LeaderboardEntry struct {
+		Rank   int64
+		UserID uuid.UUID
+		PlayerLevel
+	}
+
+	Leaderboard struct {
+		Items []LeaderboardEntry
+		Total int64
+	}
+)
+
+// Level уровень для накопленного опыта, уровни начинаются с 1
+func (c LevelCurve) Level(xp int64) PlayerLevel {
+	result := PlayerLevel{Level: 1, XP: xp}
+
+	var prev int64
+	for _, threshold := range c {
+		if xp < threshold {
+			result.LevelXP = prev
+			result.NextLevelXP = threshold
+			return result
+		}
+
+		result.Level++
+		prev = threshold
+	}
+
+	step := prev
+	if len(c) > 1 {
+		step = c[len(c)-1] - c[len(c)-2]
+	}
+	if step <= 0 {
+		step = 1
+	}
+
+	extra := (xp - prev) / step
+	result.Level += extra
+	result.LevelXP = prev + extra*step
+	result.NextLevelXP = result.LevelXP + step
+
+	return result
+}
+
+// DailyStreak сколько дней подряд, заканчивая lastDay, есть в days; days — дни UTC, последние сверху
+func DailyStreak(days []time.Time, lastDay time.Time) int64 {
+	expected := lastDay.UTC().Truncate(24 * time.Hour)
+
+	var streak int64
+	for _, day := range days {
+		if day.After(expected) {
+			continue
+		}
+		if !day.Equal(expected) {
+			break
+		}
+
+		streak++
+		expected = expected.AddDate(0, 0, -1)
+	}
+
+	return streak
+}
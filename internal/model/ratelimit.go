@@ -0,0 +1,91 @@
+package model
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+type (
+	// RateLimit токен-бакет: емкость Requests, полностью восстанавливается за Period
+	RateLimit struct {
+		Requests int64
+		Period   time.Duration
+	}
+
+	// RateLimitRules лимиты по маршрутам; маршрут без своего правила делит бакет Default с остальными
+	RateLimitRules struct {
+		Default RateLimit
+		// Routes ключ "METHOD /path" в формате c.FullPath(), например "POST /api/game/:game_id/accept-answer"
+		Routes map[string]RateLimit
+	}
+
+	// TokenBucket состояние бакета на момент UpdatedAt
+	TokenBucket struct {
+		Tokens    float64
+		UpdatedAt time.Time
+	}
+
+	RateDecision struct {
+		Allowed bool
+		// RetryAfter через сколько появится токен, только для отказа
+		RetryAfter time.Duration
+	}
+)
+
+func RouteKey(method string, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+func (l RateLimit) IsZero() bool {
+	return l.Requests <= 0 || l.Period <= 0
+}
+
+// Rate скорость восстановления, токенов в секунду
+func (l RateLimit) Rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// For лимит маршрута и суффикс ключа бакета: у маршрута со своим правилом отдельный бакет
+func (r RateLimitRules) For(method string, path string) (RateLimit, string) {
+	key := RouteKey(method, path)
+	if limit, ok := r.Routes[key]; ok {
+		return limit, key
+	}
+
+	return r.Default, ""
+}
+
+// Refill состояние бакета к моменту now; новый бакет полон
+func (b TokenBucket) Refill(limit RateLimit, now time.Time) TokenBucket {
+	if b.UpdatedAt.IsZero() {
+		return TokenBucket{Tokens: float64(limit.Requests), UpdatedAt: now}
+	}
+
+	elapsed := max(now.Sub(b.UpdatedAt).Seconds(), 0)
+	return TokenBucket{
+		Tokens:    min(float64(limit.Requests), b.Tokens+elapsed*limit.Rate()),
+		UpdatedAt: now,
+	}
+}
+
+// Take забирает токен, если он есть, и возвращает новое состояние бакета
+func (b TokenBucket) Take(limit RateLimit, now time.Time) (TokenBucket, RateDecision) {
+	bucket := b.Refill(limit, now)
+	if bucket.Tokens >= 1 {
+		bucket.Tokens--
+		return bucket, RateDecision{Allowed: true}
+	}
+
+	return bucket, RateDecision{RetryAfter: bucket.RetryAfter(limit)}
+}
+
+// RetryAfter через сколько в бакете появится целый токен
+func (b TokenBucket) RetryAfter(limit RateLimit) time.Duration {
+	missing := 1 - b.Tokens
+	if missing <= 0 {
+		return 0
+	}
+
+	return time.Duration(math.Ceil(missing / limit.Rate() * float64(time.Second)))
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	// 10 запросов в минуту: токен восстанавливается за 6 секунд
+	limit := RateLimit{Requests: 10, Period: time.Minute}
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		bucket         TokenBucket
+		now            time.Time
+		wantTokens     float64
+		wantAllowed    bool
+		wantRetryAfter time.Duration
+	}{
+		{name: "new bucket is full", bucket: TokenBucket{}, now: start, wantTokens: 9, wantAllowed: true},
+		{name: "refill is proportional to elapsed time", bucket: TokenBucket{Tokens: 0, UpdatedAt: start}, now: start.Add(12 * time.Second), wantTokens: 1, wantAllowed: true},
+		{name: "refill is capped by capacity", bucket: TokenBucket{Tokens: 5, UpdatedAt: start}, now: start.Add(time.Hour), wantTokens: 9, wantAllowed: true},
+		{name: "clock going back adds nothing", bucket: TokenBucket{Tokens: 2, UpdatedAt: start}, now: start.Add(-time.Minute), wantTokens: 1, wantAllowed: true},
+		{name: "empty bucket waits for a whole token", bucket: TokenBucket{Tokens: 0, UpdatedAt: start}, now: start, wantTokens: 0, wantRetryAfter: 6 * time.Second},
+		{name: "partial token waits for the rest", bucket: TokenBucket{Tokens: 0.5, UpdatedAt: start}, now: start, wantTokens: 0.5, wantRetryAfter: 3 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bucket, decision := tt.bucket.Take(limit, tt.now)
+			if decision.Allowed != tt.wantAllowed {
+				t.Errorf("Allowed = %v, want %v", decision.Allowed, tt.wantAllowed)
+			}
+			if decision.RetryAfter != tt.wantRetryAfter {
+				t.Errorf("RetryAfter = %s, want %s", decision.RetryAfter, tt.wantRetryAfter)
+			}
+			if diff := bucket.Tokens - tt.wantTokens; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("Tokens = %v, want %v", bucket.Tokens, tt.wantTokens)
+			}
+			if !bucket.UpdatedAt.Equal(tt.now) {
+				t.Errorf("UpdatedAt = %s, want %s", bucket.UpdatedAt, tt.now)
+			}
+		})
+	}
+}
+
+func TestRetryAfterFullBucket(t *testing.T) {
+	limit := RateLimit{Requests: 10, Period: time.Minute}
+	if got := (TokenBucket{Tokens: 1}).RetryAfter(limit); got != 0 {
+		t.Errorf("RetryAfter = %s, want 0", got)
+	}
+}
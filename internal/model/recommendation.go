@@ -0,0 +1,30 @@
+package model
+
+import (
+	"github.com/google/uuid"
+)
+
+type (
+	// CompletedGame лучший результат игрока в завершенной игре, основа для подбора похожих игр
+	CompletedGame struct {
+		GameID         uuid.UUID
+		Category       *string
+		BestScore      int64
+		QuestionsCount int64
+	}
+
+	Recommendation struct {
+		CatalogItem
+		// Score итоговый вес игры, чем больше, тем выше в выдаче
+		Score float64
+	}
+)
+
+// ScoreRatio доля набранных очков от числа вопросов, ограничена сверху единицей
+func (g CompletedGame) ScoreRatio() float64 {
+	if g.QuestionsCount <= 0 {
+		return 0
+	}
+
+	return min(float64(g.BestScore)/float64(g.QuestionsCount), 1)
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	// ReplayUnlimited новую попытку можно начать в любой момент
+	ReplayUnlimited ReplayPolicyType = "unlimited"
+	// ReplayAttempts всего не больше MaxAttempts попыток, включая первую
+	ReplayAttempts ReplayPolicyType = "attempts"
+	// ReplayCooldown новую попытку можно начать через Cooldown после завершения предыдущей,
+	// а если она не завершена — после ее начала
+	ReplayCooldown ReplayPolicyType = "cooldown"
+	// ReplayNever игра проходится один раз
+	ReplayNever ReplayPolicyType = "never"
+)
+
+var ReplayPolicyTypes = []ReplayPolicyType{ReplayUnlimited, ReplayAttempts, ReplayCooldown, ReplayNever}
+
+type (
+	ReplayPolicyType string
+
+	// ReplayPolicy правило повторного прохождения игры из квиза
+	ReplayPolicy struct {
+		Type        ReplayPolicyType
+		MaxAttempts int64
+		Cooldown    time.Duration
+	}
+)
+
+func (t ReplayPolicyType) IsValid() bool {
+	return slices.Contains(ReplayPolicyTypes, t)
+}
+
+// ReplayPolicy правило повтора игры; если в квизе его нет, ежедневную игру повторить нельзя, остальные — без ограничений
+func (g Game) ReplayPolicy() ReplayPolicy {
+	if g.Replay.Type != "" {
+		return g.Replay
+	}
+	if g.Type == GameTypeDaily {
+		return ReplayPolicy{Type: ReplayNever}
+	}
+
+	return ReplayPolicy{Type: ReplayUnlimited}
+}
@@ -0,0 +1,20 @@
+package model
+
+type (
+	SchemaStatus struct {
+		Version    uint
+		Dirty      bool
+		Latest     uint
+		Migrations []SchemaMigration
+	}
+
+	SchemaMigration struct {
+		Version uint
+		Name    string
+		Applied bool
+	}
+)
+
+func (s SchemaStatus) IsBehind() bool {
+	return s.Version < s.Latest
+}
@@ -1,6 +1,10 @@
 package model
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type (
 	User struct {
@@ -9,8 +13,9 @@ type (
 
 	UserSource struct {
 		User
-		IDext  string
-		Source string
+		IDext     string
+		Source    string
+		CreatedAt time.Time
 	}
 
 	UserChat struct {
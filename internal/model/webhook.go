@@ -0,0 +1,84 @@
+package model
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "pending"
+	DeliveryStatusSucceeded DeliveryStatus = "succeeded"
+	DeliveryStatusFailed    DeliveryStatus = "failed"
+)
+
+// WebhookEventTypes события, на которые можно подписать вебхук
+var WebhookEventTypes = []EventType{EventGameCompleted, EventDailyCompleted}
+
+type (
+	DeliveryStatus string
+
+	// Webhook подписка партнера на события игры
+	Webhook struct {
+		ID         uuid.UUID
+		GameID     uuid.UUID
+		URL        string
+		Secret     string
+		EventTypes []EventType
+		Active     bool
+		CreatedAt  time.Time
+	}
+
+	// WebhookDelivery запись журнала доставки, Body отправляется как есть при каждой попытке и при повторе
+	WebhookDelivery struct {
+		ID             int64
+		WebhookID      uuid.UUID
+		EventID        int64
+		EventType      EventType
+		Body           json.RawMessage
+		Status         DeliveryStatus
+		Attempts       int64
+		NextAttemptAt  time.Time
+		LastStatusCode *int64
+		LastError      *string
+		CreatedAt      time.Time
+		DeliveredAt    *time.Time
+	}
+
+	WebhookDeliveries struct {
+		Items []WebhookDelivery
+		Total int64
+	}
+
+	// WebhookBody тело запроса партнеру
+	WebhookBody struct {
+		EventID   int64            `json:"eventId"`
+		Type      EventType        `json:"type"`
+		CreatedAt time.Time        `json:"createdAt"`
+		Data      WebhookCompleted `json:"data"`
+	}
+
+	// WebhookCompleted данные game.completed и daily.completed для партнера. Внутренний id игрока
+	// не передается: PlayerID псевдоним, постоянный в пределах одного вебхука.
+	WebhookCompleted struct {
+		GameID     uuid.UUID `json:"gameId"`
+		GameType   GameType  `json:"gameType"`
+		PlayerID   string    `json:"playerId"`
+		SessionID  uuid.UUID `json:"sessionId"`
+		Attempt    int64     `json:"attempt"`
+		Score      int64     `json:"score"`
+		ResultText string    `json:"resultText"`
+		StartedAt  time.Time `json:"startedAt"`
+		FinishedAt time.Time `json:"finishedAt"`
+	}
+)
+
+func (w Webhook) IsSubscribed(eventType EventType) bool {
+	return w.Active && slices.Contains(w.EventTypes, eventType)
+}
+
+func IsWebhookEventType(eventType EventType) bool {
+	return slices.Contains(WebhookEventTypes, eventType)
+}
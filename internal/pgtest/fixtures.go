@@ -0,0 +1,80 @@
+package pgtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// InsertGame добавляет квиз из questions вопросов: первый вариант ответа правильный, второй нет.
+// Результат "done" покрывает любой счет.
+func InsertGame(tb testing.TB, db *sqlx.DB, gameType string, questions int) uuid.UUID {
+	tb.Helper()
+
+	type option struct {
+		Text      string `json:"text"`
+		IsCorrect bool   `json:"isCorrect"`
+	}
+	type question struct {
+		Question string   `json:"question"`
+		Options  []option `json:"options"`
+	}
+
+	payload := map[string]any{
+		"name":   "pgtest quiz",
+		"type":   gameType,
+		"result": map[string]string{fmt.Sprintf("0-%d", questions): "done"},
+	}
+	items := make([]question, 0, questions)
+	for i := range questions {
+		items = append(items, question{
+			Question: fmt.Sprintf("question %d", i),
+			Options:  []option{{Text: "right", IsCorrect: true}, {Text: "wrong"}},
+		})
+	}
+	payload["questions"] = items
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		tb.Fatalf("pgtest: failed to marshal game: %v", err)
+	}
+
+	id := uuid.New()
+	if _, err := db.Exec(
+		`insert into easy_quizy_game (id, payload, "type") values ($1, $2, $3)`,
+		id, raw, gameType,
+	); err != nil {
+		tb.Fatalf("pgtest: failed to insert game: %v", err)
+	}
+
+	return id
+}
+
+// InsertDailyGame ставит игру в очередь ежедневных
+func InsertDailyGame(tb testing.TB, db *sqlx.DB, gameID uuid.UUID) {
+	tb.Helper()
+
+	if _, err := db.Exec(`insert into easy_quizy_game_daily (game_id) values ($1)`, gameID); err != nil {
+		tb.Fatalf("pgtest: failed to insert daily game: %v", err)
+	}
+}
+
+// InsertSession начинает следующую попытку игрока без ответов
+func InsertSession(tb testing.TB, db *sqlx.DB, gameID uuid.UUID, playerID uuid.UUID) uuid.UUID {
+	tb.Helper()
+
+	id := uuid.New()
+	if _, err := db.Exec(`
+		insert into easy_quizy_session (id, game_id, player_id, attempt)
+		select $1, $2, $3, coalesce(max(attempt), 0) + 1
+		from easy_quizy_session
+		where game_id = $2 and player_id = $3
+	`, id, gameID, playerID); err != nil {
+		tb.Fatalf("pgtest: failed to insert session: %v", err)
+	}
+
+	return id
+}
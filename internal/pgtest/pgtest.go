@@ -0,0 +1,150 @@
+// Package pgtest поднимает одноразовый Postgres для интеграционных тестов репозиториев.
+//
+// Источник базы выбирается по порядку:
+//  1. PGTEST_DSN — существующий сервер, для каждого вызова создается отдельная база;
+//  2. initdb/pg_ctl из PATH (или из каталога PGTEST_BIN) — кластер во временном каталоге;
+//  3. docker — контейнер postgres:16-alpine (образ задается PGTEST_IMAGE).
+//
+// Если ни один способ недоступен, тест пропускается.
+package pgtest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+
+	schemaRepo "easy-quizy/internal/repositories/schema"
+	"easy-quizy/migrations"
+)
+
+const (
+	envDSN   = "PGTEST_DSN"
+	envBin   = "PGTEST_BIN"
+	envImage = "PGTEST_IMAGE"
+
+	defaultImage = "postgres:16-alpine"
+	readyTimeout = 30 * time.Second
+)
+
+type (
+	// server запущенный экземпляр Postgres, dsn указывает на служебную базу postgres
+	server struct {
+		dsn  string
+		stop func()
+	}
+
+	starter func(tb testing.TB) (*server, error)
+)
+
+// Start возвращает подключение к пустой базе с примененными миграциями из migrations/.
+// База и сервер удаляются в tb.Cleanup.
+func Start(tb testing.TB) *sqlx.DB {
+	tb.Helper()
+
+	srv := startServer(tb)
+
+	admin, err := connect(srv.dsn)
+	if err != nil {
+		tb.Fatalf("pgtest: failed to connect to server: %v", err)
+	}
+	tb.Cleanup(func() { _ = admin.Close() })
+
+	name := "pgtest_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	if _, err := admin.Exec("create database " + name); err != nil {
+		tb.Fatalf("pgtest: failed to create database: %v", err)
+	}
+
+	db, err := connect(withDatabase(srv.dsn, name))
+	if err != nil {
+		tb.Fatalf("pgtest: failed to connect to database: %v", err)
+	}
+	tb.Cleanup(func() {
+		_ = db.Close()
+		_, _ = admin.Exec("drop database if exists " + name + " with (force)")
+	})
+
+	if err := schemaRepo.NewMigrator(db, migrations.FS).Up(context.Background()); err != nil {
+		tb.Fatalf("pgtest: failed to apply migrations: %v", err)
+	}
+
+	return db
+}
+
+// Truncate очищает все таблицы сервиса, чтобы кейсы table-driven теста не влияли друг на друга
+func Truncate(tb testing.TB, db *sqlx.DB) {
+	tb.Helper()
+
+	var tables []string
+	if err := db.Select(&tables, `
+		select quote_ident(tablename)
+		from pg_tables
+		where schemaname = current_schema() and tablename like 'easy_quizy_%'
+	`); err != nil {
+		tb.Fatalf("pgtest: failed to list tables: %v", err)
+	}
+	if len(tables) == 0 {
+		return
+	}
+
+	if _, err := db.Exec("truncate " + strings.Join(tables, ", ") + " restart identity cascade"); err != nil {
+		tb.Fatalf("pgtest: failed to truncate tables: %v", err)
+	}
+}
+
+func startServer(tb testing.TB) *server {
+	tb.Helper()
+
+	var reasons []string
+	for _, start := range []starter{startFromDSN, startLocal, startDocker} {
+		srv, err := start(tb)
+		if err != nil {
+			reasons = append(reasons, err.Error())
+			continue
+		}
+
+		tb.Cleanup(srv.stop)
+		return srv
+	}
+
+	tb.Skipf("pgtest: postgres is not available: %s", strings.Join(reasons, "; "))
+	return nil
+}
+
+func connect(dsn string) (*sqlx.DB, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), readyTimeout)
+	defer cancel()
+
+	var lastErr error
+	for ctx.Err() == nil {
+		db, err := sqlx.ConnectContext(ctx, "postgres", dsn)
+		if err == nil {
+			return db, nil
+		}
+
+		lastErr = err
+		time.Sleep(200 * time.Millisecond)
+	}
+
+	return nil, fmt.Errorf("%w: %v", ctx.Err(), lastErr)
+}
+
+// withDatabase подменяет dbname в DSN формата key=value
+func withDatabase(dsn string, name string) string {
+	fields := strings.Fields(dsn)
+	result := make([]string, 0, len(fields)+1)
+	for _, field := range fields {
+		if strings.HasPrefix(field, "dbname=") {
+			continue
+		}
+
+		result = append(result, field)
+	}
+
+	return strings.Join(append(result, "dbname="+name), " ")
+}
@@ -0,0 +1,151 @@
+package pgtest
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	user     = "postgres"
+	password = "postgres"
+)
+
+func startFromDSN(_ testing.TB) (*server, error) {
+	dsn, ok := os.LookupEnv(envDSN)
+	if !ok || dsn == "" {
+		return nil, fmt.Errorf("%s is not set", envDSN)
+	}
+
+	return &server{dsn: dsn, stop: func() {}}, nil
+}
+
+// startLocal инициализирует кластер во временном каталоге; initdb не запускается от root
+func startLocal(tb testing.TB) (*server, error) {
+	if os.Geteuid() == 0 {
+		return nil, errors.New("initdb refuses to run as root")
+	}
+
+	initdb, err := lookBin("initdb")
+	if err != nil {
+		return nil, err
+	}
+	pgctl, err := lookBin("pg_ctl")
+	if err != nil {
+		return nil, err
+	}
+
+	dir := tb.TempDir()
+	data := filepath.Join(dir, "data")
+	pwfile := filepath.Join(dir, "pwfile")
+	if err := os.WriteFile(pwfile, []byte(password), 0o600); err != nil {
+		return nil, err
+	}
+
+	if out, err := exec.Command(initdb, "-D", data, "-U", user, "--pwfile", pwfile, "-A", "md5").CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("initdb: %w: %s", err, out)
+	}
+
+	port, err := freePort()
+	if err != nil {
+		return nil, err
+	}
+
+	options := fmt.Sprintf("-p %d -k %s -c listen_addresses=127.0.0.1 -c fsync=off", port, dir)
+	if out, err := exec.Command(pgctl, "-D", data, "-o", options, "-w", "start").CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("pg_ctl start: %w: %s", err, out)
+	}
+
+	return &server{
+		dsn: dsn("127.0.0.1", port),
+		stop: func() {
+			_ = exec.Command(pgctl, "-D", data, "-m", "immediate", "stop").Run()
+		},
+	}, nil
+}
+
+func startDocker(_ testing.TB) (*server, error) {
+	docker, err := exec.LookPath("docker")
+	if err != nil {
+		return nil, err
+	}
+
+	image := os.Getenv(envImage)
+	if image == "" {
+		image = defaultImage
+	}
+
+	out, err := exec.Command(
+		docker, "run", "-d", "--rm",
+		"-e", "POSTGRES_USER="+user,
+		"-e", "POSTGRES_PASSWORD="+password,
+		"-p", "127.0.0.1::5432",
+		image,
+		"-c", "fsync=off",
+	).Output()
+	if err != nil {
+		return nil, fmt.Errorf("docker run: %w", err)
+	}
+	id := strings.TrimSpace(string(out))
+	stop := func() { _ = exec.Command(docker, "rm", "-f", id).Run() }
+
+	out, err = exec.Command(docker, "port", id, "5432/tcp").Output()
+	if err != nil {
+		stop()
+		return nil, fmt.Errorf("docker port: %w", err)
+	}
+
+	// Формат вывода: 127.0.0.1:49153
+	mapping := strings.TrimSpace(strings.Split(string(out), "\n")[0])
+	_, portStr, err := net.SplitHostPort(mapping)
+	if err != nil {
+		stop()
+		return nil, fmt.Errorf("unexpected docker port output '%s': %w", mapping, err)
+	}
+
+	var port int
+	if _, err := fmt.Sscanf(portStr, "%d", &port); err != nil {
+		stop()
+		return nil, err
+	}
+
+	return &server{dsn: dsn("127.0.0.1", port), stop: stop}, nil
+}
+
+func lookBin(name string) (string, error) {
+	if dir, ok := os.LookupEnv(envBin); ok {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err != nil {
+			return "", err
+		}
+
+		return path, nil
+	}
+
+	return exec.LookPath(name)
+}
+
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+func dsn(host string, port int) string {
+	return fmt.Sprintf(
+		"user=%s password=%s host=%s port=%d dbname=postgres sslmode=disable",
+		user,
+		password,
+		host,
+		port,
+	)
+}
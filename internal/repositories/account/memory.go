@@ -0,0 +1,83 @@
+package account
+
+import (
+	"context"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// MemoryRepository коды привязки и слияния пользователей в памяти процесса для демо-режима
+	MemoryRepository struct {
+		mu     sync.Mutex
+		codes  map[string]*memoryLinkCode
+		merges map[uuid.UUID]uuid.UUID
+	}
+
+	memoryLinkCode struct {
+		model.LinkCode
+		used bool
+	}
+)
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		codes:  make(map[string]*memoryLinkCode),
+		merges: make(map[uuid.UUID]uuid.UUID),
+	}
+}
+
+func (r *MemoryRepository) InsertLinkCode(_ context.Context, code model.LinkCode) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.codes[code.Code]; exists {
+		return false, nil
+	}
+
+	r.codes[code.Code] = &memoryLinkCode{LinkCode: code}
+	return true, nil
+}
+
+func (r *MemoryRepository) UseLinkCode(_ context.Context, code string, _ uuid.UUID, now time.Time) (model.LinkCode, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.codes[code]
+	if !ok || stored.used || !stored.ExpiresAt.After(now) {
+		return model.LinkCode{}, contracts.ErrLinkCodeNotFound
+	}
+
+	stored.used = true
+	return stored.LinkCode, nil
+}
+
+func (r *MemoryRepository) InsertMerge(_ context.Context, from uuid.UUID, to uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for source, target := range r.merges {
+		if target == from {
+			r.merges[source] = to
+		}
+	}
+	r.merges[from] = to
+
+	return nil
+}
+
+func (r *MemoryRepository) GetMergedInto(_ context.Context, from uuid.UUID) (uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	to, ok := r.merges[from]
+	if !ok {
+		return uuid.Nil, contracts.ErrUserMergeNotFound
+	}
+
+	return to, nil
+}
@@ -0,0 +1,120 @@
+package account
+
+import (
+	"context"
+	"database/sql"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"errors"
+	"time"
+
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type (
+	DefaultRepository struct {
+		sqlx *sqlx.DB
+		tx   *trmsqlx.CtxGetter
+	}
+
+	sqlxLinkCode struct {
+		Code      string    `db:"code"`
+		UserID    uuid.UUID `db:"user_id"`
+		CreatedAt time.Time `db:"created_at"`
+		ExpiresAt time.Time `db:"expires_at"`
+	}
+)
+
+func NewRepository(sqlx *sqlx.DB, tx *trmsqlx.CtxGetter) *DefaultRepository {
+	return &DefaultRepository{sqlx: sqlx, tx: tx}
+}
+
+func (r *DefaultRepository) db(ctx context.Context) trmsqlx.Tr {
+	return r.tx.DefaultTrOrDB(ctx, r.sqlx)
+}
+
+// InsertLinkCode сохраняет код, false — такой код уже выдан
+func (r *DefaultRepository) InsertLinkCode(ctx context.Context, code model.LinkCode) (bool, error) {
+	const query = `
+		insert into easy_quizy_link_code (code, user_id, created_at, expires_at)
+		values ($1, $2, $3, $4)
+		on conflict (code) do nothing
+	`
+
+	result, err := r.db(ctx).ExecContext(ctx, query, code.Code, code.UserID, code.CreatedAt, code.ExpiresAt)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// UseLinkCode гасит действующий код, повторно тот же код не сработает
+func (r *DefaultRepository) UseLinkCode(ctx context.Context, code string, usedBy uuid.UUID, now time.Time) (model.LinkCode, error) {
+	const query = `
+		update easy_quizy_link_code
+		set used_at = $3, used_by = $2
+		where code = $1 and used_at is null and expires_at > $3
+		returning code, user_id, created_at, expires_at
+	`
+
+	var result sqlxLinkCode
+	if err := r.db(ctx).GetContext(ctx, &result, query, code, usedBy, now); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.LinkCode{}, contracts.ErrLinkCodeNotFound
+		}
+
+		return model.LinkCode{}, err
+	}
+
+	return model.LinkCode{
+		Code:      result.Code,
+		UserID:    result.UserID,
+		CreatedAt: result.CreatedAt,
+		ExpiresAt: result.ExpiresAt,
+	}, nil
+}
+
+// InsertMerge запоминает, что from слит в to; ранее слитые в from теперь указывают на to
+func (r *DefaultRepository) InsertMerge(ctx context.Context, from uuid.UUID, to uuid.UUID) error {
+	const redirectQuery = `
+		update easy_quizy_user_merge
+		set to_user_id = $2
+		where to_user_id = $1
+	`
+
+	const query = `
+		insert into easy_quizy_user_merge (from_user_id, to_user_id)
+		values ($1, $2)
+		on conflict (from_user_id) do update set to_user_id = excluded.to_user_id, merged_at = NOW()
+	`
+
+	if _, err := r.db(ctx).ExecContext(ctx, redirectQuery, from, to); err != nil {
+		return err
+	}
+
+	_, err := r.db(ctx).ExecContext(ctx, query, from, to)
+	return err
+}
+
+func (r *DefaultRepository) GetMergedInto(ctx context.Context, from uuid.UUID) (uuid.UUID, error) {
+	const query = `
+		select to_user_id
+		from easy_quizy_user_merge
+		where from_user_id = $1
+	`
+
+	var result uuid.UUID
+	if err := r.db(ctx).GetContext(ctx, &result, query, from); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, contracts.ErrUserMergeNotFound
+		}
+
+		return uuid.Nil, err
+	}
+
+	return result, nil
+}
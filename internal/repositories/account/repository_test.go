@@ -0,0 +1,164 @@
+package account
+
+import (
+	"context"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"easy-quizy/internal/pgtest"
+	"errors"
+	"testing"
+	"time"
+
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+func newTestRepository(t *testing.T) (*DefaultRepository, *sqlx.DB) {
+	t.Helper()
+
+	db := pgtest.Start(t)
+	return NewRepository(db, trmsqlx.DefaultCtxGetter), db
+}
+
+func TestInsertLinkCode(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	tests := []struct {
+		name  string
+		codes []string
+		want  []bool
+	}{
+		{name: "new code", codes: []string{"ABCD2345"}, want: []bool{true}},
+		{name: "issued code", codes: []string{"ABCD2345", "ABCD2345"}, want: []bool{true, false}},
+		{name: "different codes", codes: []string{"ABCD2345", "ABCD2346"}, want: []bool{true, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pgtest.Truncate(t, db)
+
+			for i, code := range tt.codes {
+				inserted, err := repo.InsertLinkCode(ctx, model.LinkCode{
+					Code:      code,
+					UserID:    uuid.New(),
+					CreatedAt: now,
+					ExpiresAt: now.Add(time.Minute),
+				})
+				if err != nil {
+					t.Fatalf("InsertLinkCode: %v", err)
+				}
+				if inserted != tt.want[i] {
+					t.Errorf("InsertLinkCode #%d = %v, want %v", i, inserted, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestUseLinkCode(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	now := time.Now()
+	owner := uuid.New()
+
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		uses      int
+		code      string
+		wantErr   error
+	}{
+		{name: "valid code", expiresAt: now.Add(time.Minute), uses: 1, code: "ABCD2345"},
+		{name: "used code", expiresAt: now.Add(time.Minute), uses: 2, code: "ABCD2345", wantErr: contracts.ErrLinkCodeNotFound},
+		{name: "expired code", expiresAt: now.Add(-time.Second), uses: 1, code: "ABCD2345", wantErr: contracts.ErrLinkCodeNotFound},
+		{name: "unknown code", expiresAt: now.Add(time.Minute), uses: 1, code: "ZZZZ9999", wantErr: contracts.ErrLinkCodeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pgtest.Truncate(t, db)
+			if _, err := repo.InsertLinkCode(ctx, model.LinkCode{
+				Code:      "ABCD2345",
+				UserID:    owner,
+				CreatedAt: now.Add(-time.Minute),
+				ExpiresAt: tt.expiresAt,
+			}); err != nil {
+				t.Fatalf("InsertLinkCode: %v", err)
+			}
+
+			var (
+				code model.LinkCode
+				err  error
+			)
+			for range tt.uses {
+				code, err = repo.UseLinkCode(ctx, tt.code, uuid.New(), now)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UseLinkCode error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && code.UserID != owner {
+				t.Errorf("UseLinkCode owner = %s, want %s", code.UserID, owner)
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		name    string
+		merges  [][2]uuid.UUID
+		from    uuid.UUID
+		want    uuid.UUID
+		wantErr error
+	}{
+		{
+			name:    "not merged",
+			from:    first,
+			wantErr: contracts.ErrUserMergeNotFound,
+		},
+		{
+			name:   "merged",
+			merges: [][2]uuid.UUID{{first, second}},
+			from:   first,
+			want:   second,
+		},
+		{
+			name:   "chained merge points to the last owner",
+			merges: [][2]uuid.UUID{{first, second}, {second, third}},
+			from:   first,
+			want:   third,
+		},
+		{
+			name:   "merged again",
+			merges: [][2]uuid.UUID{{first, second}, {first, third}},
+			from:   first,
+			want:   third,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pgtest.Truncate(t, db)
+			for _, merge := range tt.merges {
+				if err := repo.InsertMerge(ctx, merge[0], merge[1]); err != nil {
+					t.Fatalf("InsertMerge: %v", err)
+				}
+			}
+
+			got, err := repo.GetMergedInto(ctx, tt.from)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetMergedInto error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("GetMergedInto = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
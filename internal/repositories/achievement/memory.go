@@ -0,0 +1,103 @@
+package achievement
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// MemoryRepository достижения в памяти процесса для демо-режима
+	MemoryRepository struct {
+		mu           sync.RWMutex
+		achievements []model.Achievement
+	}
+)
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{}
+}
+
+func (r *MemoryRepository) InsertAchievement(_ context.Context, achievement model.Achievement) (model.Achievement, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.achievements {
+		if existing.UserID == achievement.UserID && existing.Code == achievement.Code {
+			return model.Achievement{}, false, nil
+		}
+	}
+
+	stored := model.Achievement{
+		AchievementDefinition: model.AchievementDefinition{Code: achievement.Code},
+		UserID:                achievement.UserID,
+		GameID:                achievement.GameID,
+		SessionID:             achievement.SessionID,
+		UnlockedAt:            achievement.UnlockedAt,
+	}
+	r.achievements = append(r.achievements, stored)
+
+	return stored, true, nil
+}
+
+func (r *MemoryRepository) GetUserAchievements(_ context.Context, userID uuid.UUID) ([]model.Achievement, error) {
+	return r.filter(func(achievement model.Achievement) bool {
+		return achievement.UserID == userID
+	}), nil
+}
+
+func (r *MemoryRepository) GetSessionAchievements(_ context.Context, sessionID uuid.UUID) ([]model.Achievement, error) {
+	return r.filter(func(achievement model.Achievement) bool {
+		return achievement.SessionID != nil && *achievement.SessionID == sessionID
+	}), nil
+}
+
+func (r *MemoryRepository) filter(match func(achievement model.Achievement) bool) []model.Achievement {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]model.Achievement, 0)
+	for _, achievement := range r.achievements {
+		if match(achievement) {
+			result = append(result, achievement)
+		}
+	}
+
+	return result
+}
+
+func (r *MemoryRepository) ReassignAchievements(_ context.Context, from uuid.UUID, to uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := make(map[model.AchievementCode]int, len(r.achievements))
+	for i, achievement := range r.achievements {
+		if achievement.UserID == to {
+			kept[achievement.Code] = i
+		}
+	}
+
+	result := r.achievements[:0]
+	for _, achievement := range r.achievements {
+		if achievement.UserID != from {
+			result = append(result, achievement)
+			continue
+		}
+
+		achievement.UserID = to
+		if i, ok := kept[achievement.Code]; ok {
+			if achievement.UnlockedAt.Before(r.achievements[i].UnlockedAt) {
+				r.achievements[i] = achievement
+			}
+			continue
+		}
+
+		kept[achievement.Code] = len(result)
+		result = append(result, achievement)
+	}
+	r.achievements = result
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package achievement
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"time"
+
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type (
+	DefaultRepository struct {
+		sqlx *sqlx.DB
+		tx   *trmsqlx.CtxGetter
+	}
+
+	sqlxAchievement struct {
+		UserID     uuid.UUID  `db:"user_id"`
+		Code       string     `db:"code"`
+		GameID     *uuid.UUID `db:"game_id"`
+		SessionID  *uuid.UUID `db:"session_id"`
+		UnlockedAt time.Time  `db:"unlocked_at"`
+	}
+)
+
+func NewRepository(sqlx *sqlx.DB, tx *trmsqlx.CtxGetter) *DefaultRepository {
+	return &DefaultRepository{sqlx: sqlx, tx: tx}
+}
+
+func (r *DefaultRepository) db(ctx context.Context) trmsqlx.Tr {
+	return r.tx.DefaultTrOrDB(ctx, r.sqlx)
+}
+
+// InsertAchievement выдает достижение, false — игрок уже получил его раньше
+func (r *DefaultRepository) InsertAchievement(ctx context.Context, achievement model.Achievement) (model.Achievement, bool, error) {
+	const query = `
+		insert into easy_quizy_user_achievement (user_id, code, game_id, session_id, unlocked_at)
+		values ($1, $2, $3, $4, $5)
+		on conflict (user_id, code) do nothing
+		returning user_id, code, game_id, session_id, unlocked_at
+	`
+
+	var rows []sqlxAchievement
+	err := r.db(ctx).SelectContext(ctx, &rows, query,
+		achievement.UserID, achievement.Code, achievement.GameID, achievement.SessionID, achievement.UnlockedAt,
+	)
+	if err != nil {
+		return model.Achievement{}, false, err
+	}
+	if len(rows) == 0 {
+		return model.Achievement{}, false, nil
+	}
+
+	return convertToAchievement(rows[0]), true, nil
+}
+
+func (r *DefaultRepository) GetUserAchievements(ctx context.Context, userID uuid.UUID) ([]model.Achievement, error) {
+	const query = `
+		select user_id, code, game_id, session_id, unlocked_at
+		from easy_quizy_user_achievement
+		where user_id = $1
+		order by unlocked_at, code
+	`
+
+	return r.selectAchievements(ctx, query, userID)
+}
+
+// GetSessionAchievements достижения, полученные в попытке
+func (r *DefaultRepository) GetSessionAchievements(ctx context.Context, sessionID uuid.UUID) ([]model.Achievement, error) {
+	const query = `
+		select user_id, code, game_id, session_id, unlocked_at
+		from easy_quizy_user_achievement
+		where session_id = $1
+		order by unlocked_at, code
+	`
+
+	return r.selectAchievements(ctx, query, sessionID)
+}
+
+func (r *DefaultRepository) selectAchievements(ctx context.Context, query string, args ...any) ([]model.Achievement, error) {
+	var rows []sqlxAchievement
+	if err := r.db(ctx).SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+
+	result := make([]model.Achievement, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, convertToAchievement(row))
+	}
+
+	return result, nil
+}
+
+// ReassignAchievements переносит достижения пользователя from на пользователя to,
+// у достижения, полученного обоими, остается более раннее получение
+func (r *DefaultRepository) ReassignAchievements(ctx context.Context, from uuid.UUID, to uuid.UUID) error {
+	const query = `
+		with moved as (
+			delete from easy_quizy_user_achievement
+			where user_id = $1
+			returning code, game_id, session_id, unlocked_at
+		)
+		insert into easy_quizy_user_achievement (user_id, code, game_id, session_id, unlocked_at)
+		select $2, code, game_id, session_id, unlocked_at
+		from moved
+		on conflict (user_id, code) do update
+		set game_id = excluded.game_id, session_id = excluded.session_id, unlocked_at = excluded.unlocked_at
+		where excluded.unlocked_at < easy_quizy_user_achievement.unlocked_at
+	`
+
+	_, err := r.db(ctx).ExecContext(ctx, query, from, to)
+	return err
+}
+
+// convertToAchievement заполняет только код, описание подставляет usecase по справочнику правил
+func convertToAchievement(in sqlxAchievement) model.Achievement {
+	return model.Achievement{
+		AchievementDefinition: model.AchievementDefinition{Code: model.AchievementCode(in.Code)},
+		UserID:                in.UserID,
+		GameID:                in.GameID,
+		SessionID:             in.SessionID,
+		UnlockedAt:            in.UnlockedAt,
+	}
+}
@@ -0,0 +1,198 @@
+package achievement
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"easy-quizy/internal/pgtest"
+	"testing"
+	"time"
+
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+func newTestRepository(t *testing.T) (*DefaultRepository, *sqlx.DB) {
+	t.Helper()
+
+	db := pgtest.Start(t)
+	return NewRepository(db, trmsqlx.DefaultCtxGetter), db
+}
+
+func achievement(userID uuid.UUID, code model.AchievementCode, sessionID uuid.UUID, unlockedAt time.Time) model.Achievement {
+	return model.Achievement{
+		AchievementDefinition: model.AchievementDefinition{Code: code},
+		UserID:                userID,
+		SessionID:             &sessionID,
+		UnlockedAt:            unlockedAt,
+	}
+}
+
+func TestInsertAchievement(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	userID, sessionID := uuid.New(), uuid.New()
+	now := time.Now().Truncate(time.Second)
+
+	tests := []struct {
+		name         string
+		achievements []model.Achievement
+		want         []bool
+	}{
+		{
+			name:         "first unlock",
+			achievements: []model.Achievement{achievement(userID, model.AchievementPerfectScore, sessionID, now)},
+			want:         []bool{true},
+		},
+		{
+			name: "repeated unlock",
+			achievements: []model.Achievement{
+				achievement(userID, model.AchievementPerfectScore, sessionID, now),
+				achievement(userID, model.AchievementPerfectScore, uuid.New(), now.Add(time.Minute)),
+			},
+			want: []bool{true, false},
+		},
+		{
+			name: "different achievements",
+			achievements: []model.Achievement{
+				achievement(userID, model.AchievementPerfectScore, sessionID, now),
+				achievement(userID, model.AchievementFirstInChat, sessionID, now),
+			},
+			want: []bool{true, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pgtest.Truncate(t, db)
+
+			for i, item := range tt.achievements {
+				got, inserted, err := repo.InsertAchievement(ctx, item)
+				if err != nil {
+					t.Fatalf("InsertAchievement: %v", err)
+				}
+				if inserted != tt.want[i] {
+					t.Errorf("InsertAchievement #%d inserted = %v, want %v", i, inserted, tt.want[i])
+				}
+				if inserted && got.Code != item.Code {
+					t.Errorf("InsertAchievement #%d code = %s, want %s", i, got.Code, item.Code)
+				}
+			}
+		})
+	}
+}
+
+func TestGetAchievements(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	userID, otherID := uuid.New(), uuid.New()
+	firstSession, secondSession := uuid.New(), uuid.New()
+	now := time.Now().Truncate(time.Second)
+
+	pgtest.Truncate(t, db)
+	for _, item := range []model.Achievement{
+		achievement(userID, model.AchievementFirstInChat, secondSession, now.Add(time.Minute)),
+		achievement(userID, model.AchievementPerfectScore, firstSession, now),
+		achievement(otherID, model.AchievementPerfectScore, uuid.New(), now),
+	} {
+		if _, _, err := repo.InsertAchievement(ctx, item); err != nil {
+			t.Fatalf("InsertAchievement: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		get  func() ([]model.Achievement, error)
+		want []model.AchievementCode
+	}{
+		{
+			name: "user achievements by unlock time",
+			get:  func() ([]model.Achievement, error) { return repo.GetUserAchievements(ctx, userID) },
+			want: []model.AchievementCode{model.AchievementPerfectScore, model.AchievementFirstInChat},
+		},
+		{
+			name: "session achievements",
+			get:  func() ([]model.Achievement, error) { return repo.GetSessionAchievements(ctx, secondSession) },
+			want: []model.AchievementCode{model.AchievementFirstInChat},
+		},
+		{
+			name: "unknown user",
+			get:  func() ([]model.Achievement, error) { return repo.GetUserAchievements(ctx, uuid.New()) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.get()
+			if err != nil {
+				t.Fatalf("get: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want codes %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i].Code != tt.want[i] {
+					t.Errorf("achievement #%d = %s, want %s", i, got[i].Code, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReassignAchievements(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	from, to := uuid.New(), uuid.New()
+	fromSession, toSession := uuid.New(), uuid.New()
+	now := time.Now().Truncate(time.Second)
+
+	tests := []struct {
+		name        string
+		fromAt      time.Time
+		toAt        time.Time
+		wantSession uuid.UUID
+	}{
+		{name: "earlier unlock of the source wins", fromAt: now, toAt: now.Add(time.Hour), wantSession: fromSession},
+		{name: "earlier unlock of the target stays", fromAt: now.Add(time.Hour), toAt: now, wantSession: toSession},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pgtest.Truncate(t, db)
+			for _, item := range []model.Achievement{
+				achievement(from, model.AchievementPerfectScore, fromSession, tt.fromAt),
+				achievement(from, model.AchievementFirstInChat, fromSession, tt.fromAt),
+				achievement(to, model.AchievementPerfectScore, toSession, tt.toAt),
+			} {
+				if _, _, err := repo.InsertAchievement(ctx, item); err != nil {
+					t.Fatalf("InsertAchievement: %v", err)
+				}
+			}
+
+			if err := repo.ReassignAchievements(ctx, from, to); err != nil {
+				t.Fatalf("ReassignAchievements: %v", err)
+			}
+
+			got, err := repo.GetUserAchievements(ctx, to)
+			if err != nil {
+				t.Fatalf("GetUserAchievements: %v", err)
+			}
+			if len(got) != 2 {
+				t.Fatalf("target achievements = %v, want 2", got)
+			}
+			for _, item := range got {
+				if item.Code == model.AchievementPerfectScore && *item.SessionID != tt.wantSession {
+					t.Errorf("perfect score session = %s, want %s", *item.SessionID, tt.wantSession)
+				}
+			}
+
+			left, err := repo.GetUserAchievements(ctx, from)
+			if err != nil {
+				t.Fatalf("GetUserAchievements: %v", err)
+			}
+			if len(left) != 0 {
+				t.Errorf("source achievements = %v, want none", left)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package anticheat
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// MemoryRepository пометки попыток в памяти процесса для демо-режима
+	MemoryRepository struct {
+		mu     sync.RWMutex
+		nextID int64
+		flags  []model.SessionFlag
+	}
+)
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{}
+}
+
+func (r *MemoryRepository) InsertFlags(_ context.Context, flags []model.SessionFlag) ([]model.SessionFlag, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]model.SessionFlag, 0, len(flags))
+	for _, flag := range flags {
+		exists := slices.ContainsFunc(r.flags, func(existing model.SessionFlag) bool {
+			return existing.SessionID == flag.SessionID && existing.Reason == flag.Reason
+		})
+		if exists {
+			continue
+		}
+
+		r.nextID++
+		flag.ID = r.nextID
+		flag.CreatedAt = time.Now()
+		r.flags = append(r.flags, flag)
+		result = append(result, flag)
+	}
+
+	return result, nil
+}
+
+func (r *MemoryRepository) GetFlaggedSessions(_ context.Context, userIDs []uuid.UUID) ([]uuid.UUID, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []uuid.UUID
+	for _, flag := range r.flags {
+		if slices.Contains(userIDs, flag.UserID) && !slices.Contains(result, flag.SessionID) {
+			result = append(result, flag.SessionID)
+		}
+	}
+
+	return result, nil
+}
+
+func (r *MemoryRepository) GetFlags(_ context.Context, page model.Page) (model.SessionFlags, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items := make([]model.SessionFlag, 0, len(r.flags))
+	for i := len(r.flags) - 1; i >= 0; i-- {
+		items = append(items, r.flags[i])
+	}
+
+	result := model.SessionFlags{Items: []model.SessionFlag{}, Total: int64(len(items))}
+	if page.Offset < int64(len(items)) {
+		end := min(page.Offset+page.Limit, int64(len(items)))
+		result.Items = items[page.Offset:end]
+	}
+
+	return result, nil
+}
+
+func (r *MemoryRepository) ReassignFlags(_ context.Context, from uuid.UUID, to uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.flags {
+		if r.flags[i].UserID == from {
+			r.flags[i].UserID = to
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package anticheat
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"time"
+
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type (
+	DefaultRepository struct {
+		sqlx *sqlx.DB
+		tx   *trmsqlx.CtxGetter
+	}
+
+	sqlxFlag struct {
+		ID        int64     `db:"id"`
+		SessionID uuid.UUID `db:"session_id"`
+		UserID    uuid.UUID `db:"user_id"`
+		GameID    uuid.UUID `db:"game_id"`
+		Reason    string    `db:"reason"`
+		Details   string    `db:"details"`
+		CreatedAt time.Time `db:"created_at"`
+	}
+)
+
+func NewRepository(sqlx *sqlx.DB, tx *trmsqlx.CtxGetter) *DefaultRepository {
+	return &DefaultRepository{sqlx: sqlx, tx: tx}
+}
+
+func (r *DefaultRepository) db(ctx context.Context) trmsqlx.Tr {
+	return r.tx.DefaultTrOrDB(ctx, r.sqlx)
+}
+
+// InsertFlags помечает попытки и возвращает новые пометки, повторная пометка с той же причиной пропускается
+func (r *DefaultRepository) InsertFlags(ctx context.Context, flags []model.SessionFlag) ([]model.SessionFlag, error) {
+	const query = `
+		insert into easy_quizy_session_flag (session_id, user_id, game_id, reason, details)
+		values ($1, $2, $3, $4, $5)
+		on conflict (session_id, reason) do nothing
+		returning id, session_id, user_id, game_id, reason, details, created_at
+	`
+
+	result := make([]model.SessionFlag, 0, len(flags))
+	for _, flag := range flags {
+		var rows []sqlxFlag
+		err := r.db(ctx).SelectContext(ctx, &rows, query, flag.SessionID, flag.UserID, flag.GameID, flag.Reason, flag.Details)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			result = append(result, convertToFlag(row))
+		}
+	}
+
+	return result, nil
+}
+
+// GetFlaggedSessions помеченные попытки игроков
+func (r *DefaultRepository) GetFlaggedSessions(ctx context.Context, userIDs []uuid.UUID) ([]uuid.UUID, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	const query = `
+		select distinct session_id
+		from easy_quizy_session_flag
+		where user_id = any($1)
+	`
+
+	var result []uuid.UUID
+	if err := r.db(ctx).SelectContext(ctx, &result, query, pq.Array(userIDs)); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetFlags пометки для модерации, новые сверху
+func (r *DefaultRepository) GetFlags(ctx context.Context, page model.Page) (model.SessionFlags, error) {
+	const (
+		query = `
+			select id, session_id, user_id, game_id, reason, details, created_at
+			from easy_quizy_session_flag
+			order by id desc
+			limit $1 offset $2
+		`
+		countQuery = `select count(*) from easy_quizy_session_flag`
+	)
+
+	var rows []sqlxFlag
+	if err := r.db(ctx).SelectContext(ctx, &rows, query, page.Limit, page.Offset); err != nil {
+		return model.SessionFlags{}, err
+	}
+
+	var total int64
+	if err := r.db(ctx).GetContext(ctx, &total, countQuery); err != nil {
+		return model.SessionFlags{}, err
+	}
+
+	items := make([]model.SessionFlag, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, convertToFlag(row))
+	}
+
+	return model.SessionFlags{Items: items, Total: total}, nil
+}
+
+// ReassignFlags переносит пометки пользователя from на пользователя to
+func (r *DefaultRepository) ReassignFlags(ctx context.Context, from uuid.UUID, to uuid.UUID) error {
+	const query = `
+		update easy_quizy_session_flag
+		set user_id = $2
+		where user_id = $1
+	`
+
+	_, err := r.db(ctx).ExecContext(ctx, query, from, to)
+	return err
+}
+
+func convertToFlag(in sqlxFlag) model.SessionFlag {
+	return model.SessionFlag{
+		ID:        in.ID,
+		SessionID: in.SessionID,
+		UserID:    in.UserID,
+		GameID:    in.GameID,
+		Reason:    model.CheatReason(in.Reason),
+		Details:   in.Details,
+		CreatedAt: in.CreatedAt,
+	}
+}
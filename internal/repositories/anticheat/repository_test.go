@@ -0,0 +1,224 @@
+package anticheat
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"easy-quizy/internal/pgtest"
+	"slices"
+	"testing"
+
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type testFixture struct {
+	gameID   uuid.UUID
+	alice    uuid.UUID
+	bob      uuid.UUID
+	sessions map[uuid.UUID]uuid.UUID
+}
+
+func newTestRepository(t *testing.T) (*DefaultRepository, *sqlx.DB) {
+	t.Helper()
+
+	db := pgtest.Start(t)
+	return NewRepository(db, trmsqlx.DefaultCtxGetter), db
+}
+
+// newFixture очищает базу и начинает по попытке игрокам alice и bob
+func newFixture(t *testing.T, db *sqlx.DB) testFixture {
+	t.Helper()
+
+	pgtest.Truncate(t, db)
+	f := testFixture{
+		gameID:   pgtest.InsertGame(t, db, model.GameTypeClassic, 3),
+		alice:    uuid.New(),
+		bob:      uuid.New(),
+		sessions: make(map[uuid.UUID]uuid.UUID),
+	}
+	for _, playerID := range []uuid.UUID{f.alice, f.bob} {
+		f.sessions[playerID] = pgtest.InsertSession(t, db, f.gameID, playerID)
+	}
+
+	return f
+}
+
+func (f testFixture) flag(playerID uuid.UUID, reason model.CheatReason) model.SessionFlag {
+	return model.SessionFlag{
+		SessionID: f.sessions[playerID],
+		UserID:    playerID,
+		GameID:    f.gameID,
+		Reason:    reason,
+		Details:   "details",
+	}
+}
+
+func TestInsertFlags(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		batches func(f testFixture) [][]model.SessionFlag
+		want    []int
+	}{
+		{
+			name: "new flags",
+			batches: func(f testFixture) [][]model.SessionFlag {
+				return [][]model.SessionFlag{{f.flag(f.alice, model.CheatFastAnswer), f.flag(f.alice, model.CheatAnswerRate)}}
+			},
+			want: []int{2},
+		},
+		{
+			name: "same reason is flagged once per session",
+			batches: func(f testFixture) [][]model.SessionFlag {
+				return [][]model.SessionFlag{
+					{f.flag(f.alice, model.CheatFastAnswer)},
+					{f.flag(f.alice, model.CheatFastAnswer), f.flag(f.bob, model.CheatFastAnswer)},
+				}
+			},
+			want: []int{1, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t, db)
+
+			for i, batch := range tt.batches(f) {
+				inserted, err := repo.InsertFlags(ctx, batch)
+				if err != nil {
+					t.Fatalf("InsertFlags: %v", err)
+				}
+				if len(inserted) != tt.want[i] {
+					t.Errorf("InsertFlags #%d inserted %d flags, want %d", i, len(inserted), tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGetFlags(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	f := newFixture(t, db)
+
+	if _, err := repo.InsertFlags(ctx, []model.SessionFlag{
+		f.flag(f.alice, model.CheatFastAnswer),
+		f.flag(f.alice, model.CheatAnswerRate),
+		f.flag(f.bob, model.CheatSharedAnswers),
+	}); err != nil {
+		t.Fatalf("InsertFlags: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		page        model.Page
+		wantReasons []model.CheatReason
+	}{
+		{
+			name:        "newest first",
+			page:        model.Page{Limit: 10},
+			wantReasons: []model.CheatReason{model.CheatSharedAnswers, model.CheatAnswerRate, model.CheatFastAnswer},
+		},
+		{
+			name:        "second page",
+			page:        model.Page{Limit: 2, Offset: 2},
+			wantReasons: []model.CheatReason{model.CheatFastAnswer},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.GetFlags(ctx, tt.page)
+			if err != nil {
+				t.Fatalf("GetFlags: %v", err)
+			}
+			if got.Total != 3 {
+				t.Errorf("Total = %d, want 3", got.Total)
+			}
+
+			reasons := make([]model.CheatReason, 0, len(got.Items))
+			for _, item := range got.Items {
+				reasons = append(reasons, item.Reason)
+			}
+			if !slices.Equal(reasons, tt.wantReasons) {
+				t.Errorf("reasons = %v, want %v", reasons, tt.wantReasons)
+			}
+		})
+	}
+}
+
+func TestGetFlaggedSessions(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	f := newFixture(t, db)
+
+	if _, err := repo.InsertFlags(ctx, []model.SessionFlag{
+		f.flag(f.alice, model.CheatFastAnswer),
+		f.flag(f.alice, model.CheatAnswerRate),
+	}); err != nil {
+		t.Fatalf("InsertFlags: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		userIDs []uuid.UUID
+		want    []uuid.UUID
+	}{
+		{name: "flagged player", userIDs: []uuid.UUID{f.alice, f.bob}, want: []uuid.UUID{f.sessions[f.alice]}},
+		{name: "clean player", userIDs: []uuid.UUID{f.bob}},
+		{name: "no players"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.GetFlaggedSessions(ctx, tt.userIDs)
+			if err != nil {
+				t.Fatalf("GetFlaggedSessions: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("GetFlaggedSessions = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReassignFlags(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	f := newFixture(t, db)
+
+	if _, err := repo.InsertFlags(ctx, []model.SessionFlag{
+		f.flag(f.alice, model.CheatFastAnswer),
+		f.flag(f.bob, model.CheatFastAnswer),
+	}); err != nil {
+		t.Fatalf("InsertFlags: %v", err)
+	}
+
+	if err := repo.ReassignFlags(ctx, f.alice, f.bob); err != nil {
+		t.Fatalf("ReassignFlags: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		userID    uuid.UUID
+		wantCount int
+	}{
+		{name: "target owns both flags", userID: f.bob, wantCount: 2},
+		{name: "source has no flags", userID: f.alice, wantCount: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.GetFlaggedSessions(ctx, []uuid.UUID{tt.userID})
+			if err != nil {
+				t.Fatalf("GetFlaggedSessions: %v", err)
+			}
+			if len(got) != tt.wantCount {
+				t.Errorf("GetFlaggedSessions = %v, want %d sessions", got, tt.wantCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package feedback
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// MemoryRepository хранит оценки и жалобы в памяти процесса, для тестов и демо-режима
+	MemoryRepository struct {
+		mu      sync.RWMutex
+		ratings map[ratingKey]model.GameRating
+		flags   map[flagKey]model.QuestionFlag
+	}
+
+	ratingKey struct {
+		gameID   uuid.UUID
+		playerID uuid.UUID
+	}
+
+	flagKey struct {
+		gameID     uuid.UUID
+		playerID   uuid.UUID
+		questionID int64
+	}
+
+	questionKey struct {
+		gameID     uuid.UUID
+		questionID int64
+	}
+)
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		ratings: make(map[ratingKey]model.GameRating),
+		flags:   make(map[flagKey]model.QuestionFlag),
+	}
+}
+
+func (r *MemoryRepository) UpsertGameRating(_ context.Context, rating model.GameRating) (model.GameRating, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rating.UpdatedAt = time.Now()
+	r.ratings[ratingKey{gameID: rating.GameID, playerID: rating.PlayerID}] = rating
+
+	return rating, nil
+}
+
+func (r *MemoryRepository) UpsertQuestionFlag(_ context.Context, flag model.QuestionFlag) (model.QuestionFlag, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	flag.UpdatedAt = time.Now()
+	r.flags[flagKey{gameID: flag.GameID, playerID: flag.PlayerID, questionID: flag.QuestionID}] = flag
+
+	return flag, nil
+}
+
+func (r *MemoryRepository) GetFlaggedQuestions(_ context.Context, filter model.FlaggedQuestionsFilter) (model.FlaggedQuestions, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	flags := make([]model.QuestionFlag, 0, len(r.flags))
+	for _, flag := range r.flags {
+		if filter.GameID == nil || flag.GameID == *filter.GameID {
+			flags = append(flags, flag)
+		}
+	}
+	sort.Slice(flags, func(i, j int) bool {
+		return flags[i].UpdatedAt.After(flags[j].UpdatedAt)
+	})
+
+	grouped := make(map[questionKey]*model.FlaggedQuestion)
+	var items []*model.FlaggedQuestion
+	for _, flag := range flags {
+		key := questionKey{gameID: flag.GameID, questionID: flag.QuestionID}
+		item, ok := grouped[key]
+		if !ok {
+			item = &model.FlaggedQuestion{
+				GameID:        flag.GameID,
+				QuestionID:    flag.QuestionID,
+				LastFlaggedAt: flag.UpdatedAt,
+			}
+			grouped[key] = item
+			items = append(items, item)
+		}
+
+		item.Total++
+		switch flag.Reason {
+		case model.FlagReasonWrong:
+			item.Wrong++
+		case model.FlagReasonAmbiguous:
+			item.Ambiguous++
+		}
+		if flag.Comment != nil && len(item.RecentComments) < recentCommentsLimit {
+			item.RecentComments = append(item.RecentComments, *flag.Comment)
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Total > items[j].Total
+	})
+
+	result := model.FlaggedQuestions{Total: int64(len(items))}
+	if filter.Page.Offset < int64(len(items)) {
+		end := min(filter.Page.Offset+filter.Page.Limit, int64(len(items)))
+		for _, item := range items[filter.Page.Offset:end] {
+			result.Items = append(result.Items, *item)
+		}
+	}
+
+	return result, nil
+}
+
+func (r *MemoryRepository) GetRatingSummaries(_ context.Context, page model.Page) (model.GameRatingSummaries, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	grouped := make(map[uuid.UUID]*model.GameRatingSummary)
+	var items []*model.GameRatingSummary
+	for _, rating := range r.ratings {
+		item, ok := grouped[rating.GameID]
+		if !ok {
+			item = &model.GameRatingSummary{GameID: rating.GameID}
+			grouped[rating.GameID] = item
+			items = append(items, item)
+		}
+
+		item.Average = (item.Average*float64(item.Count) + float64(rating.Rating)) / float64(item.Count+1)
+		item.Count++
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Average != items[j].Average {
+			return items[i].Average > items[j].Average
+		}
+
+		return items[i].Count > items[j].Count
+	})
+
+	result := model.GameRatingSummaries{Total: int64(len(items))}
+	if page.Offset < int64(len(items)) {
+		end := min(page.Offset+page.Limit, int64(len(items)))
+		for _, item := range items[page.Offset:end] {
+			result.Items = append(result.Items, *item)
+		}
+	}
+
+	return result, nil
+}
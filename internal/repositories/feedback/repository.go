@@ -0,0 +1,217 @@
+package feedback
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"easy-quizy/pkg/structs/collections/slices"
+	"time"
+
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// recentCommentsLimit сколько последних комментариев показывать по вопросу
+const recentCommentsLimit = 5
+
+type (
+	DefaultRepository struct {
+		sqlx *sqlx.DB
+		tx   *trmsqlx.CtxGetter
+	}
+
+	sqlxGameRating struct {
+		GameID    uuid.UUID `db:"game_id"`
+		PlayerID  uuid.UUID `db:"player_id"`
+		Rating    int64     `db:"rating"`
+		UpdatedAt time.Time `db:"updated_at"`
+	}
+
+	sqlxQuestionFlag struct {
+		GameID     uuid.UUID `db:"game_id"`
+		PlayerID   uuid.UUID `db:"player_id"`
+		QuestionID int64     `db:"question_id"`
+		Reason     string    `db:"reason"`
+		Comment    *string   `db:"comment"`
+		UpdatedAt  time.Time `db:"updated_at"`
+	}
+
+	sqlxFlaggedQuestion struct {
+		GameID         uuid.UUID      `db:"game_id"`
+		QuestionID     int64          `db:"question_id"`
+		Total          int64          `db:"total"`
+		Wrong          int64          `db:"wrong"`
+		Ambiguous      int64          `db:"ambiguous"`
+		LastFlaggedAt  time.Time      `db:"last_flagged_at"`
+		RecentComments pq.StringArray `db:"recent_comments"`
+	}
+
+	sqlxGameRatingSummary struct {
+		GameID  uuid.UUID `db:"game_id"`
+		Average float64   `db:"average"`
+		Count   int64     `db:"count"`
+	}
+)
+
+func NewRepository(sqlx *sqlx.DB, tx *trmsqlx.CtxGetter) *DefaultRepository {
+	return &DefaultRepository{sqlx: sqlx, tx: tx}
+}
+
+func (r *DefaultRepository) db(ctx context.Context) trmsqlx.Tr {
+	return r.tx.DefaultTrOrDB(ctx, r.sqlx)
+}
+
+func (r *DefaultRepository) UpsertGameRating(ctx context.Context, rating model.GameRating) (model.GameRating, error) {
+	const query = `
+		insert into easy_quizy_game_rating (game_id, player_id, rating)
+		values ($1, $2, $3)
+		on conflict (game_id, player_id) do update
+		set rating = excluded.rating, updated_at = NOW()
+		returning game_id, player_id, rating, updated_at
+	`
+
+	var result sqlxGameRating
+	if err := r.db(ctx).GetContext(ctx, &result, query, rating.GameID, rating.PlayerID, rating.Rating); err != nil {
+		return model.GameRating{}, err
+	}
+
+	return model.GameRating(result), nil
+}
+
+func (r *DefaultRepository) UpsertQuestionFlag(ctx context.Context, flag model.QuestionFlag) (model.QuestionFlag, error) {
+	const query = `
+		insert into easy_quizy_question_flag (game_id, player_id, question_id, reason, comment)
+		values ($1, $2, $3, $4, $5)
+		on conflict (game_id, player_id, question_id) do update
+		set reason = excluded.reason, comment = excluded.comment, updated_at = NOW()
+		returning game_id, player_id, question_id, reason, comment, updated_at
+	`
+
+	var result sqlxQuestionFlag
+	if err := r.db(ctx).GetContext(
+		ctx,
+		&result,
+		query,
+		flag.GameID,
+		flag.PlayerID,
+		flag.QuestionID,
+		flag.Reason,
+		flag.Comment,
+	); err != nil {
+		return model.QuestionFlag{}, err
+	}
+
+	return model.QuestionFlag{
+		GameID:     result.GameID,
+		PlayerID:   result.PlayerID,
+		QuestionID: result.QuestionID,
+		Reason:     model.FlagReason(result.Reason),
+		Comment:    result.Comment,
+		UpdatedAt:  result.UpdatedAt,
+	}, nil
+}
+
+// GetFlaggedQuestions группирует жалобы по вопросу, вопросы с наибольшим числом жалоб сверху
+func (r *DefaultRepository) GetFlaggedQuestions(ctx context.Context, filter model.FlaggedQuestionsFilter) (model.FlaggedQuestions, error) {
+	const countQuery = `
+		select count(*)
+		from (
+			select distinct game_id, question_id
+			from easy_quizy_question_flag
+			where $1::uuid is null or game_id = $1
+		) q
+	`
+
+	const query = `
+		select
+			f.game_id,
+			f.question_id,
+			count(*) as total,
+			count(*) filter (where f.reason = 'wrong') as wrong,
+			count(*) filter (where f.reason = 'ambiguous') as ambiguous,
+			max(f.updated_at) as last_flagged_at,
+			coalesce(
+				(array_agg(f.comment order by f.updated_at desc) filter (where f.comment is not null))[1:$4],
+				'{}'
+			) as recent_comments
+		from easy_quizy_question_flag f
+		where $1::uuid is null or f.game_id = $1
+		group by f.game_id, f.question_id
+		order by total desc, last_flagged_at desc, f.game_id, f.question_id
+		limit $2 offset $3
+	`
+
+	var total int64
+	if err := r.db(ctx).GetContext(ctx, &total, countQuery, filter.GameID); err != nil {
+		return model.FlaggedQuestions{}, err
+	}
+
+	var result []sqlxFlaggedQuestion
+	if err := r.db(ctx).SelectContext(
+		ctx,
+		&result,
+		query,
+		filter.GameID,
+		filter.Page.Limit,
+		filter.Page.Offset,
+		recentCommentsLimit,
+	); err != nil {
+		return model.FlaggedQuestions{}, err
+	}
+
+	return model.FlaggedQuestions{
+		Items: slices.SafeMap(result, func(item sqlxFlaggedQuestion) model.FlaggedQuestion {
+			return model.FlaggedQuestion{
+				GameID:         item.GameID,
+				QuestionID:     item.QuestionID,
+				Total:          item.Total,
+				Wrong:          item.Wrong,
+				Ambiguous:      item.Ambiguous,
+				LastFlaggedAt:  item.LastFlaggedAt,
+				RecentComments: item.RecentComments,
+			}
+		}),
+		Total: total,
+	}, nil
+}
+
+// GetRatingSummaries средние оценки игр, лучшие сверху
+func (r *DefaultRepository) GetRatingSummaries(ctx context.Context, page model.Page) (model.GameRatingSummaries, error) {
+	const countQuery = `
+		select count(distinct game_id)
+		from easy_quizy_game_rating
+	`
+
+	const query = `
+		select
+			r.game_id,
+			avg(r.rating)::float8 as average,
+			count(*) as count
+		from easy_quizy_game_rating r
+		group by r.game_id
+		order by average desc, count desc, r.game_id
+		limit $1 offset $2
+	`
+
+	var total int64
+	if err := r.db(ctx).GetContext(ctx, &total, countQuery); err != nil {
+		return model.GameRatingSummaries{}, err
+	}
+
+	var result []sqlxGameRatingSummary
+	if err := r.db(ctx).SelectContext(ctx, &result, query, page.Limit, page.Offset); err != nil {
+		return model.GameRatingSummaries{}, err
+	}
+
+	return model.GameRatingSummaries{
+		Items: slices.SafeMap(result, func(item sqlxGameRatingSummary) model.GameRatingSummary {
+			return model.GameRatingSummary{
+				GameID:  item.GameID,
+				Average: item.Average,
+				Count:   item.Count,
+			}
+		}),
+		Total: total,
+	}, nil
+}
@@ -0,0 +1,235 @@
+package feedback
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"easy-quizy/internal/pgtest"
+	"testing"
+
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+func newTestRepository(t *testing.T) (*DefaultRepository, *sqlx.DB) {
+	t.Helper()
+
+	db := pgtest.Start(t)
+	return NewRepository(db, trmsqlx.DefaultCtxGetter), db
+}
+
+func comment(text string) *string {
+	return &text
+}
+
+func TestUpsertGameRating(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		ratings []int64
+		want    int64
+		wantErr bool
+	}{
+		{name: "first rating", ratings: []int64{4}, want: 4},
+		{name: "second rating replaces the first", ratings: []int64{4, 2}, want: 2},
+		{name: "out of range", ratings: []int64{6}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pgtest.Truncate(t, db)
+			gameID := pgtest.InsertGame(t, db, model.GameTypeClassic, 1)
+			playerID := uuid.New()
+
+			var (
+				got model.GameRating
+				err error
+			)
+			for _, rating := range tt.ratings {
+				got, err = repo.UpsertGameRating(ctx, model.GameRating{GameID: gameID, PlayerID: playerID, Rating: rating})
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UpsertGameRating error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got.Rating != tt.want {
+				t.Errorf("Rating = %d, want %d", got.Rating, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpsertQuestionFlag(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		flags      []model.QuestionFlag
+		wantReason model.FlagReason
+		wantErr    bool
+	}{
+		{
+			name:       "first flag",
+			flags:      []model.QuestionFlag{{Reason: model.FlagReasonWrong}},
+			wantReason: model.FlagReasonWrong,
+		},
+		{
+			name: "second flag replaces the first",
+			flags: []model.QuestionFlag{
+				{Reason: model.FlagReasonWrong},
+				{Reason: model.FlagReasonAmbiguous, Comment: comment("two answers fit")},
+			},
+			wantReason: model.FlagReasonAmbiguous,
+		},
+		{
+			name:    "unknown reason",
+			flags:   []model.QuestionFlag{{Reason: "boring"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pgtest.Truncate(t, db)
+			gameID := pgtest.InsertGame(t, db, model.GameTypeClassic, 1)
+			playerID := uuid.New()
+
+			var (
+				got model.QuestionFlag
+				err error
+			)
+			for _, flag := range tt.flags {
+				flag.GameID, flag.PlayerID = gameID, playerID
+				got, err = repo.UpsertQuestionFlag(ctx, flag)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UpsertQuestionFlag error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got.Reason != tt.wantReason {
+				t.Errorf("Reason = %s, want %s", got.Reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestGetFlaggedQuestions(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+
+	pgtest.Truncate(t, db)
+	firstGame := pgtest.InsertGame(t, db, model.GameTypeClassic, 2)
+	secondGame := pgtest.InsertGame(t, db, model.GameTypeClassic, 2)
+	for _, flag := range []model.QuestionFlag{
+		{GameID: firstGame, QuestionID: 0, Reason: model.FlagReasonWrong, Comment: comment("typo")},
+		{GameID: firstGame, QuestionID: 0, Reason: model.FlagReasonAmbiguous},
+		{GameID: firstGame, QuestionID: 1, Reason: model.FlagReasonWrong},
+		{GameID: secondGame, QuestionID: 0, Reason: model.FlagReasonWrong},
+	} {
+		flag.PlayerID = uuid.New()
+		if _, err := repo.UpsertQuestionFlag(ctx, flag); err != nil {
+			t.Fatalf("UpsertQuestionFlag: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		filter    model.FlaggedQuestionsFilter
+		wantTotal int64
+		wantFirst model.FlaggedQuestion
+	}{
+		{
+			name:      "all games, most flagged first",
+			filter:    model.FlaggedQuestionsFilter{Page: model.Page{Limit: 10}},
+			wantTotal: 3,
+			wantFirst: model.FlaggedQuestion{GameID: firstGame, QuestionID: 0, Total: 2, Wrong: 1, Ambiguous: 1},
+		},
+		{
+			name:      "one game",
+			filter:    model.FlaggedQuestionsFilter{GameID: &secondGame, Page: model.Page{Limit: 10}},
+			wantTotal: 1,
+			wantFirst: model.FlaggedQuestion{GameID: secondGame, QuestionID: 0, Total: 1, Wrong: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.GetFlaggedQuestions(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("GetFlaggedQuestions: %v", err)
+			}
+			if got.Total != tt.wantTotal || int64(len(got.Items)) != tt.wantTotal {
+				t.Fatalf("GetFlaggedQuestions = %d items of %d, want %d", len(got.Items), got.Total, tt.wantTotal)
+			}
+
+			first := got.Items[0]
+			if first.GameID != tt.wantFirst.GameID ||
+				first.QuestionID != tt.wantFirst.QuestionID ||
+				first.Total != tt.wantFirst.Total ||
+				first.Wrong != tt.wantFirst.Wrong ||
+				first.Ambiguous != tt.wantFirst.Ambiguous {
+				t.Errorf("first item = %+v, want %+v", first, tt.wantFirst)
+			}
+		})
+	}
+}
+
+func TestGetRatingSummaries(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+
+	pgtest.Truncate(t, db)
+	bestGame := pgtest.InsertGame(t, db, model.GameTypeClassic, 1)
+	otherGame := pgtest.InsertGame(t, db, model.GameTypeClassic, 1)
+	for _, rating := range []model.GameRating{
+		{GameID: bestGame, Rating: 5},
+		{GameID: bestGame, Rating: 4},
+		{GameID: otherGame, Rating: 3},
+	} {
+		rating.PlayerID = uuid.New()
+		if _, err := repo.UpsertGameRating(ctx, rating); err != nil {
+			t.Fatalf("UpsertGameRating: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		page model.Page
+		want []model.GameRatingSummary
+	}{
+		{
+			name: "best first",
+			page: model.Page{Limit: 10},
+			want: []model.GameRatingSummary{
+				{GameID: bestGame, Average: 4.5, Count: 2},
+				{GameID: otherGame, Average: 3, Count: 1},
+			},
+		},
+		{
+			name: "second page",
+			page: model.Page{Limit: 1, Offset: 1},
+			want: []model.GameRatingSummary{{GameID: otherGame, Average: 3, Count: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.GetRatingSummaries(ctx, tt.page)
+			if err != nil {
+				t.Fatalf("GetRatingSummaries: %v", err)
+			}
+			if got.Total != 2 {
+				t.Errorf("Total = %d, want 2", got.Total)
+			}
+			if len(got.Items) != len(tt.want) {
+				t.Fatalf("Items = %v, want %v", got.Items, tt.want)
+			}
+			for i := range got.Items {
+				if got.Items[i] != tt.want[i] {
+					t.Errorf("item #%d = %+v, want %+v", i, got.Items[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
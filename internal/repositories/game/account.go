@@ -0,0 +1,184 @@
+package game
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type sqlxOrphanPlayer struct {
+	PlayerID    uuid.UUID `db:"player_id"`
+	FirstSeenAt time.Time `db:"first_seen_at"`
+	FirstGameID uuid.UUID `db:"first_game_id"`
+}
+
+// GetOrphanPlayers игроки, у попыток которых нет ни одного источника, с их первой попыткой
+func (r *DefaultRepository) GetOrphanPlayers(ctx context.Context) ([]model.OrphanPlayer, error) {
+	const query = `
+		select distinct on (s.player_id)
+			s.player_id,
+			s.started_at as first_seen_at,
+			s.game_id as first_game_id
+		from easy_quizy_session s
+		where not exists (
+			select 1
+			from easy_quizy_user_source u
+			where u.user_id_int = s.player_id
+		)
+		order by s.player_id, s.started_at
+	`
+
+	var rows []sqlxOrphanPlayer
+	if err := r.db(ctx).SelectContext(ctx, &rows, query); err != nil {
+		return nil, err
+	}
+
+	result := make([]model.OrphanPlayer, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, model.OrphanPlayer{
+			PlayerID:    row.PlayerID,
+			FirstSeenAt: row.FirstSeenAt,
+			FirstGameID: row.FirstGameID,
+		})
+	}
+
+	return result, nil
+}
+
+// ReassignSessions переносит попытки и ответы игрока from на игрока to.
+// Перенесенные попытки нумеруются после попыток to в той же игре, совпавшие ключи идемпотентности сбрасываются
+func (r *DefaultRepository) ReassignSessions(ctx context.Context, from uuid.UUID, to uuid.UUID) (int64, error) {
+	const answersQuery = `
+		update easy_quizy_game_session a
+		set
+			player_id = $2,
+			idempotency_key = case
+				when exists (
+					select 1
+					from easy_quizy_game_session t
+					where t.player_id = $2 and t.idempotency_key = a.idempotency_key
+				) then null
+				else a.idempotency_key
+			end
+		where a.player_id = $1
+	`
+
+	const sessionsQuery = `
+		with target as (
+			select game_id, max(attempt) as max_attempt
+			from easy_quizy_session
+			where player_id = $2
+			group by game_id
+		), moved as (
+			select
+				s.id,
+				coalesce(t.max_attempt, 0) + row_number() over (partition by s.game_id order by s.attempt) as attempt
+			from easy_quizy_session s
+			left join target t on t.game_id = s.game_id
+			where s.player_id = $1
+		)
+		update easy_quizy_session s
+		set player_id = $2, attempt = m.attempt
+		from moved m
+		where s.id = m.id
+	`
+
+	if _, err := r.db(ctx).ExecContext(ctx, answersQuery, from, to); err != nil {
+		return 0, err
+	}
+
+	result, err := r.db(ctx).ExecContext(ctx, sessionsQuery, from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// MergeSessions переносит попытки и ответы игрока from на игрока to и помечает их как перенесенные.
+// В играх, где попытки есть у обоих, все попытки нумеруются заново по времени начала, а лучшая завершенная
+// становится последней и видна как текущий результат. Незавершенная попытка остается последней, чтобы ее можно было доиграть
+func (r *DefaultRepository) MergeSessions(ctx context.Context, from uuid.UUID, to uuid.UUID) (int64, error) {
+	const markQuery = `
+		update easy_quizy_session
+		set merged_from = coalesce(merged_from, player_id)
+		where player_id = $1
+	`
+
+	// Номера сначала записываются со знаком минус, чтобы перестановка не нарушала уникальность попытки
+	const numberQuery = `
+		with shared as (
+			select game_id from easy_quizy_session where player_id = $1
+			intersect
+			select game_id from easy_quizy_session where player_id = $2
+		), best as (
+			select distinct on (s.game_id) s.game_id, s.id
+			from easy_quizy_session s
+			inner join shared g on g.game_id = s.game_id
+			where s.player_id in ($1, $2) and s.finished_at is not null
+			order by s.game_id, s.score desc, s.finished_at
+		), numbered as (
+			select
+				s.id,
+				row_number() over (
+					partition by s.game_id
+					order by s.finished_at is null, coalesce(s.id = b.id, false), s.started_at, s.attempt
+				) as attempt
+			from easy_quizy_session s
+			inner join shared g on g.game_id = s.game_id
+			left join best b on b.game_id = s.game_id
+			where s.player_id in ($1, $2)
+		)
+		update easy_quizy_session s
+		set player_id = $2, attempt = -n.attempt
+		from numbered n
+		where s.id = n.id
+	`
+
+	const flipQuery = `
+		update easy_quizy_session
+		set attempt = -attempt
+		where player_id = $1 and attempt < 0
+	`
+
+	result, err := r.db(ctx).ExecContext(ctx, markQuery, from)
+	if err != nil {
+		return 0, err
+	}
+	moved, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := r.db(ctx).ExecContext(ctx, numberQuery, from, to); err != nil {
+		return 0, err
+	}
+	if _, err := r.db(ctx).ExecContext(ctx, flipQuery, to); err != nil {
+		return 0, err
+	}
+
+	// Попытки в играх, где у to попыток не было, переносятся с прежними номерами
+	if _, err := r.ReassignSessions(ctx, from, to); err != nil {
+		return 0, err
+	}
+
+	return moved, nil
+}
+
+// CountOwnAttempts число попыток игрока в игре без перенесенных слиянием аккаунтов
+func (r *DefaultRepository) CountOwnAttempts(ctx context.Context, gameID uuid.UUID, playerID uuid.UUID) (int64, error) {
+	const query = `
+		select count(*)
+		from easy_quizy_session
+		where game_id = $1 and player_id = $2 and merged_from is null
+	`
+
+	var result int64
+	if err := r.db(ctx).GetContext(ctx, &result, query, gameID, playerID); err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,48 @@
+package game
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CountCorrectAnswers число правильных ответов игрока во всех попытках
+func (r *DefaultRepository) CountCorrectAnswers(ctx context.Context, playerID uuid.UUID) (int64, error) {
+	const query = `
+		select count(*)
+		from easy_quizy_game_session
+		where player_id = $1 and is_correct
+	`
+
+	var result int64
+	if err := r.db(ctx).GetContext(ctx, &result, query, playerID); err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+
+// GetDailyCompletionDays дни (UTC) начиная с since, в которые игрок завершил ежедневную игру, последние сверху
+func (r *DefaultRepository) GetDailyCompletionDays(ctx context.Context, playerID uuid.UUID, since time.Time) ([]time.Time, error) {
+	const query = `
+		select distinct date_trunc('day', s.finished_at at time zone 'UTC') as day
+		from easy_quizy_session s
+		inner join easy_quizy_game g on g.id = s.game_id
+		where s.player_id = $1 and g.type = $2 and s.finished_at >= $3
+		order by day desc
+	`
+
+	var rows []time.Time
+	if err := r.db(ctx).SelectContext(ctx, &rows, query, playerID, model.GameTypeDaily, since); err != nil {
+		return nil, err
+	}
+
+	result := make([]time.Time, 0, len(rows))
+	for _, day := range rows {
+		result = append(result, time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC))
+	}
+
+	return result, nil
+}
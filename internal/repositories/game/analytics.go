@@ -0,0 +1,212 @@
+package game
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type (
+	sqlxQuestionStats struct {
+		GameID              uuid.UUID `db:"game_id"`
+		QuestionID          int64     `db:"question_id"`
+		OptionCounts        []byte    `db:"option_counts"`
+		Answers             int64     `db:"answers"`
+		Correct             int64     `db:"correct"`
+		MedianAnswerSeconds *float64  `db:"median_answer_seconds"`
+		Reached             int64     `db:"reached"`
+		Dropped             int64     `db:"dropped"`
+		ComputedAt          time.Time `db:"computed_at"`
+	}
+)
+
+// RefreshQuestionStats пересчитывает статистику по вопросам игр, в которых что-то изменилось с прошлого
+// расчета. Попытка считается брошенной, если она не завершена и последняя активность в ней была раньше
+// abandonedBefore; брошенной на вопросе считается первый по порядку вопрос без ответа. Игры без новых
+// ответов и попыток с момента прошлого расчета минус время до брошенности не меняются: их попытки
+// не могли стать брошенными. Вызывать внутри транзакции.
+func (r *DefaultRepository) RefreshQuestionStats(ctx context.Context, abandonedBefore time.Time, computedAt time.Time) error {
+	// Без прошлого расчета пересчитываются все игры
+	const changedQuery = `
+		with last as (
+			select coalesce(max(computed_at) - ($2::timestamptz - $1::timestamptz), '-infinity') as since
+			from easy_quizy_question_stats
+		)
+		select s.game_id
+		from easy_quizy_session s, last l
+		where s.started_at >= l.since
+		union
+		select a.game_id
+		from easy_quizy_game_session a, last l
+		where a.created_at >= l.since
+	`
+
+	const deleteQuery = `delete from easy_quizy_question_stats where game_id = any($1)`
+
+	const insertQuery = `
+		with timed as (
+			select
+				a.game_id,
+				a.question_id,
+				a.answer_id,
+				a.is_correct,
+				extract(epoch from a.created_at - coalesce(
+					lag(a.created_at) over (partition by a.session_id order by a.created_at),
+					s.started_at
+				))::float8 as answer_seconds
+			from easy_quizy_game_session a
+			inner join easy_quizy_session s on s.id = a.session_id
+			where a.game_id = any($3)
+		),
+		options as (
+			select
+				game_id,
+				question_id,
+				jsonb_object_agg(answer_id::text, answers) as option_counts
+			from (
+				select game_id, question_id, answer_id, count(*) as answers
+				from timed
+				group by game_id, question_id, answer_id
+			) o
+			group by game_id, question_id
+		),
+		answered as (
+			select
+				game_id,
+				question_id,
+				count(*) as answers,
+				count(*) filter (where is_correct) as correct,
+				percentile_cont(0.5) within group (order by answer_seconds) as median_answer_seconds
+			from timed
+			group by game_id, question_id
+		),
+		abandoned as (
+			select
+				s.game_id,
+				(
+					select min(q.id)
+					from generate_series(0, jsonb_array_length(g.payload -> 'questions') - 1) as q(id)
+					where not exists (
+						select 1
+						from easy_quizy_game_session a
+						where a.session_id = s.id and a.question_id = q.id
+					)
+				) as question_id
+			from easy_quizy_session s
+			inner join easy_quizy_game g on g.id = s.game_id
+			where s.game_id = any($3)
+				and s.finished_at is null
+				and coalesce(
+					(select max(a.created_at) from easy_quizy_game_session a where a.session_id = s.id),
+					s.started_at
+				) < $1
+		),
+		dropped as (
+			select game_id, question_id, count(*) as dropped
+			from abandoned
+			where question_id is not null
+			group by game_id, question_id
+		)
+		insert into easy_quizy_question_stats (
+			game_id,
+			question_id,
+			option_counts,
+			answers,
+			correct,
+			median_answer_seconds,
+			reached,
+			dropped,
+			computed_at
+		)
+		select
+			coalesce(a.game_id, d.game_id),
+			coalesce(a.question_id, d.question_id),
+			coalesce(o.option_counts, '{}'),
+			coalesce(a.answers, 0),
+			coalesce(a.correct, 0),
+			a.median_answer_seconds,
+			coalesce(a.answers, 0) + coalesce(d.dropped, 0),
+			coalesce(d.dropped, 0),
+			$2
+		from answered a
+		full join dropped d on d.game_id = a.game_id and d.question_id = a.question_id
+		left join options o on o.game_id = a.game_id and o.question_id = a.question_id
+	`
+
+	var changed []uuid.UUID
+	if err := r.db(ctx).SelectContext(ctx, &changed, changedQuery, abandonedBefore, computedAt); err != nil {
+		return err
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	if _, err := r.db(ctx).ExecContext(ctx, deleteQuery, pq.Array(changed)); err != nil {
+		return err
+	}
+
+	_, err := r.db(ctx).ExecContext(ctx, insertQuery, abandonedBefore, computedAt, pq.Array(changed))
+	return err
+}
+
+func (r *DefaultRepository) GetQuestionStats(ctx context.Context, gameID uuid.UUID) ([]model.QuestionStatsRow, error) {
+	const query = `
+		select
+			game_id,
+			question_id,
+			option_counts,
+			answers,
+			correct,
+			median_answer_seconds,
+			reached,
+			dropped,
+			computed_at
+		from easy_quizy_question_stats
+		where game_id = $1
+		order by question_id
+	`
+
+	var rows []sqlxQuestionStats
+	if err := r.db(ctx).SelectContext(ctx, &rows, query, gameID); err != nil {
+		return nil, err
+	}
+
+	result := make([]model.QuestionStatsRow, 0, len(rows))
+	for _, row := range rows {
+		var counts map[string]int64
+		if err := json.Unmarshal(row.OptionCounts, &counts); err != nil {
+			return nil, err
+		}
+
+		item := model.QuestionStatsRow{
+			GameID:       row.GameID,
+			QuestionID:   row.QuestionID,
+			OptionCounts: make(map[int64]int64, len(counts)),
+			Answers:      row.Answers,
+			Correct:      row.Correct,
+			Reached:      row.Reached,
+			Dropped:      row.Dropped,
+			ComputedAt:   row.ComputedAt,
+		}
+		for key, count := range counts {
+			optionID, err := strconv.ParseInt(key, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			item.OptionCounts[optionID] = count
+		}
+		if row.MedianAnswerSeconds != nil {
+			median := time.Duration(*row.MedianAnswerSeconds * float64(time.Second))
+			item.MedianAnswerTime = &median
+		}
+
+		result = append(result, item)
+	}
+
+	return result, nil
+}
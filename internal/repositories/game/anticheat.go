@@ -0,0 +1,156 @@
+package game
+
+import (
+	"context"
+	"database/sql"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// GetGameSession попытка по идентификатору вместе с ответами и подсказками
+func (r *DefaultRepository) GetGameSession(ctx context.Context, sessionID uuid.UUID) (model.GameSession, error) {
+	const query = `
+		select
+			id,
+			game_id,
+			player_id,
+			attempt,
+			started_at,
+			finished_at,
+			score,
+			result_text
+		from easy_quizy_session
+		where id = $1
+	`
+
+	var result sqlxSession
+	if err := r.db(ctx).GetContext(ctx, &result, query, sessionID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.GameSession{}, contracts.ErrSessionNotFound
+		}
+
+		return model.GameSession{}, err
+	}
+
+	answers, err := r.getGameSessionAnswers(ctx, result.ID)
+	if err != nil {
+		return model.GameSession{}, err
+	}
+
+	lifelines, err := r.getLifelineUsages(ctx, result.ID)
+	if err != nil {
+		return model.GameSession{}, err
+	}
+
+	return convertToSession(result, answers, lifelines), nil
+}
+
+// CountPlayerAnswers число ответов игрока во всех играх начиная с since
+func (r *DefaultRepository) CountPlayerAnswers(ctx context.Context, playerID uuid.UUID, since time.Time) (int64, error) {
+	const query = `
+		select count(*)
+		from easy_quizy_game_session
+		where player_id = $1 and created_at >= $2
+	`
+
+	var result int64
+	if err := r.db(ctx).GetContext(ctx, &result, query, playerID, since); err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+
+// CountGameSessions число попыток игрока в игре, начатых начиная с since
+func (r *DefaultRepository) CountGameSessions(ctx context.Context, gameID uuid.UUID, playerID uuid.UUID, since time.Time) (int64, error) {
+	const query = `
+		select count(*)
+		from easy_quizy_session
+		where game_id = $1 and player_id = $2 and started_at >= $3
+	`
+
+	var result int64
+	if err := r.db(ctx).GetContext(ctx, &result, query, gameID, playerID, since); err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+
+// GetFinishedSessions попытки игроков в игре, завершенные начиная с since, вместе с ответами
+func (r *DefaultRepository) GetFinishedSessions(
+	ctx context.Context,
+	gameID uuid.UUID,
+	playerIDs []uuid.UUID,
+	since time.Time,
+) ([]model.GameSession, error) {
+	if len(playerIDs) == 0 {
+		return nil, nil
+	}
+
+	const (
+		query = `
+			select
+				id,
+				game_id,
+				player_id,
+				attempt,
+				started_at,
+				finished_at,
+				score,
+				result_text
+			from easy_quizy_session
+			where game_id = $1 and player_id = any($2) and finished_at >= $3
+			order by finished_at
+		`
+		answersQuery = `
+			select
+				session_id,
+				game_id,
+				player_id,
+				question_id,
+				answer_id,
+				is_correct,
+				created_at,
+				idempotency_key
+			from easy_quizy_game_session
+			where session_id = any($1)
+			order by id
+		`
+	)
+
+	var sessions []sqlxSession
+	if err := r.db(ctx).SelectContext(ctx, &sessions, query, gameID, pq.Array(playerIDs), since); err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 {
+		return nil, nil
+	}
+
+	sessionIDs := make([]uuid.UUID, 0, len(sessions))
+	for _, session := range sessions {
+		sessionIDs = append(sessionIDs, session.ID)
+	}
+
+	var answers []sqlxGameSession
+	if err := r.db(ctx).SelectContext(ctx, &answers, answersQuery, pq.Array(sessionIDs)); err != nil {
+		return nil, err
+	}
+
+	bySession := make(map[uuid.UUID][]sqlxGameSession, len(sessions))
+	for _, answer := range answers {
+		bySession[answer.SessionID] = append(bySession[answer.SessionID], answer)
+	}
+
+	result := make([]model.GameSession, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, convertToSession(session, bySession[session.ID], nil))
+	}
+
+	return result, nil
+}
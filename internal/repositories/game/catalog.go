@@ -0,0 +1,144 @@
+package game
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"easy-quizy/pkg/structs/collections/slices"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type (
+	sqlxCatalogItem struct {
+		sqlxGame
+		Played    bool `db:"played"`
+		Completed bool `db:"completed"`
+	}
+)
+
+// GetCatalog возвращает страницу каталога с флагами прохождения для игрока. При поиске
+// игры упорядочены по релевантности, иначе — новые сверху.
+func (r *DefaultRepository) GetCatalog(ctx context.Context, playerID uuid.UUID, filter model.CatalogFilter) (model.Catalog, error) {
+	where, args := catalogConditions(filter)
+
+	countQuery := fmt.Sprintf(`
+		select count(*)
+		from easy_quizy_game g
+		where %s
+	`, where)
+
+	order := "g.created_at desc, g.id"
+	if filter.Query != "" {
+		order = "ts_rank(g.search, websearch_to_tsquery('simple', $1)) desc, " + order
+	}
+
+	args = append(args, playerID, filter.Page.Limit, filter.Page.Offset)
+	query := fmt.Sprintf(`
+		select
+			g.id,
+			g.type,
+			g.payload,
+			g.created_at,
+			exists (
+				select 1
+				from easy_quizy_game_session a
+				where a.game_id = g.id and a.player_id = $%[2]d
+			) as played,
+			exists (
+				select 1
+				from easy_quizy_session s
+				where s.game_id = g.id and s.player_id = $%[2]d and s.finished_at is not null
+			) as completed
+		from easy_quizy_game g
+		where %[1]s
+		order by %[5]s
+		limit $%[3]d offset $%[4]d
+	`, where, len(args)-2, len(args)-1, len(args), order)
+
+	var total int64
+	if err := r.db(ctx).GetContext(ctx, &total, countQuery, args[:len(args)-3]...); err != nil {
+		return model.Catalog{}, err
+	}
+
+	var result []sqlxCatalogItem
+	if err := r.db(ctx).SelectContext(ctx, &result, query, args...); err != nil {
+		return model.Catalog{}, err
+	}
+
+	items, err := slices.Map(result, func(i sqlxCatalogItem) (model.CatalogItem, error) {
+		game, err := convertToGame(i.sqlxGame)
+		if err != nil {
+			return model.CatalogItem{}, err
+		}
+
+		item := model.CatalogItemFromGame(game)
+		item.Played = i.Played
+		item.Completed = i.Completed
+		return item, nil
+	})
+	if err != nil {
+		return model.Catalog{}, err
+	}
+
+	return model.Catalog{
+		Items: items,
+		Total: total,
+	}, nil
+}
+
+// releasedDaily ежедневная игра уже выходила: закрыта или стоит первой в очереди. Игры, поставленные
+// в очередь на следующие дни, в каталоге не показываются.
+const releasedDaily = `exists (
+	select 1
+	from easy_quizy_game_daily gd
+	where gd.game_id = g.id and (gd.ended_at is not null or gd.id = (
+		select id
+		from easy_quizy_game_daily
+		where ended_at is null
+		order by created_at asc
+		limit 1
+	))
+)`
+
+// catalogConditions собирает условие where, параметр поиска всегда идет первым — на него ссылается сортировка
+func catalogConditions(filter model.CatalogFilter) (string, []any) {
+	var (
+		conditions []string
+		args       []any
+	)
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Query != "" {
+		add("g.search @@ websearch_to_tsquery('simple', $%d)", filter.Query)
+	}
+	if filter.Type != nil {
+		add("g.type = $%d", string(*filter.Type))
+		if *filter.Type == model.GameTypeDaily {
+			conditions = append(conditions, releasedDaily)
+		}
+	} else {
+		add("g.type <> $%d", model.GameTypeDaily)
+	}
+	if filter.Category != nil {
+		add("g.payload ->> 'category' = $%d", *filter.Category)
+	}
+	if filter.Difficulty != nil {
+		add("g.payload ->> 'difficulty' = $%d", *filter.Difficulty)
+	}
+	if filter.Language != nil {
+		add("g.payload ->> 'language' = $%d", *filter.Language)
+	}
+	if len(filter.Tags) > 0 {
+		// ошибка невозможна: срез строк всегда сериализуется
+		tags, _ := json.Marshal(filter.Tags)
+		add("g.payload -> 'tags' @> $%d::jsonb", string(tags))
+	}
+
+	return strings.Join(conditions, " and "), args
+}
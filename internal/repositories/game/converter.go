@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // Вспомогательные структуры для парсинга JSON
@@ -13,14 +14,37 @@ type rawGame struct {
 	Name        string            `json:"name"`
 	Description *string           `json:"description"`
 	Type        string            `json:"type"`
+	Category    *string           `json:"category"`
+	Tags        []string          `json:"tags"`
+	Difficulty  *string           `json:"difficulty"`
+	Language    *string           `json:"language"`
+	Cover       *string           `json:"cover"`
 	Questions   []rawQuestion     `json:"questions"`
 	Result      map[string]string `json:"result"`
+	Lifelines   *rawLifelines     `json:"lifelines"`
+	Replay      *rawReplay        `json:"replay"`
+}
+
+// rawReplay правило повтора: policy unlimited, attempts (с attempts), cooldown (с cooldown вида "24h") или never
+type rawReplay struct {
+	Policy   string `json:"policy"`
+	Attempts int64  `json:"attempts"`
+	Cooldown string `json:"cooldown"`
+}
+
+// rawLifelines сколько раз за попытку доступна каждая подсказка
+type rawLifelines struct {
+	FiftyFifty  int64 `json:"fiftyFifty"`
+	Skip        int64 `json:"skip"`
+	Hint        int64 `json:"hint"`
+	ReduceScore bool  `json:"reduceScore"`
 }
 
 type rawQuestion struct {
 	Question    string            `json:"question"`
 	Image       *string           `json:"image"`
 	Explanation *string           `json:"explanation"`
+	Hint        *string           `json:"hint"`
 	Options     []rawAnswerOption `json:"options"`
 }
 
@@ -51,11 +75,63 @@ func parseScoreResults(resultMap map[string]string) ([]model.ScoreResult, error)
 	return results, nil
 }
 
+func parseReplay(in *rawReplay) (model.ReplayPolicy, error) {
+	if in == nil {
+		return model.ReplayPolicy{}, nil
+	}
+
+	result := model.ReplayPolicy{Type: model.ReplayPolicyType(in.Policy)}
+	switch result.Type {
+	case model.ReplayUnlimited, model.ReplayNever:
+	case model.ReplayAttempts:
+		if in.Attempts <= 0 {
+			return model.ReplayPolicy{}, fmt.Errorf("replay attempts must be positive")
+		}
+		result.MaxAttempts = in.Attempts
+	case model.ReplayCooldown:
+		cooldown, err := time.ParseDuration(in.Cooldown)
+		if err != nil || cooldown <= 0 {
+			return model.ReplayPolicy{}, fmt.Errorf("invalid replay cooldown: %s", in.Cooldown)
+		}
+		result.Cooldown = cooldown
+	default:
+		return model.ReplayPolicy{}, fmt.Errorf("invalid replay policy: %s", in.Policy)
+	}
+
+	return result, nil
+}
+
+func parseLifelines(in *rawLifelines) (model.LifelineRules, error) {
+	if in == nil {
+		return model.LifelineRules{}, nil
+	}
+	if in.FiftyFifty < 0 || in.Skip < 0 || in.Hint < 0 {
+		return model.LifelineRules{}, fmt.Errorf("negative lifeline allowance")
+	}
+
+	return model.LifelineRules{
+		Allowance: map[model.LifelineType]int64{
+			model.LifelineFiftyFifty: in.FiftyFifty,
+			model.LifelineSkip:       in.Skip,
+			model.LifelineHint:       in.Hint,
+		},
+		ReduceScore: in.ReduceScore,
+	}, nil
+}
+
 func rawToGame(rg rawGame) (model.Game, error) {
 	scoreResults, err := parseScoreResults(rg.Result)
 	if err != nil {
 		return model.Game{}, err
 	}
+	lifelines, err := parseLifelines(rg.Lifelines)
+	if err != nil {
+		return model.Game{}, err
+	}
+	replay, err := parseReplay(rg.Replay)
+	if err != nil {
+		return model.Game{}, err
+	}
 	var questions []model.Question
 	for idxq, rq := range rg.Questions {
 		var options []model.AnswerOption
@@ -72,14 +148,22 @@ func rawToGame(rg rawGame) (model.Game, error) {
 			Text:          rq.Question,
 			ImageID:       rq.Image,
 			Explanation:   rq.Explanation,
+			Hint:          rq.Hint,
 			AnswerOptions: options,
 		})
 	}
 	return model.Game{
 		Title:        rg.Name,
 		Description:  rg.Description,
+		Category:     rg.Category,
+		Tags:         rg.Tags,
+		Difficulty:   rg.Difficulty,
+		Language:     rg.Language,
+		Cover:        rg.Cover,
 		Questions:    questions,
 		ScoreResults: scoreResults,
+		Lifelines:    lifelines,
+		Replay:       replay,
 	}, nil
 }
 
@@ -95,27 +179,49 @@ func convertToGame(in sqlxGame) (model.Game, error) {
 
 	game.ID = in.ID
 	game.Type = model.GameType(in.Type)
+	game.CreatedAt = in.CreatedAt
 	return game, nil
 }
 
-func convertToSession(in []sqlxGameSession) model.GameSession {
-	if len(in) == 0 {
-		return model.GameSession{}
-	}
-
+func convertToSession(in sqlxSession, answers []sqlxGameSession, lifelines []sqlxLifelineUsage) model.GameSession {
 	result := model.GameSession{
-		GameID:   in[0].GameID,
-		PlayerID: in[0].PlayerID,
-		Answers:  make([]model.GameSessionAnswer, 0, len(in)),
+		ID:         in.ID,
+		GameID:     in.GameID,
+		PlayerID:   in.PlayerID,
+		Attempt:    in.Attempt,
+		StartedAt:  in.StartedAt,
+		FinishedAt: in.FinishedAt,
+		Score:      in.Score,
+		ResultText: in.ResultText,
+		Answers:    make([]model.GameSessionAnswer, 0, len(answers)),
+		Lifelines:  make([]model.LifelineUsage, 0, len(lifelines)),
 	}
 
-	for _, item := range in {
-		result.Answers = append(result.Answers, model.GameSessionAnswer{
-			QuestionID: item.QuestionID,
-			AnswerID:   item.AnswerID,
-			IsCorrect:  item.IsCorrect,
-		})
+	for _, item := range answers {
+		result.Answers = append(result.Answers, convertToSessionAnswer(item))
+	}
+	for _, item := range lifelines {
+		result.Lifelines = append(result.Lifelines, convertToLifelineUsage(item))
 	}
 
 	return result
 }
+
+func convertToSessionAnswer(in sqlxGameSession) model.GameSessionAnswer {
+	return model.GameSessionAnswer{
+		QuestionID:     in.QuestionID,
+		AnswerID:       in.AnswerID,
+		IsCorrect:      in.IsCorrect,
+		IdempotencyKey: in.IdempotencyKey,
+		CreatedAt:      in.CreatedAt,
+	}
+}
+
+func convertToLifelineUsage(in sqlxLifelineUsage) model.LifelineUsage {
+	return model.LifelineUsage{
+		QuestionID:       in.QuestionID,
+		Type:             model.LifelineType(in.Lifeline),
+		RemovedAnswerIDs: in.RemovedAnswerIDs,
+		CreatedAt:        in.CreatedAt,
+	}
+}
@@ -0,0 +1,120 @@
+package game
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"easy-quizy/pkg/structs/collections/slices"
+	"time"
+)
+
+type (
+	sqlxFunnelDay struct {
+		Day       time.Time `db:"day"`
+		Opened    int64     `db:"opened"`
+		Answered  int64     `db:"answered"`
+		Completed int64     `db:"completed"`
+		Resets    int64     `db:"resets"`
+	}
+)
+
+// RefreshFunnel пересобирает дневные агрегаты воронки начиная с дня from. Читаются только события
+// с начала дня from; первый ответ и первое завершение игрока проверяются по индексу на отсутствие
+// более ранних. Вызывать внутри транзакции.
+func (r *DefaultRepository) RefreshFunnel(ctx context.Context, from time.Time, computedAt time.Time) error {
+	const deleteQuery = `delete from easy_quizy_game_funnel_daily where day >= $1::date`
+
+	const insertQuery = `
+		insert into easy_quizy_game_funnel_daily (game_id, day, opened, answered, completed, resets, computed_at)
+		select
+			e.game_id,
+			e.day,
+			sum(e.opened),
+			sum(e.answered),
+			sum(e.completed),
+			sum(e.resets),
+			$2
+		from (
+			select game_id, (started_at at time zone 'UTC')::date as day, 1 as opened, 0 as answered, 0 as completed, 0 as resets
+			from easy_quizy_session
+			where attempt = 1 and started_at >= $1
+
+			union all
+
+			select a.game_id, (min(a.created_at) at time zone 'UTC')::date, 0, 1, 0, 0
+			from easy_quizy_game_session a
+			where a.created_at >= $1
+				and not exists (
+					select 1
+					from easy_quizy_game_session p
+					where p.game_id = a.game_id and p.player_id = a.player_id and p.created_at < $1
+				)
+			group by a.game_id, a.player_id
+
+			union all
+
+			select s.game_id, (min(s.finished_at) at time zone 'UTC')::date, 0, 0, 1, 0
+			from easy_quizy_session s
+			where s.finished_at >= $1
+				and not exists (
+					select 1
+					from easy_quizy_session p
+					where p.game_id = s.game_id and p.player_id = s.player_id and p.finished_at < $1
+				)
+			group by s.game_id, s.player_id
+
+			union all
+
+			select game_id, (started_at at time zone 'UTC')::date, 0, 0, 0, 1
+			from easy_quizy_session
+			where attempt > 1 and started_at >= $1
+		) e
+		group by e.game_id, e.day
+	`
+
+	from = from.UTC().Truncate(24 * time.Hour)
+	if _, err := r.db(ctx).ExecContext(ctx, deleteQuery, from.Format(time.DateOnly)); err != nil {
+		return err
+	}
+
+	_, err := r.db(ctx).ExecContext(ctx, insertQuery, from, computedAt)
+	return err
+}
+
+// GetFunnel возвращает только дни, по которым есть агрегаты
+func (r *DefaultRepository) GetFunnel(ctx context.Context, filter model.FunnelFilter) ([]model.FunnelDay, error) {
+	const query = `
+		select
+			day,
+			sum(opened)::bigint as opened,
+			sum(answered)::bigint as answered,
+			sum(completed)::bigint as completed,
+			sum(resets)::bigint as resets
+		from easy_quizy_game_funnel_daily
+		where ($1::uuid is null or game_id = $1)
+			and day between $2::date and $3::date
+		group by day
+		order by day
+	`
+
+	var result []sqlxFunnelDay
+	if err := r.db(ctx).SelectContext(
+		ctx,
+		&result,
+		query,
+		filter.GameID,
+		filter.From.UTC().Format(time.DateOnly),
+		filter.To.UTC().Format(time.DateOnly),
+	); err != nil {
+		return nil, err
+	}
+
+	return slices.SafeMap(result, func(item sqlxFunnelDay) model.FunnelDay {
+		return model.FunnelDay{
+			Day:       item.Day.UTC(),
+			Opened:    item.Opened,
+			Answered:  item.Answered,
+			Completed: item.Completed,
+			Resets:    item.Resets,
+		}
+	}), nil
+}
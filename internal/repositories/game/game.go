@@ -20,14 +20,6 @@ type (
 		Payload   []byte    `db:"payload"`
 		CreatedAt time.Time `db:"created_at"`
 	}
-
-	sqlxGameSession struct {
-		GameID     uuid.UUID `db:"game_id"`
-		PlayerID   uuid.UUID `db:"player_id"`
-		QuestionID int64     `db:"question_id"`
-		AnswerID   int64     `db:"answer_id"`
-		IsCorrect  bool      `db:"is_correct"`
-	}
 )
 
 func (r *DefaultRepository) GetGamesByIDs(ctx context.Context, ids []uuid.UUID) ([]model.Game, error) {
@@ -81,64 +73,3 @@ func (r *DefaultRepository) GetDailyGame(ctx context.Context) (model.Game, error
 
 	return convertToGame(result)
 }
-
-func (r *DefaultRepository) InsertGameSessionAnswer(ctx context.Context, gameID uuid.UUID, playerID uuid.UUID, data model.GameSessionAnswer) error {
-	const query = `
-		insert into easy_quizy_game_session
-		(game_id, player_id, question_id, answer_id, is_correct)
-		values ($1, $2, $3, $4, $5)
-	`
-
-	_, err := r.db(ctx).ExecContext(
-		ctx,
-		query,
-		gameID,
-		playerID,
-		data.QuestionID,
-		data.AnswerID,
-		data.IsCorrect,
-	)
-
-	return err
-}
-
-func (r *DefaultRepository) DeleteGameSessionAnswers(ctx context.Context, gameID uuid.UUID, playerID uuid.UUID) error {
-	const query = `
-		delete from easy_quizy_game_session 
-		where game_id = $1 and player_id = $2
-	`
-
-	_, err := r.db(ctx).ExecContext(
-		ctx,
-		query,
-		gameID,
-		playerID,
-	)
-
-	return err
-}
-
-func (r *DefaultRepository) GetGameSession(ctx context.Context, gameID uuid.UUID, playerID uuid.UUID) (model.GameSession, error) {
-	const query = `
-		select 
-			game_id,
-            player_id,
-            question_id,
-    		answer_id
-		from easy_quizy_game_session
-		where game_id = $1 and player_id = $2
-	`
-
-	var result []sqlxGameSession
-	if err := r.db(ctx).SelectContext(
-		ctx,
-		&result,
-		query,
-		gameID,
-		playerID,
-	); err != nil {
-		return model.GameSession{}, err
-	}
-
-	return convertToSession(result), nil
-}
@@ -0,0 +1,150 @@
+package game
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"easy-quizy/pkg/structs/collections/slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	sqlxPlayerStats struct {
+		GamesPlayed            int64    `db:"games_played"`
+		GamesCompleted         int64    `db:"games_completed"`
+		FinishedAttempts       int64    `db:"finished_attempts"`
+		BestScore              *int64   `db:"best_score"`
+		AverageDurationSeconds *float64 `db:"average_duration_seconds"`
+	}
+
+	sqlxAnswerStats struct {
+		TotalAnswers   int64 `db:"total_answers"`
+		CorrectAnswers int64 `db:"correct_answers"`
+	}
+
+	sqlxGameHistoryItem struct {
+		GameID     uuid.UUID `db:"game_id"`
+		Title      string    `db:"title"`
+		Score      *int64    `db:"score"`
+		ResultText *string   `db:"result_text"`
+		FinishedAt time.Time `db:"finished_at"`
+		Attempts   int64     `db:"attempts"`
+	}
+)
+
+func (r *DefaultRepository) GetPlayerStats(ctx context.Context, playerID uuid.UUID) (model.PlayerStats, error) {
+	const sessionsQuery = `
+		select
+			count(distinct s.game_id) filter (
+				where exists (select 1 from easy_quizy_game_session a where a.session_id = s.id)
+			) as games_played,
+			count(distinct s.game_id) filter (where s.finished_at is not null) as games_completed,
+			count(*) filter (where s.finished_at is not null) as finished_attempts,
+			max(s.score) as best_score,
+			avg(extract(epoch from s.finished_at - s.started_at)) filter (
+				where s.finished_at is not null
+			)::float8 as average_duration_seconds
+		from easy_quizy_session s
+		where s.player_id = $1
+	`
+
+	const answersQuery = `
+		select
+			count(*) as total_answers,
+			count(*) filter (where is_correct) as correct_answers
+		from easy_quizy_game_session
+		where player_id = $1
+	`
+
+	var sessions sqlxPlayerStats
+	if err := r.db(ctx).GetContext(ctx, &sessions, sessionsQuery, playerID); err != nil {
+		return model.PlayerStats{}, err
+	}
+
+	var answers sqlxAnswerStats
+	if err := r.db(ctx).GetContext(ctx, &answers, answersQuery, playerID); err != nil {
+		return model.PlayerStats{}, err
+	}
+
+	result := model.PlayerStats{
+		GamesPlayed:      sessions.GamesPlayed,
+		GamesCompleted:   sessions.GamesCompleted,
+		FinishedAttempts: sessions.FinishedAttempts,
+		TotalAnswers:     answers.TotalAnswers,
+		CorrectAnswers:   answers.CorrectAnswers,
+		BestScore:        sessions.BestScore,
+	}
+	if sessions.AverageDurationSeconds != nil {
+		duration := time.Duration(*sessions.AverageDurationSeconds * float64(time.Second))
+		result.AverageDuration = &duration
+	}
+
+	return result, nil
+}
+
+// GetPlayerHistory возвращает завершенные игры игрока, последние сверху
+func (r *DefaultRepository) GetPlayerHistory(ctx context.Context, playerID uuid.UUID, page model.Page) (model.GameHistory, error) {
+	const countQuery = `
+		select count(distinct game_id)
+		from easy_quizy_session
+		where player_id = $1 and finished_at is not null
+	`
+
+	const query = `
+		with finished as (
+			select distinct on (s.game_id)
+				s.game_id,
+				s.score,
+				s.result_text,
+				s.finished_at
+			from easy_quizy_session s
+			where s.player_id = $1 and s.finished_at is not null
+			order by s.game_id, s.finished_at desc
+		)
+		select
+			f.game_id,
+			coalesce(g.payload ->> 'name', '') as title,
+			f.score,
+			f.result_text,
+			f.finished_at,
+			(
+				select count(*)
+				from easy_quizy_session a
+				where a.game_id = f.game_id and a.player_id = $1
+			) as attempts
+		from finished f
+		inner join easy_quizy_game g on g.id = f.game_id
+		order by f.finished_at desc, f.game_id
+		limit $2 offset $3
+	`
+
+	var total int64
+	if err := r.db(ctx).GetContext(ctx, &total, countQuery, playerID); err != nil {
+		return model.GameHistory{}, err
+	}
+
+	var result []sqlxGameHistoryItem
+	if err := r.db(ctx).SelectContext(ctx, &result, query, playerID, page.Limit, page.Offset); err != nil {
+		return model.GameHistory{}, err
+	}
+
+	return model.GameHistory{
+		Items: slices.SafeMap(result, func(item sqlxGameHistoryItem) model.GameHistoryItem {
+			score := int64(0)
+			if item.Score != nil {
+				score = *item.Score
+			}
+
+			return model.GameHistoryItem{
+				GameID:     item.GameID,
+				Title:      item.Title,
+				Score:      score,
+				ResultText: item.ResultText,
+				FinishedAt: item.FinishedAt,
+				Attempts:   item.Attempts,
+			}
+		}),
+		Total: total,
+	}, nil
+}
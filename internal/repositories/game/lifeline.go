@@ -0,0 +1,94 @@
+package game
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// sqlxLifelineUsage строка из easy_quizy_session_lifeline
+type sqlxLifelineUsage struct {
+	QuestionID       int64         `db:"question_id"`
+	Lifeline         string        `db:"lifeline"`
+	RemovedAnswerIDs pq.Int64Array `db:"removed_answer_ids"`
+	CreatedAt        time.Time     `db:"created_at"`
+}
+
+// LockGameSession блокирует попытку до конца транзакции, чтобы параллельные запросы не превысили лимит подсказок
+func (r *DefaultRepository) LockGameSession(ctx context.Context, sessionID uuid.UUID) error {
+	const query = `
+		select id
+		from easy_quizy_session
+		where id = $1
+		for update
+	`
+
+	var id uuid.UUID
+	return r.db(ctx).GetContext(ctx, &id, query, sessionID)
+}
+
+// InsertLifelineUsage записывает использование подсказки; если подсказку на этом вопросе уже использовали,
+// возвращается первая запись, а флаг inserted ложен
+func (r *DefaultRepository) InsertLifelineUsage(ctx context.Context, sessionID uuid.UUID, usage model.LifelineUsage) (model.LifelineUsage, bool, error) {
+	const query = `
+		insert into easy_quizy_session_lifeline
+		(session_id, question_id, lifeline, removed_answer_ids, created_at)
+		values ($1, $2, $3, $4, $5)
+		on conflict (session_id, question_id, lifeline) do nothing
+		returning question_id, lifeline, removed_answer_ids, created_at
+	`
+
+	if usage.CreatedAt.IsZero() {
+		usage.CreatedAt = time.Now()
+	}
+
+	var result []sqlxLifelineUsage
+	err := r.db(ctx).SelectContext(
+		ctx,
+		&result,
+		query,
+		sessionID,
+		usage.QuestionID,
+		usage.Type,
+		pq.Int64Array(usage.RemovedAnswerIDs),
+		usage.CreatedAt,
+	)
+	if err != nil {
+		return model.LifelineUsage{}, false, err
+	}
+	if len(result) > 0 {
+		return convertToLifelineUsage(result[0]), true, nil
+	}
+
+	const selectQuery = `
+		select question_id, lifeline, removed_answer_ids, created_at
+		from easy_quizy_session_lifeline
+		where session_id = $1 and question_id = $2 and lifeline = $3
+	`
+
+	var existing sqlxLifelineUsage
+	if err := r.db(ctx).GetContext(ctx, &existing, selectQuery, sessionID, usage.QuestionID, usage.Type); err != nil {
+		return model.LifelineUsage{}, false, err
+	}
+
+	return convertToLifelineUsage(existing), false, nil
+}
+
+func (r *DefaultRepository) getLifelineUsages(ctx context.Context, sessionID uuid.UUID) ([]sqlxLifelineUsage, error) {
+	const query = `
+		select question_id, lifeline, removed_answer_ids, created_at
+		from easy_quizy_session_lifeline
+		where session_id = $1
+		order by id
+	`
+
+	var result []sqlxLifelineUsage
+	if err := r.db(ctx).SelectContext(ctx, &result, query, sessionID); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
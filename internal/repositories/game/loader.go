@@ -0,0 +1,56 @@
+package game
+
+import (
+	"easy-quizy/internal/model"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// LoadGamesFromDir читает квизы из *.json каталога. ID игры вычисляется из имени файла,
+// поэтому ссылки на игру не меняются между перезапусками.
+func LoadGamesFromDir(dir string) ([]model.Game, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	result := make([]model.Game, 0, len(paths))
+	for _, path := range paths {
+		payload, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var rg rawGame
+		if err := json.Unmarshal(payload, &rg); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+
+		game, err := rawToGame(rg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s: %w", path, err)
+		}
+
+		game.ID = uuid.NewSHA1(uuid.NameSpaceURL, []byte(filepath.Base(path)))
+		game.CreatedAt = info.ModTime()
+		game.Type = model.GameType(rg.Type)
+		if game.Type == "" {
+			game.Type = model.GameTypeClassic
+		}
+
+		result = append(result, game)
+	}
+
+	return result, nil
+}
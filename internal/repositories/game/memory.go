@@ -0,0 +1,1005 @@
+package game
+
+import (
+	"cmp"
+	"context"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// MemoryRepository хранит игры и ответы в памяти процесса, для тестов и демо-режима
+	MemoryRepository struct {
+		mu       sync.RWMutex
+		games    map[uuid.UUID]model.Game
+		daily    []uuid.UUID
+		sessions map[sessionKey][]*model.GameSession
+		stats    map[uuid.UUID][]model.QuestionStatsRow
+		funnel   map[funnelKey]model.FunnelDay
+		// merged попытки, перешедшие при слиянии аккаунтов, с игроком, от которого они пришли
+		merged map[uuid.UUID]uuid.UUID
+	}
+
+	funnelKey struct {
+		gameID uuid.UUID
+		day    time.Time
+	}
+
+	sessionKey struct {
+		gameID   uuid.UUID
+		playerID uuid.UUID
+	}
+)
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		games:    make(map[uuid.UUID]model.Game),
+		sessions: make(map[sessionKey][]*model.GameSession),
+		stats:    make(map[uuid.UUID][]model.QuestionStatsRow),
+		funnel:   make(map[funnelKey]model.FunnelDay),
+		merged:   make(map[uuid.UUID]uuid.UUID),
+	}
+}
+
+func (r *MemoryRepository) AddGame(game model.Game) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.games[game.ID] = game
+}
+
+// AddDailyGame ставит игру в очередь ежедневных, текущей считается первая добавленная
+func (r *MemoryRepository) AddDailyGame(gameID uuid.UUID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.daily = append(r.daily, gameID)
+}
+
+func (r *MemoryRepository) GetGamesByIDs(_ context.Context, ids []uuid.UUID) ([]model.Game, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]model.Game, 0, len(ids))
+	for _, id := range ids {
+		game, ok := r.games[id]
+		if !ok {
+			continue
+		}
+
+		result = append(result, game)
+	}
+
+	return result, nil
+}
+
+func (r *MemoryRepository) GetDailyGame(_ context.Context) (model.Game, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	game, ok := r.currentDaily()
+	if !ok {
+		return model.Game{}, contracts.ErrGameNotFound
+	}
+
+	return game, nil
+}
+
+// currentDaily первая существующая игра из очереди ежедневных, вызывается под блокировкой
+func (r *MemoryRepository) currentDaily() (model.Game, bool) {
+	for _, id := range r.daily {
+		if game, ok := r.games[id]; ok {
+			return game, true
+		}
+	}
+
+	return model.Game{}, false
+}
+
+func (r *MemoryRepository) GetLastGameSession(_ context.Context, gameID uuid.UUID, playerID uuid.UUID) (model.GameSession, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.lastSession(sessionKey{gameID: gameID, playerID: playerID})
+}
+
+func (r *MemoryRepository) CreateGameSession(_ context.Context, session model.GameSession) (model.GameSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := sessionKey{gameID: session.GameID, playerID: session.PlayerID}
+	for _, existing := range r.sessions[key] {
+		if existing.Attempt == session.Attempt {
+			return copySession(*existing), nil
+		}
+	}
+
+	stored := copySession(session)
+	r.sessions[key] = append(r.sessions[key], &stored)
+	return copySession(stored), nil
+}
+
+func (r *MemoryRepository) FinishGameSession(_ context.Context, sessionID uuid.UUID, finishedAt time.Time, result model.Result) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.findSession(sessionID)
+	if !ok || session.IsFinished() {
+		return false, nil
+	}
+
+	session.FinishedAt = &finishedAt
+	session.Score = &result.TotalScore
+	session.ResultText = &result.ResultText
+	return true, nil
+}
+
+func (r *MemoryRepository) InsertGameSessionAnswer(_ context.Context, session model.GameSession, data model.GameSessionAnswer) (model.GameSessionAnswer, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.findSession(session.ID)
+	if !ok {
+		return model.GameSessionAnswer{}, false, contracts.ErrSessionNotFound
+	}
+
+	if answer, answered := stored.IsAnswered(data.QuestionID); answered {
+		return answer, false, nil
+	}
+
+	if data.IdempotencyKey != nil {
+		if _, _, found := r.findByIdempotencyKey(session.PlayerID, *data.IdempotencyKey); found {
+			return model.GameSessionAnswer{}, false, contracts.ErrIdempotencyKeyReused
+		}
+	}
+
+	if data.CreatedAt.IsZero() {
+		data.CreatedAt = time.Now()
+	}
+
+	stored.Answers = append(stored.Answers, data)
+	return data, true, nil
+}
+
+func (r *MemoryRepository) GetGameSessionAnswerByIdempotencyKey(_ context.Context, playerID uuid.UUID, key string) (uuid.UUID, model.GameSessionAnswer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	gameID, answer, found := r.findByIdempotencyKey(playerID, key)
+	if !found {
+		return uuid.Nil, model.GameSessionAnswer{}, contracts.ErrAnswerNotFound
+	}
+
+	return gameID, answer, nil
+}
+
+func (r *MemoryRepository) lastSession(key sessionKey) (model.GameSession, error) {
+	sessions := r.sessions[key]
+	if len(sessions) == 0 {
+		return model.GameSession{}, contracts.ErrSessionNotFound
+	}
+
+	last := sessions[0]
+	for _, session := range sessions {
+		if session.Attempt > last.Attempt {
+			last = session
+		}
+	}
+
+	return copySession(*last), nil
+}
+
+func (r *MemoryRepository) findSession(sessionID uuid.UUID) (*model.GameSession, bool) {
+	for _, sessions := range r.sessions {
+		for _, session := range sessions {
+			if session.ID == sessionID {
+				return session, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+func (r *MemoryRepository) findByIdempotencyKey(playerID uuid.UUID, key string) (uuid.UUID, model.GameSessionAnswer, bool) {
+	for sKey, sessions := range r.sessions {
+		if sKey.playerID != playerID {
+			continue
+		}
+
+		for _, session := range sessions {
+			for _, answer := range session.Answers {
+				if answer.IdempotencyKey != nil && *answer.IdempotencyKey == key {
+					return sKey.gameID, answer, true
+				}
+			}
+		}
+	}
+
+	return uuid.Nil, model.GameSessionAnswer{}, false
+}
+
+// copySession отдает наружу копию, чтобы вызывающий код не менял хранилище в обход мьютекса
+func copySession(in model.GameSession) model.GameSession {
+	in.Answers = append([]model.GameSessionAnswer(nil), in.Answers...)
+	in.Lifelines = append([]model.LifelineUsage(nil), in.Lifelines...)
+	return in
+}
+
+// LockGameSession в памяти не нужен: InsertLifelineUsage и так выполняется под мьютексом
+func (r *MemoryRepository) LockGameSession(_ context.Context, _ uuid.UUID) error {
+	return nil
+}
+
+func (r *MemoryRepository) InsertLifelineUsage(_ context.Context, sessionID uuid.UUID, usage model.LifelineUsage) (model.LifelineUsage, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.findSession(sessionID)
+	if !ok {
+		return model.LifelineUsage{}, false, contracts.ErrSessionNotFound
+	}
+
+	if existing, used := stored.LifelineUsed(usage.QuestionID, usage.Type); used {
+		return existing, false, nil
+	}
+
+	if usage.CreatedAt.IsZero() {
+		usage.CreatedAt = time.Now()
+	}
+
+	stored.Lifelines = append(stored.Lifelines, usage)
+	return usage, true, nil
+}
+
+func (r *MemoryRepository) GetPlayerStats(_ context.Context, playerID uuid.UUID) (model.PlayerStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var (
+		result        model.PlayerStats
+		totalDuration time.Duration
+		played        = make(map[uuid.UUID]struct{})
+		completed     = make(map[uuid.UUID]struct{})
+	)
+	for key, sessions := range r.sessions {
+		if key.playerID != playerID {
+			continue
+		}
+
+		for _, session := range sessions {
+			if len(session.Answers) > 0 {
+				played[key.gameID] = struct{}{}
+			}
+			for _, answer := range session.Answers {
+				result.TotalAnswers++
+				if answer.IsCorrect {
+					result.CorrectAnswers++
+				}
+			}
+
+			if !session.IsFinished() {
+				continue
+			}
+
+			completed[key.gameID] = struct{}{}
+			result.FinishedAttempts++
+			totalDuration += *session.Duration()
+			if session.Score != nil && (result.BestScore == nil || *session.Score > *result.BestScore) {
+				result.BestScore = session.Score
+			}
+		}
+	}
+
+	result.GamesPlayed = int64(len(played))
+	result.GamesCompleted = int64(len(completed))
+	if result.FinishedAttempts > 0 {
+		average := totalDuration / time.Duration(result.FinishedAttempts)
+		result.AverageDuration = &average
+	}
+
+	return result, nil
+}
+
+func (r *MemoryRepository) GetPlayerHistory(_ context.Context, playerID uuid.UUID, page model.Page) (model.GameHistory, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var items []model.GameHistoryItem
+	for key, sessions := range r.sessions {
+		if key.playerID != playerID {
+			continue
+		}
+
+		var last *model.GameSession
+		for _, session := range sessions {
+			if session.IsFinished() && (last == nil || session.FinishedAt.After(*last.FinishedAt)) {
+				last = session
+			}
+		}
+		if last == nil {
+			continue
+		}
+
+		item := model.GameHistoryItem{
+			GameID:     key.gameID,
+			Title:      r.games[key.gameID].Title,
+			ResultText: last.ResultText,
+			FinishedAt: *last.FinishedAt,
+			Attempts:   int64(len(sessions)),
+		}
+		if last.Score != nil {
+			item.Score = *last.Score
+		}
+		items = append(items, item)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].FinishedAt.After(items[j].FinishedAt)
+	})
+
+	result := model.GameHistory{Total: int64(len(items))}
+	if page.Offset < int64(len(items)) {
+		end := min(page.Offset+page.Limit, int64(len(items)))
+		result.Items = items[page.Offset:end]
+	}
+
+	return result, nil
+}
+
+// GetCatalog упрощенный аналог полнотекстового поиска: каждое слово запроса должно встречаться в названии или описании
+func (r *MemoryRepository) GetCatalog(_ context.Context, playerID uuid.UUID, filter model.CatalogFilter) (model.Catalog, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// ежедневные игры в памяти не закрываются, поэтому из них вышла только текущая
+	daily, _ := r.currentDaily()
+
+	games := make([]model.Game, 0, len(r.games))
+	for _, game := range r.games {
+		if game.Type == model.GameTypeDaily && game.ID != daily.ID {
+			continue
+		}
+		if matchCatalogFilter(game, filter) {
+			games = append(games, game)
+		}
+	}
+
+	sort.Slice(games, func(i, j int) bool {
+		if !games[i].CreatedAt.Equal(games[j].CreatedAt) {
+			return games[i].CreatedAt.After(games[j].CreatedAt)
+		}
+
+		return games[i].Title < games[j].Title
+	})
+
+	result := model.Catalog{Total: int64(len(games))}
+	if filter.Page.Offset >= int64(len(games)) {
+		return result, nil
+	}
+
+	end := min(filter.Page.Offset+filter.Page.Limit, int64(len(games)))
+	for _, game := range games[filter.Page.Offset:end] {
+		item := model.CatalogItemFromGame(game)
+		for _, session := range r.sessions[sessionKey{gameID: game.ID, playerID: playerID}] {
+			item.Played = item.Played || len(session.Answers) > 0
+			item.Completed = item.Completed || session.IsFinished()
+		}
+
+		result.Items = append(result.Items, item)
+	}
+
+	return result, nil
+}
+
+func matchCatalogFilter(game model.Game, filter model.CatalogFilter) bool {
+	if filter.Type != nil {
+		if game.Type != *filter.Type {
+			return false
+		}
+	} else if game.Type == model.GameTypeDaily {
+		return false
+	}
+
+	if !equalOptional(game.Category, filter.Category) ||
+		!equalOptional(game.Difficulty, filter.Difficulty) ||
+		!equalOptional(game.Language, filter.Language) {
+		return false
+	}
+
+	for _, tag := range filter.Tags {
+		if !slices.Contains(game.Tags, tag) {
+			return false
+		}
+	}
+
+	text := strings.ToLower(game.Title)
+	if game.Description != nil {
+		text += " " + strings.ToLower(*game.Description)
+	}
+	for _, word := range strings.Fields(strings.ToLower(filter.Query)) {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// equalOptional пустой фильтр пропускает любое значение
+func equalOptional(value *string, filter *string) bool {
+	if filter == nil {
+		return true
+	}
+
+	return value != nil && *value == *filter
+}
+
+func (r *MemoryRepository) GetUnplayedGames(_ context.Context, playerID uuid.UUID, limit int64) ([]model.Game, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []model.Game
+	for _, game := range r.games {
+		if game.Type == model.GameTypeDaily {
+			continue
+		}
+
+		played := false
+		for _, session := range r.sessions[sessionKey{gameID: game.ID, playerID: playerID}] {
+			played = played || len(session.Answers) > 0
+		}
+		if !played {
+			result = append(result, game)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+	if int64(len(result)) > limit {
+		result = result[:limit]
+	}
+
+	return result, nil
+}
+
+func (r *MemoryRepository) GetPlayerCompletedGames(_ context.Context, playerID uuid.UUID) ([]model.CompletedGame, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []model.CompletedGame
+	for key, sessions := range r.sessions {
+		if key.playerID != playerID {
+			continue
+		}
+
+		completed := false
+		item := model.CompletedGame{
+			GameID:         key.gameID,
+			Category:       r.games[key.gameID].Category,
+			QuestionsCount: int64(len(r.games[key.gameID].Questions)),
+		}
+		for _, session := range sessions {
+			if !session.IsFinished() {
+				continue
+			}
+
+			completed = true
+			if session.Score != nil && *session.Score > item.BestScore {
+				item.BestScore = *session.Score
+			}
+		}
+		if completed {
+			result = append(result, item)
+		}
+	}
+
+	return result, nil
+}
+
+func (r *MemoryRepository) CountCompletions(_ context.Context, gameIDs []uuid.UUID, playerIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make(map[uuid.UUID]int64, len(gameIDs))
+	for _, gameID := range gameIDs {
+		for _, playerID := range playerIDs {
+			for _, session := range r.sessions[sessionKey{gameID: gameID, playerID: playerID}] {
+				if session.IsFinished() {
+					result[gameID]++
+					break
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (r *MemoryRepository) HasFinishedSession(_ context.Context, gameID uuid.UUID, playerID uuid.UUID) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, session := range r.sessions[sessionKey{gameID: gameID, playerID: playerID}] {
+		if session.IsFinished() {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (r *MemoryRepository) CountCorrectAnswers(_ context.Context, playerID uuid.UUID) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result int64
+	for key, sessions := range r.sessions {
+		if key.playerID != playerID {
+			continue
+		}
+
+		for _, session := range sessions {
+			for _, answer := range session.Answers {
+				if answer.IsCorrect {
+					result++
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (r *MemoryRepository) GetDailyCompletionDays(_ context.Context, playerID uuid.UUID, since time.Time) ([]time.Time, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	days := make(map[time.Time]struct{})
+	for key, sessions := range r.sessions {
+		if key.playerID != playerID || r.games[key.gameID].Type != model.GameTypeDaily {
+			continue
+		}
+
+		for _, session := range sessions {
+			if session.IsFinished() && !session.FinishedAt.Before(since) {
+				days[truncateDay(*session.FinishedAt)] = struct{}{}
+			}
+		}
+	}
+
+	result := make([]time.Time, 0, len(days))
+	for day := range days {
+		result = append(result, day)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].After(result[j])
+	})
+
+	return result, nil
+}
+
+// RefreshQuestionStats повторяет расчет DefaultRepository.RefreshQuestionStats по данным в памяти
+func (r *MemoryRepository) GetGameSession(_ context.Context, sessionID uuid.UUID) (model.GameSession, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	session, ok := r.findSession(sessionID)
+	if !ok {
+		return model.GameSession{}, contracts.ErrSessionNotFound
+	}
+
+	return copySession(*session), nil
+}
+
+func (r *MemoryRepository) CountPlayerAnswers(_ context.Context, playerID uuid.UUID, since time.Time) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result int64
+	for key, sessions := range r.sessions {
+		if key.playerID != playerID {
+			continue
+		}
+
+		for _, session := range sessions {
+			for _, answer := range session.Answers {
+				if !answer.CreatedAt.Before(since) {
+					result++
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (r *MemoryRepository) CountGameSessions(_ context.Context, gameID uuid.UUID, playerID uuid.UUID, since time.Time) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result int64
+	for _, session := range r.sessions[sessionKey{gameID: gameID, playerID: playerID}] {
+		if !session.StartedAt.Before(since) {
+			result++
+		}
+	}
+
+	return result, nil
+}
+
+func (r *MemoryRepository) GetFinishedSessions(
+	_ context.Context,
+	gameID uuid.UUID,
+	playerIDs []uuid.UUID,
+	since time.Time,
+) ([]model.GameSession, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []model.GameSession
+	for _, playerID := range playerIDs {
+		for _, session := range r.sessions[sessionKey{gameID: gameID, playerID: playerID}] {
+			if session.IsFinished() && !session.FinishedAt.Before(since) {
+				result = append(result, copySession(*session))
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].FinishedAt.Before(*result[j].FinishedAt)
+	})
+
+	return result, nil
+}
+
+func (r *MemoryRepository) RefreshQuestionStats(_ context.Context, abandonedBefore time.Time, computedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	type questionKey struct {
+		gameID     uuid.UUID
+		questionID int64
+	}
+
+	rows := make(map[questionKey]*model.QuestionStatsRow)
+	durations := make(map[questionKey][]time.Duration)
+	row := func(key questionKey) *model.QuestionStatsRow {
+		if item, ok := rows[key]; ok {
+			return item
+		}
+
+		item := &model.QuestionStatsRow{
+			GameID:       key.gameID,
+			QuestionID:   key.questionID,
+			OptionCounts: make(map[int64]int64),
+			ComputedAt:   computedAt,
+		}
+		rows[key] = item
+		return item
+	}
+
+	for key, sessions := range r.sessions {
+		for _, session := range sessions {
+			answers := append([]model.GameSessionAnswer(nil), session.Answers...)
+			sort.Slice(answers, func(i, j int) bool {
+				return answers[i].CreatedAt.Before(answers[j].CreatedAt)
+			})
+
+			lastActivity := session.StartedAt
+			for _, answer := range answers {
+				qKey := questionKey{gameID: key.gameID, questionID: answer.QuestionID}
+				item := row(qKey)
+				item.OptionCounts[answer.AnswerID]++
+				item.Answers++
+				item.Reached++
+				if answer.IsCorrect {
+					item.Correct++
+				}
+
+				durations[qKey] = append(durations[qKey], answer.CreatedAt.Sub(lastActivity))
+				lastActivity = answer.CreatedAt
+			}
+
+			if session.IsFinished() || !lastActivity.Before(abandonedBefore) {
+				continue
+			}
+
+			for _, question := range r.games[key.gameID].Questions {
+				if _, ok := session.IsAnswered(question.ID); ok {
+					continue
+				}
+
+				item := row(questionKey{gameID: key.gameID, questionID: question.ID})
+				item.Dropped++
+				item.Reached++
+				break
+			}
+		}
+	}
+
+	r.stats = make(map[uuid.UUID][]model.QuestionStatsRow)
+	for key, item := range rows {
+		if values := durations[key]; len(values) > 0 {
+			median := medianDuration(values)
+			item.MedianAnswerTime = &median
+		}
+
+		r.stats[key.gameID] = append(r.stats[key.gameID], *item)
+	}
+	for _, items := range r.stats {
+		sort.Slice(items, func(i, j int) bool {
+			return items[i].QuestionID < items[j].QuestionID
+		})
+	}
+
+	return nil
+}
+
+func (r *MemoryRepository) GetQuestionStats(_ context.Context, gameID uuid.UUID) ([]model.QuestionStatsRow, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]model.QuestionStatsRow(nil), r.stats[gameID]...), nil
+}
+
+// medianDuration медиана с интерполяцией, как percentile_cont(0.5)
+func medianDuration(values []time.Duration) time.Duration {
+	sorted := append([]time.Duration(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[middle]
+	}
+
+	return (sorted[middle-1] + sorted[middle]) / 2
+}
+
+// RefreshFunnel повторяет расчет DefaultRepository.RefreshFunnel по данным в памяти
+func (r *MemoryRepository) RefreshFunnel(_ context.Context, from time.Time, _ time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	from = truncateDay(from)
+	for key := range r.funnel {
+		if !key.day.Before(from) {
+			delete(r.funnel, key)
+		}
+	}
+
+	add := func(gameID uuid.UUID, at time.Time, apply func(day *model.FunnelDay)) {
+		key := funnelKey{gameID: gameID, day: truncateDay(at)}
+		if key.day.Before(from) {
+			return
+		}
+
+		day := r.funnel[key]
+		day.Day = key.day
+		apply(&day)
+		r.funnel[key] = day
+	}
+
+	for key, sessions := range r.sessions {
+		var firstAnswer, firstFinish *time.Time
+		for _, session := range sessions {
+			if session.Attempt == 1 {
+				add(key.gameID, session.StartedAt, func(day *model.FunnelDay) { day.Opened++ })
+			} else {
+				add(key.gameID, session.StartedAt, func(day *model.FunnelDay) { day.Resets++ })
+			}
+
+			for _, answer := range session.Answers {
+				if firstAnswer == nil || answer.CreatedAt.Before(*firstAnswer) {
+					firstAnswer = &answer.CreatedAt
+				}
+			}
+			if session.IsFinished() && (firstFinish == nil || session.FinishedAt.Before(*firstFinish)) {
+				firstFinish = session.FinishedAt
+			}
+		}
+
+		if firstAnswer != nil {
+			add(key.gameID, *firstAnswer, func(day *model.FunnelDay) { day.Answered++ })
+		}
+		if firstFinish != nil {
+			add(key.gameID, *firstFinish, func(day *model.FunnelDay) { day.Completed++ })
+		}
+	}
+
+	return nil
+}
+
+func (r *MemoryRepository) GetFunnel(_ context.Context, filter model.FunnelFilter) ([]model.FunnelDay, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	from, to := truncateDay(filter.From), truncateDay(filter.To)
+	days := make(map[time.Time]*model.FunnelDay)
+	for key, item := range r.funnel {
+		if filter.GameID != nil && key.gameID != *filter.GameID {
+			continue
+		}
+		if key.day.Before(from) || key.day.After(to) {
+			continue
+		}
+
+		day, ok := days[key.day]
+		if !ok {
+			day = &model.FunnelDay{Day: key.day}
+			days[key.day] = day
+		}
+		day.Opened += item.Opened
+		day.Answered += item.Answered
+		day.Completed += item.Completed
+		day.Resets += item.Resets
+	}
+
+	result := make([]model.FunnelDay, 0, len(days))
+	for _, day := range days {
+		result = append(result, *day)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Day.Before(result[j].Day)
+	})
+
+	return result, nil
+}
+
+// truncateDay начало дня по UTC
+func truncateDay(at time.Time) time.Time {
+	return at.UTC().Truncate(24 * time.Hour)
+}
+
+// GetOrphanPlayers в памяти пустой: источник создается под мьютексом до первой попытки, осиротевших попыток не бывает
+func (r *MemoryRepository) GetOrphanPlayers(_ context.Context) ([]model.OrphanPlayer, error) {
+	return nil, nil
+}
+
+func (r *MemoryRepository) ReassignSessions(_ context.Context, from uuid.UUID, to uuid.UUID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var moved int64
+	for key, sessions := range r.sessions {
+		if key.playerID != from {
+			continue
+		}
+
+		target := sessionKey{gameID: key.gameID, playerID: to}
+		var maxAttempt int64
+		for _, session := range r.sessions[target] {
+			maxAttempt = max(maxAttempt, session.Attempt)
+		}
+
+		sorted := slices.Clone(sessions)
+		slices.SortFunc(sorted, func(a, b *model.GameSession) int {
+			return cmp.Compare(a.Attempt, b.Attempt)
+		})
+		r.resetIdempotencyKeys(sorted, to)
+		for i, session := range sorted {
+			session.PlayerID = to
+			session.Attempt = maxAttempt + int64(i) + 1
+		}
+
+		delete(r.sessions, key)
+		r.sessions[target] = append(r.sessions[target], sorted...)
+		moved += int64(len(sorted))
+	}
+
+	return moved, nil
+}
+
+func (r *MemoryRepository) MergeSessions(_ context.Context, from uuid.UUID, to uuid.UUID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var moved int64
+	for key, sessions := range r.sessions {
+		if key.playerID != from {
+			continue
+		}
+
+		for _, session := range sessions {
+			if _, ok := r.merged[session.ID]; !ok {
+				r.merged[session.ID] = from
+			}
+		}
+
+		r.resetIdempotencyKeys(sessions, to)
+		target := sessionKey{gameID: key.gameID, playerID: to}
+		merged := append(slices.Clone(r.sessions[target]), sessions...)
+		best := bestSession(merged)
+		slices.SortFunc(merged, func(a, b *model.GameSession) int {
+			if a.IsFinished() != b.IsFinished() {
+				return cmp.Compare(boolToInt(!a.IsFinished()), boolToInt(!b.IsFinished()))
+			}
+			if a == best || b == best {
+				return cmp.Compare(boolToInt(a == best), boolToInt(b == best))
+			}
+			if byStart := a.StartedAt.Compare(b.StartedAt); byStart != 0 {
+				return byStart
+			}
+			return cmp.Compare(a.Attempt, b.Attempt)
+		})
+		for i, session := range merged {
+			session.PlayerID = to
+			session.Attempt = int64(i) + 1
+		}
+
+		delete(r.sessions, key)
+		r.sessions[target] = merged
+		moved += int64(len(sessions))
+	}
+
+	return moved, nil
+}
+
+func (r *MemoryRepository) CountOwnAttempts(_ context.Context, gameID uuid.UUID, playerID uuid.UUID) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result int64
+	for _, session := range r.sessions[sessionKey{gameID: gameID, playerID: playerID}] {
+		if _, ok := r.merged[session.ID]; !ok {
+			result++
+		}
+	}
+
+	return result, nil
+}
+
+// resetIdempotencyKeys сбрасывает ключи ответов, которые уже использовал игрок playerID
+func (r *MemoryRepository) resetIdempotencyKeys(sessions []*model.GameSession, playerID uuid.UUID) {
+	for _, session := range sessions {
+		for i, answer := range session.Answers {
+			if answer.IdempotencyKey == nil {
+				continue
+			}
+			if _, _, found := r.findByIdempotencyKey(playerID, *answer.IdempotencyKey); found {
+				session.Answers[i].IdempotencyKey = nil
+			}
+		}
+	}
+}
+
+// bestSession завершенная попытка с наибольшим счетом, при равенстве — раньше завершенная; nil, если завершенных нет
+func bestSession(sessions []*model.GameSession) *model.GameSession {
+	var best *model.GameSession
+	for _, session := range sessions {
+		if !session.IsFinished() {
+			continue
+		}
+		if best == nil || betterSession(session, best) {
+			best = session
+		}
+	}
+
+	return best
+}
+
+func betterSession(a, b *model.GameSession) bool {
+	if *a.Score != *b.Score {
+		return *a.Score > *b.Score
+	}
+
+	return a.FinishedAt.Before(*b.FinishedAt)
+}
+
+func boolToInt(value bool) int {
+	if value {
+		return 1
+	}
+
+	return 0
+}
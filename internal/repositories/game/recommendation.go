@@ -0,0 +1,110 @@
+package game
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"easy-quizy/pkg/structs/collections/slices"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type (
+	sqlxCompletedGame struct {
+		GameID         uuid.UUID `db:"game_id"`
+		Category       *string   `db:"category"`
+		BestScore      int64     `db:"best_score"`
+		QuestionsCount int64     `db:"questions_count"`
+	}
+
+	sqlxGameCount struct {
+		GameID uuid.UUID `db:"game_id"`
+		Count  int64     `db:"count"`
+	}
+)
+
+// GetUnplayedGames возвращает до limit самых свежих игр, в которых игрок не ответил ни на один вопрос.
+// Ежедневные игры не рекомендуются.
+func (r *DefaultRepository) GetUnplayedGames(ctx context.Context, playerID uuid.UUID, limit int64) ([]model.Game, error) {
+	const query = `
+		select
+			g.id,
+			g.type,
+			g.payload,
+			g.created_at
+		from easy_quizy_game g
+		where g.type <> $2
+			and not exists (
+				select 1
+				from easy_quizy_game_session a
+				where a.game_id = g.id and a.player_id = $1
+			)
+		order by g.created_at desc, g.id
+		limit $3
+	`
+
+	var result []sqlxGame
+	if err := r.db(ctx).SelectContext(ctx, &result, query, playerID, model.GameTypeDaily, limit); err != nil {
+		return nil, err
+	}
+
+	return slices.Map(result, func(i sqlxGame) (model.Game, error) {
+		return convertToGame(i)
+	})
+}
+
+func (r *DefaultRepository) GetPlayerCompletedGames(ctx context.Context, playerID uuid.UUID) ([]model.CompletedGame, error) {
+	const query = `
+		select
+			s.game_id,
+			g.payload ->> 'category' as category,
+			max(coalesce(s.score, 0)) as best_score,
+			jsonb_array_length(g.payload -> 'questions') as questions_count
+		from easy_quizy_session s
+		inner join easy_quizy_game g on g.id = s.game_id
+		where s.player_id = $1 and s.finished_at is not null
+		group by s.game_id, g.payload
+	`
+
+	var result []sqlxCompletedGame
+	if err := r.db(ctx).SelectContext(ctx, &result, query, playerID); err != nil {
+		return nil, err
+	}
+
+	return slices.SafeMap(result, func(i sqlxCompletedGame) model.CompletedGame {
+		return model.CompletedGame{
+			GameID:         i.GameID,
+			Category:       i.Category,
+			BestScore:      i.BestScore,
+			QuestionsCount: i.QuestionsCount,
+		}
+	}), nil
+}
+
+// CountCompletions считает, сколько игроков из playerIDs завершили каждую из игр
+func (r *DefaultRepository) CountCompletions(ctx context.Context, gameIDs []uuid.UUID, playerIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	result := make(map[uuid.UUID]int64, len(gameIDs))
+	if len(gameIDs) == 0 || len(playerIDs) == 0 {
+		return result, nil
+	}
+
+	const query = `
+		select
+			game_id,
+			count(distinct player_id) as count
+		from easy_quizy_session
+		where game_id = any($1) and player_id = any($2) and finished_at is not null
+		group by game_id
+	`
+
+	var rows []sqlxGameCount
+	if err := r.db(ctx).SelectContext(ctx, &rows, query, pq.Array(gameIDs), pq.Array(playerIDs)); err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		result[row.GameID] = row.Count
+	}
+
+	return result, nil
+}
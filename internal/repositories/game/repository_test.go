@@ -0,0 +1,653 @@
+package game
+
+import (
+	"context"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"easy-quizy/internal/pgtest"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+func newTestRepository(t *testing.T) (*DefaultRepository, *sqlx.DB) {
+	t.Helper()
+
+	db := pgtest.Start(t)
+	return NewRepository(db, trmsqlx.DefaultCtxGetter), db
+}
+
+func createSession(t *testing.T, repo *DefaultRepository, gameID, playerID uuid.UUID, attempt int64, startedAt time.Time) model.GameSession {
+	t.Helper()
+
+	session, err := repo.CreateGameSession(context.Background(), model.GameSession{
+		ID:        uuid.New(),
+		GameID:    gameID,
+		PlayerID:  playerID,
+		Attempt:   attempt,
+		StartedAt: startedAt,
+	})
+	if err != nil {
+		t.Fatalf("CreateGameSession: %v", err)
+	}
+
+	return session
+}
+
+func finishSession(t *testing.T, repo *DefaultRepository, sessionID uuid.UUID, finishedAt time.Time, score int64) {
+	t.Helper()
+
+	if _, err := repo.FinishGameSession(context.Background(), sessionID, finishedAt, model.Result{TotalScore: score, ResultText: "done"}); err != nil {
+		t.Fatalf("FinishGameSession: %v", err)
+	}
+}
+
+func key(value string) *string {
+	return &value
+}
+
+func TestCreateGameSession(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+
+	pgtest.Truncate(t, db)
+	gameID := pgtest.InsertGame(t, db, model.GameTypeClassic, 2)
+	playerID := uuid.New()
+	first := createSession(t, repo, gameID, playerID, 1, time.Now())
+
+	tests := []struct {
+		name        string
+		attempt     int64
+		wantCreated bool
+	}{
+		{name: "next attempt is created", attempt: 2, wantCreated: true},
+		{name: "taken attempt returns the existing session", attempt: 1, wantCreated: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := uuid.New()
+			got, err := repo.CreateGameSession(ctx, model.GameSession{
+				ID:        id,
+				GameID:    gameID,
+				PlayerID:  playerID,
+				Attempt:   tt.attempt,
+				StartedAt: time.Now(),
+			})
+			if err != nil {
+				t.Fatalf("CreateGameSession: %v", err)
+			}
+
+			want := first.ID
+			if tt.wantCreated {
+				want = id
+			}
+			if got.ID != want || got.Attempt != tt.attempt {
+				t.Errorf("CreateGameSession = %s #%d, want %s #%d", got.ID, got.Attempt, want, tt.attempt)
+			}
+		})
+	}
+}
+
+func TestGetLastGameSession(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	pgtest.Truncate(t, db)
+	gameID := pgtest.InsertGame(t, db, model.GameTypeClassic, 2)
+	alice, bob := uuid.New(), uuid.New()
+	createSession(t, repo, gameID, alice, 1, now.Add(-time.Hour))
+	last := createSession(t, repo, gameID, alice, 2, now)
+	if _, _, err := repo.InsertGameSessionAnswer(ctx, last, model.GameSessionAnswer{QuestionID: 0, AnswerID: 0, IsCorrect: true}); err != nil {
+		t.Fatalf("InsertGameSessionAnswer: %v", err)
+	}
+	if _, _, err := repo.InsertLifelineUsage(ctx, last.ID, model.LifelineUsage{QuestionID: 1, Type: model.LifelineFiftyFifty, RemovedAnswerIDs: []int64{1}}); err != nil {
+		t.Fatalf("InsertLifelineUsage: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		playerID      uuid.UUID
+		wantID        uuid.UUID
+		wantAnswers   int
+		wantLifelines int
+		wantErr       error
+	}{
+		{name: "last attempt with answers and lifelines", playerID: alice, wantID: last.ID, wantAnswers: 1, wantLifelines: 1},
+		{name: "player without sessions", playerID: bob, wantErr: contracts.ErrSessionNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.GetLastGameSession(ctx, gameID, tt.playerID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetLastGameSession error = %v, want %v", err, tt.wantErr)
+			}
+			if got.ID != tt.wantID || len(got.Answers) != tt.wantAnswers || len(got.Lifelines) != tt.wantLifelines {
+				t.Errorf("GetLastGameSession = %s with %d answers and %d lifelines, want %s with %d and %d",
+					got.ID, len(got.Answers), len(got.Lifelines), tt.wantID, tt.wantAnswers, tt.wantLifelines)
+			}
+		})
+	}
+}
+
+func TestFinishGameSession(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	pgtest.Truncate(t, db)
+	gameID := pgtest.InsertGame(t, db, model.GameTypeClassic, 2)
+	playerID := uuid.New()
+	session := createSession(t, repo, gameID, playerID, 1, now)
+
+	tests := []struct {
+		name         string
+		wantFinished bool
+	}{
+		{name: "first finish", wantFinished: true},
+		{name: "second finish is ignored", wantFinished: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finished, err := repo.FinishGameSession(ctx, session.ID, now, model.Result{TotalScore: 2})
+			if err != nil {
+				t.Fatalf("FinishGameSession: %v", err)
+			}
+			if finished != tt.wantFinished {
+				t.Errorf("FinishGameSession = %v, want %v", finished, tt.wantFinished)
+			}
+
+			has, err := repo.HasFinishedSession(ctx, gameID, playerID)
+			if err != nil {
+				t.Fatalf("HasFinishedSession: %v", err)
+			}
+			if !has {
+				t.Error("HasFinishedSession = false, want true")
+			}
+		})
+	}
+}
+
+func TestInsertGameSessionAnswer(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		first        model.GameSessionAnswer
+		second       model.GameSessionAnswer
+		wantInserted bool
+		wantAnswerID int64
+		wantErr      error
+	}{
+		{
+			name:         "another question",
+			first:        model.GameSessionAnswer{QuestionID: 0, AnswerID: 0, IsCorrect: true},
+			second:       model.GameSessionAnswer{QuestionID: 1, AnswerID: 1},
+			wantInserted: true,
+			wantAnswerID: 1,
+		},
+		{
+			name:         "repeated answer returns the first one",
+			first:        model.GameSessionAnswer{QuestionID: 0, AnswerID: 0, IsCorrect: true},
+			second:       model.GameSessionAnswer{QuestionID: 0, AnswerID: 1},
+			wantInserted: false,
+			wantAnswerID: 0,
+		},
+		{
+			name:    "idempotency key reused for another question",
+			first:   model.GameSessionAnswer{QuestionID: 0, AnswerID: 0, IsCorrect: true, IdempotencyKey: key("k1")},
+			second:  model.GameSessionAnswer{QuestionID: 1, AnswerID: 0, IsCorrect: true, IdempotencyKey: key("k1")},
+			wantErr: contracts.ErrIdempotencyKeyReused,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pgtest.Truncate(t, db)
+			gameID := pgtest.InsertGame(t, db, model.GameTypeClassic, 2)
+			session := createSession(t, repo, gameID, uuid.New(), 1, time.Now())
+
+			if _, inserted, err := repo.InsertGameSessionAnswer(ctx, session, tt.first); err != nil || !inserted {
+				t.Fatalf("InsertGameSessionAnswer = %v, %v, want inserted", inserted, err)
+			}
+
+			got, inserted, err := repo.InsertGameSessionAnswer(ctx, session, tt.second)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("InsertGameSessionAnswer error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if inserted != tt.wantInserted || got.AnswerID != tt.wantAnswerID {
+				t.Errorf("InsertGameSessionAnswer = answer %d, inserted %v, want answer %d, inserted %v",
+					got.AnswerID, inserted, tt.wantAnswerID, tt.wantInserted)
+			}
+		})
+	}
+}
+
+func TestGetGameSessionAnswerByIdempotencyKey(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+
+	pgtest.Truncate(t, db)
+	gameID := pgtest.InsertGame(t, db, model.GameTypeClassic, 2)
+	alice, bob := uuid.New(), uuid.New()
+	session := createSession(t, repo, gameID, alice, 1, time.Now())
+	if _, _, err := repo.InsertGameSessionAnswer(ctx, session, model.GameSessionAnswer{QuestionID: 1, AnswerID: 0, IsCorrect: true, IdempotencyKey: key("k1")}); err != nil {
+		t.Fatalf("InsertGameSessionAnswer: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		playerID       uuid.UUID
+		key            string
+		wantQuestionID int64
+		wantErr        error
+	}{
+		{name: "known key", playerID: alice, key: "k1", wantQuestionID: 1},
+		{name: "unknown key", playerID: alice, key: "k2", wantErr: contracts.ErrAnswerNotFound},
+		{name: "key of another player", playerID: bob, key: "k1", wantErr: contracts.ErrAnswerNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotGameID, got, err := repo.GetGameSessionAnswerByIdempotencyKey(ctx, tt.playerID, tt.key)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetGameSessionAnswerByIdempotencyKey error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (gotGameID != gameID || got.QuestionID != tt.wantQuestionID) {
+				t.Errorf("GetGameSessionAnswerByIdempotencyKey = %s/%d, want %s/%d", gotGameID, got.QuestionID, gameID, tt.wantQuestionID)
+			}
+		})
+	}
+}
+
+func TestInsertLifelineUsage(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+
+	pgtest.Truncate(t, db)
+	gameID := pgtest.InsertGame(t, db, model.GameTypeClassic, 2)
+	session := createSession(t, repo, gameID, uuid.New(), 1, time.Now())
+
+	tests := []struct {
+		name         string
+		usage        model.LifelineUsage
+		wantInserted bool
+		wantRemoved  []int64
+	}{
+		{
+			name:         "first usage",
+			usage:        model.LifelineUsage{QuestionID: 0, Type: model.LifelineFiftyFifty, RemovedAnswerIDs: []int64{1}},
+			wantInserted: true,
+			wantRemoved:  []int64{1},
+		},
+		{
+			name:         "repeated usage returns the first one",
+			usage:        model.LifelineUsage{QuestionID: 0, Type: model.LifelineFiftyFifty, RemovedAnswerIDs: []int64{0}},
+			wantInserted: false,
+			wantRemoved:  []int64{1},
+		},
+		{
+			name:         "another lifeline on the same question",
+			usage:        model.LifelineUsage{QuestionID: 0, Type: model.LifelineHint},
+			wantInserted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, inserted, err := repo.InsertLifelineUsage(ctx, session.ID, tt.usage)
+			if err != nil {
+				t.Fatalf("InsertLifelineUsage: %v", err)
+			}
+			if inserted != tt.wantInserted || len(got.RemovedAnswerIDs) != len(tt.wantRemoved) ||
+				(len(tt.wantRemoved) > 0 && got.RemovedAnswerIDs[0] != tt.wantRemoved[0]) {
+				t.Errorf("InsertLifelineUsage = %v, inserted %v, want removed %v, inserted %v",
+					got.RemovedAnswerIDs, inserted, tt.wantRemoved, tt.wantInserted)
+			}
+		})
+	}
+}
+
+func TestGetGames(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+
+	pgtest.Truncate(t, db)
+	if _, err := repo.GetDailyGame(ctx); !errors.Is(err, contracts.ErrGameNotFound) {
+		t.Fatalf("GetDailyGame without daily games error = %v, want %v", err, contracts.ErrGameNotFound)
+	}
+
+	classic := pgtest.InsertGame(t, db, model.GameTypeClassic, 2)
+	daily := pgtest.InsertGame(t, db, model.GameTypeDaily, 3)
+	pgtest.InsertDailyGame(t, db, daily)
+
+	tests := []struct {
+		name  string
+		check func(t *testing.T)
+	}{
+		{
+			name: "GetGamesByIDs skips unknown ids",
+			check: func(t *testing.T) {
+				got, err := repo.GetGamesByIDs(ctx, []uuid.UUID{classic, uuid.New()})
+				if err != nil {
+					t.Fatalf("GetGamesByIDs: %v", err)
+				}
+				if len(got) != 1 || got[0].ID != classic || len(got[0].Questions) != 2 {
+					t.Errorf("GetGamesByIDs = %+v, want only %s with 2 questions", got, classic)
+				}
+			},
+		},
+		{
+			name: "GetDailyGame",
+			check: func(t *testing.T) {
+				got, err := repo.GetDailyGame(ctx)
+				if err != nil {
+					t.Fatalf("GetDailyGame: %v", err)
+				}
+				if got.ID != daily || got.Type != model.GameTypeDaily {
+					t.Errorf("GetDailyGame = %s (%s), want %s", got.ID, got.Type, daily)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, tt.check)
+	}
+}
+
+func TestGetCatalogDailyGames(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+
+	pgtest.Truncate(t, db)
+	classic := pgtest.InsertGame(t, db, model.GameTypeClassic, 2)
+	ended := pgtest.InsertGame(t, db, model.GameTypeDaily, 2)
+	current := pgtest.InsertGame(t, db, model.GameTypeDaily, 2)
+	queued := pgtest.InsertGame(t, db, model.GameTypeDaily, 2)
+	for _, id := range []uuid.UUID{ended, current, queued} {
+		pgtest.InsertDailyGame(t, db, id)
+	}
+	if _, err := db.Exec(`update easy_quizy_game_daily set ended_at = now() where game_id = $1`, ended); err != nil {
+		t.Fatalf("end daily game: %v", err)
+	}
+	daily := model.GameType(model.GameTypeDaily)
+
+	tests := []struct {
+		name   string
+		filter model.CatalogFilter
+		want   []uuid.UUID
+	}{
+		{name: "no dailies by default", want: []uuid.UUID{classic}},
+		{name: "ended and current dailies, not the queue", filter: model.CatalogFilter{Type: &daily}, want: []uuid.UUID{current, ended}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.filter.Page = tt.filter.Page.Normalize()
+			catalog, err := repo.GetCatalog(ctx, uuid.New(), tt.filter)
+			if err != nil {
+				t.Fatalf("GetCatalog: %v", err)
+			}
+
+			// новые игры сверху
+			got := make([]uuid.UUID, 0, len(catalog.Items))
+			for _, item := range catalog.Items {
+				got = append(got, item.ID)
+			}
+			if !slices.Equal(got, tt.want) || catalog.Total != int64(len(tt.want)) {
+				t.Errorf("GetCatalog = %v (total %d), want %v", got, catalog.Total, tt.want)
+			}
+		})
+	}
+}
+
+func TestReassignSessions(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	pgtest.Truncate(t, db)
+	gameID := pgtest.InsertGame(t, db, model.GameTypeClassic, 2)
+	from, to := uuid.New(), uuid.New()
+	createSession(t, repo, gameID, to, 1, now.Add(-2*time.Hour))
+	fromSession := createSession(t, repo, gameID, from, 1, now.Add(-time.Hour))
+	if _, _, err := repo.InsertGameSessionAnswer(ctx, fromSession, model.GameSessionAnswer{QuestionID: 0, AnswerID: 0, IsCorrect: true}); err != nil {
+		t.Fatalf("InsertGameSessionAnswer: %v", err)
+	}
+
+	moved, err := repo.ReassignSessions(ctx, from, to)
+	if err != nil {
+		t.Fatalf("ReassignSessions: %v", err)
+	}
+	if moved != 1 {
+		t.Errorf("ReassignSessions moved %d sessions, want 1", moved)
+	}
+
+	tests := []struct {
+		name        string
+		playerID    uuid.UUID
+		wantID      uuid.UUID
+		wantAttempt int64
+		wantErr     error
+	}{
+		{name: "moved attempt is numbered after the target's", playerID: to, wantID: fromSession.ID, wantAttempt: 2},
+		{name: "source has no sessions", playerID: from, wantErr: contracts.ErrSessionNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.GetLastGameSession(ctx, gameID, tt.playerID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetLastGameSession error = %v, want %v", err, tt.wantErr)
+			}
+			if got.ID != tt.wantID || got.Attempt != tt.wantAttempt {
+				t.Errorf("last session = %s #%d, want %s #%d", got.ID, got.Attempt, tt.wantID, tt.wantAttempt)
+			}
+		})
+	}
+}
+
+func TestMergeSessions(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name           string
+		fromUnfinished bool
+		wantLast       string
+		wantAttempt    int64
+	}{
+		{name: "best finished attempt becomes last", wantLast: "best", wantAttempt: 2},
+		{name: "unfinished attempt stays last", fromUnfinished: true, wantLast: "unfinished", wantAttempt: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pgtest.Truncate(t, db)
+			gameID := pgtest.InsertGame(t, db, model.GameTypeClassic, 2)
+			from, to := uuid.New(), uuid.New()
+			weak := createSession(t, repo, gameID, to, 1, now.Add(-3*time.Hour))
+			finishSession(t, repo, weak.ID, now.Add(-3*time.Hour), 1)
+			sessions := map[string]model.GameSession{"best": createSession(t, repo, gameID, from, 1, now.Add(-2*time.Hour))}
+			finishSession(t, repo, sessions["best"].ID, now.Add(-2*time.Hour), 2)
+			if tt.fromUnfinished {
+				sessions["unfinished"] = createSession(t, repo, gameID, from, 2, now.Add(-time.Hour))
+			}
+
+			moved, err := repo.MergeSessions(ctx, from, to)
+			if err != nil {
+				t.Fatalf("MergeSessions: %v", err)
+			}
+			if want := int64(len(sessions)); moved != want {
+				t.Errorf("MergeSessions moved %d sessions, want %d", moved, want)
+			}
+
+			got, err := repo.GetLastGameSession(ctx, gameID, to)
+			if err != nil {
+				t.Fatalf("GetLastGameSession: %v", err)
+			}
+			if want := sessions[tt.wantLast]; got.ID != want.ID || got.Attempt != tt.wantAttempt {
+				t.Errorf("last session = %s #%d, want the %s one %s #%d", got.ID, got.Attempt, tt.wantLast, want.ID, tt.wantAttempt)
+			}
+
+			own, err := repo.CountOwnAttempts(ctx, gameID, to)
+			if err != nil {
+				t.Fatalf("CountOwnAttempts: %v", err)
+			}
+			if own != 1 {
+				t.Errorf("CountOwnAttempts = %d, want only the target's attempt", own)
+			}
+		})
+	}
+}
+
+func TestRefreshFunnel(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	longAgo := today.Add(-72 * time.Hour)
+
+	pgtest.Truncate(t, db)
+	gameID := pgtest.InsertGame(t, db, model.GameTypeClassic, 2)
+	answer := func(session model.GameSession, questionID int64, at time.Time) {
+		t.Helper()
+
+		if _, _, err := repo.InsertGameSessionAnswer(ctx, session, model.GameSessionAnswer{QuestionID: questionID, IsCorrect: true}); err != nil {
+			t.Fatalf("InsertGameSessionAnswer: %v", err)
+		}
+		if _, err := db.Exec(
+			`update easy_quizy_game_session set created_at = $3 where session_id = $1 and question_id = $2`,
+			session.ID, questionID, at,
+		); err != nil {
+			t.Fatalf("failed to backdate answer: %v", err)
+		}
+	}
+
+	// Старый игрок начал и ответил до окна, а сегодня только продолжил попытку и завершил вторую
+	veteran := createSession(t, repo, gameID, uuid.New(), 1, longAgo)
+	answer(veteran, 0, longAgo)
+	finishSession(t, repo, veteran.ID, longAgo, 1)
+	answer(veteran, 1, today.Add(time.Hour))
+	retry := createSession(t, repo, gameID, veteran.PlayerID, 2, today.Add(time.Hour))
+	finishSession(t, repo, retry.ID, today.Add(2*time.Hour), 2)
+
+	newcomer := createSession(t, repo, gameID, uuid.New(), 1, today.Add(time.Hour))
+	answer(newcomer, 0, today.Add(time.Hour))
+	finishSession(t, repo, newcomer.ID, today.Add(2*time.Hour), 1)
+
+	if err := repo.RefreshFunnel(ctx, today, time.Now()); err != nil {
+		t.Fatalf("RefreshFunnel: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		from time.Time
+		want []model.FunnelDay
+	}{
+		{
+			name: "only first events of the window are counted",
+			from: today,
+			want: []model.FunnelDay{{Day: today, Opened: 1, Answered: 1, Completed: 1, Resets: 1}},
+		},
+		{
+			name: "days before the window are not rebuilt",
+			from: longAgo,
+			want: []model.FunnelDay{{Day: today, Opened: 1, Answered: 1, Completed: 1, Resets: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.GetFunnel(ctx, model.FunnelFilter{GameID: &gameID, From: tt.from, To: today})
+			if err != nil {
+				t.Fatalf("GetFunnel: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("GetFunnel = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Day.Equal(tt.want[i].Day) ||
+					got[i].Opened != tt.want[i].Opened ||
+					got[i].Answered != tt.want[i].Answered ||
+					got[i].Completed != tt.want[i].Completed ||
+					got[i].Resets != tt.want[i].Resets {
+					t.Errorf("day #%d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRefreshQuestionStats(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	const abandonAfter = 30 * time.Minute
+	firstRun := time.Now().UTC().Truncate(time.Microsecond)
+	secondRun := firstRun.Add(time.Minute)
+
+	pgtest.Truncate(t, db)
+	quiet := pgtest.InsertGame(t, db, model.GameTypeClassic, 2)
+	active := pgtest.InsertGame(t, db, model.GameTypeClassic, 2)
+
+	old := createSession(t, repo, quiet, uuid.New(), 1, firstRun.Add(-2*time.Hour))
+	if _, _, err := repo.InsertGameSessionAnswer(ctx, old, model.GameSessionAnswer{QuestionID: 0, IsCorrect: true}); err != nil {
+		t.Fatalf("InsertGameSessionAnswer: %v", err)
+	}
+	if _, err := db.Exec(`update easy_quizy_game_session set created_at = $2 where session_id = $1`, old.ID, firstRun.Add(-2*time.Hour)); err != nil {
+		t.Fatalf("failed to backdate answer: %v", err)
+	}
+	if err := repo.RefreshQuestionStats(ctx, firstRun.Add(-abandonAfter), firstRun); err != nil {
+		t.Fatalf("RefreshQuestionStats: %v", err)
+	}
+
+	// Между расчетами играли только в active
+	fresh := createSession(t, repo, active, uuid.New(), 1, time.Now())
+	if _, _, err := repo.InsertGameSessionAnswer(ctx, fresh, model.GameSessionAnswer{QuestionID: 0, AnswerID: 1}); err != nil {
+		t.Fatalf("InsertGameSessionAnswer: %v", err)
+	}
+	if err := repo.RefreshQuestionStats(ctx, secondRun.Add(-abandonAfter), secondRun); err != nil {
+		t.Fatalf("RefreshQuestionStats: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		gameID         uuid.UUID
+		wantRows       int
+		wantCorrect    int64
+		wantComputedAt time.Time
+	}{
+		// Старая попытка брошена на втором вопросе, поэтому у quiet две строки
+		{name: "game without new activity keeps its stats", gameID: quiet, wantRows: 2, wantCorrect: 1, wantComputedAt: firstRun},
+		{name: "game with new activity is recomputed", gameID: active, wantRows: 1, wantCorrect: 0, wantComputedAt: secondRun},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.GetQuestionStats(ctx, tt.gameID)
+			if err != nil {
+				t.Fatalf("GetQuestionStats: %v", err)
+			}
+			if len(got) != tt.wantRows {
+				t.Fatalf("GetQuestionStats = %+v, want %d rows", got, tt.wantRows)
+			}
+			if got[0].Answers != 1 || got[0].Correct != tt.wantCorrect || !got[0].ComputedAt.Equal(tt.wantComputedAt) {
+				t.Errorf("question 0 = %+v, want 1 answer, %d correct, computed at %s",
+					got[0], tt.wantCorrect, tt.wantComputedAt)
+			}
+		})
+	}
+}
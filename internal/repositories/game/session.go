@@ -0,0 +1,290 @@
+package game
+
+import (
+	"context"
+	"database/sql"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const (
+	uniqueViolation     = "23505"
+	idempotencyKeyIndex = "easy_quizy_game_session_idempotency_key_uidx"
+)
+
+type (
+	sqlxSession struct {
+		ID         uuid.UUID  `db:"id"`
+		GameID     uuid.UUID  `db:"game_id"`
+		PlayerID   uuid.UUID  `db:"player_id"`
+		Attempt    int64      `db:"attempt"`
+		StartedAt  time.Time  `db:"started_at"`
+		FinishedAt *time.Time `db:"finished_at"`
+		Score      *int64     `db:"score"`
+		ResultText *string    `db:"result_text"`
+	}
+
+	// sqlxGameSession строка ответа из easy_quizy_game_session
+	sqlxGameSession struct {
+		SessionID  uuid.UUID `db:"session_id"`
+		GameID     uuid.UUID `db:"game_id"`
+		PlayerID   uuid.UUID `db:"player_id"`
+		QuestionID int64     `db:"question_id"`
+		AnswerID   int64     `db:"answer_id"`
+		IsCorrect  bool      `db:"is_correct"`
+		CreatedAt  time.Time `db:"created_at"`
+
+		IdempotencyKey *string `db:"idempotency_key"`
+	}
+)
+
+// GetLastGameSession возвращает последнюю попытку игрока вместе с ответами
+func (r *DefaultRepository) GetLastGameSession(ctx context.Context, gameID uuid.UUID, playerID uuid.UUID) (model.GameSession, error) {
+	const query = `
+		select
+			id,
+			game_id,
+			player_id,
+			attempt,
+			started_at,
+			finished_at,
+			score,
+			result_text
+		from easy_quizy_session
+		where game_id = $1 and player_id = $2
+		order by attempt desc
+		limit 1
+	`
+
+	var result sqlxSession
+	if err := r.db(ctx).GetContext(ctx, &result, query, gameID, playerID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.GameSession{}, contracts.ErrSessionNotFound
+		}
+
+		return model.GameSession{}, err
+	}
+
+	answers, err := r.getGameSessionAnswers(ctx, result.ID)
+	if err != nil {
+		return model.GameSession{}, err
+	}
+
+	lifelines, err := r.getLifelineUsages(ctx, result.ID)
+	if err != nil {
+		return model.GameSession{}, err
+	}
+
+	return convertToSession(result, answers, lifelines), nil
+}
+
+// CreateGameSession начинает попытку; если параллельный запрос уже создал попытку с тем же номером,
+// возвращается она
+func (r *DefaultRepository) CreateGameSession(ctx context.Context, session model.GameSession) (model.GameSession, error) {
+	const query = `
+		insert into easy_quizy_session
+		(id, game_id, player_id, attempt, started_at)
+		values ($1, $2, $3, $4, $5)
+		on conflict (game_id, player_id, attempt) do nothing
+	`
+
+	result, err := r.db(ctx).ExecContext(
+		ctx,
+		query,
+		session.ID,
+		session.GameID,
+		session.PlayerID,
+		session.Attempt,
+		session.StartedAt,
+	)
+	if err != nil {
+		return model.GameSession{}, err
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return model.GameSession{}, err
+	}
+	if inserted > 0 {
+		return session, nil
+	}
+
+	return r.GetLastGameSession(ctx, session.GameID, session.PlayerID)
+}
+
+// FinishGameSession фиксирует результат попытки и сообщает, завершил ли ее именно этот вызов
+func (r *DefaultRepository) FinishGameSession(ctx context.Context, sessionID uuid.UUID, finishedAt time.Time, result model.Result) (bool, error) {
+	const query = `
+		update easy_quizy_session
+		set finished_at = $2, score = $3, result_text = $4
+		where id = $1 and finished_at is null
+	`
+
+	res, err := r.db(ctx).ExecContext(
+		ctx,
+		query,
+		sessionID,
+		finishedAt,
+		result.TotalScore,
+		result.ResultText,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// InsertGameSessionAnswer записывает ответ, если на вопрос в этой попытке еще не отвечали,
+// и возвращает записанный ответ: при повторной отправке это первый ответ игрока, а не переданный data.
+// Флаг inserted истинен, только если ответ записал этот вызов.
+func (r *DefaultRepository) InsertGameSessionAnswer(ctx context.Context, session model.GameSession, data model.GameSessionAnswer) (model.GameSessionAnswer, bool, error) {
+	const query = `
+		insert into easy_quizy_game_session
+		(session_id, game_id, player_id, question_id, answer_id, is_correct, idempotency_key, created_at)
+		values ($1, $2, $3, $4, $5, $6, $7, $8)
+		on conflict (session_id, question_id) do nothing
+		returning session_id, game_id, player_id, question_id, answer_id, is_correct, created_at, idempotency_key
+	`
+
+	// Время ответа по часам сервиса, как и начало попытки: античит сравнивает их между собой
+	if data.CreatedAt.IsZero() {
+		data.CreatedAt = time.Now()
+	}
+
+	var result []sqlxGameSession
+	err := r.db(ctx).SelectContext(
+		ctx,
+		&result,
+		query,
+		session.ID,
+		session.GameID,
+		session.PlayerID,
+		data.QuestionID,
+		data.AnswerID,
+		data.IsCorrect,
+		data.IdempotencyKey,
+		data.CreatedAt,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation && pqErr.Constraint == idempotencyKeyIndex {
+			return model.GameSessionAnswer{}, false, contracts.ErrIdempotencyKeyReused
+		}
+
+		return model.GameSessionAnswer{}, false, err
+	}
+	if len(result) > 0 {
+		return convertToSessionAnswer(result[0]), true, nil
+	}
+
+	// Ответ уже записан параллельным запросом: отдельный запрос видит его после коммита
+	answer, err := r.getGameSessionAnswer(ctx, session.ID, data.QuestionID)
+	return answer, false, err
+}
+
+func (r *DefaultRepository) GetGameSessionAnswerByIdempotencyKey(ctx context.Context, playerID uuid.UUID, key string) (uuid.UUID, model.GameSessionAnswer, error) {
+	const query = `
+		select
+			session_id,
+			game_id,
+			player_id,
+			question_id,
+			answer_id,
+			is_correct,
+			created_at,
+			idempotency_key
+		from easy_quizy_game_session
+		where player_id = $1 and idempotency_key = $2
+	`
+
+	var result sqlxGameSession
+	if err := r.db(ctx).GetContext(ctx, &result, query, playerID, key); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, model.GameSessionAnswer{}, contracts.ErrAnswerNotFound
+		}
+
+		return uuid.Nil, model.GameSessionAnswer{}, err
+	}
+
+	return result.GameID, convertToSessionAnswer(result), nil
+}
+
+func (r *DefaultRepository) getGameSessionAnswer(ctx context.Context, sessionID uuid.UUID, questionID int64) (model.GameSessionAnswer, error) {
+	const query = `
+		select
+			session_id,
+			game_id,
+			player_id,
+			question_id,
+			answer_id,
+			is_correct,
+			created_at,
+			idempotency_key
+		from easy_quizy_game_session
+		where session_id = $1 and question_id = $2
+	`
+
+	var result sqlxGameSession
+	if err := r.db(ctx).GetContext(ctx, &result, query, sessionID, questionID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.GameSessionAnswer{}, contracts.ErrAnswerNotFound
+		}
+
+		return model.GameSessionAnswer{}, err
+	}
+
+	return convertToSessionAnswer(result), nil
+}
+
+func (r *DefaultRepository) getGameSessionAnswers(ctx context.Context, sessionID uuid.UUID) ([]sqlxGameSession, error) {
+	const query = `
+		select
+			session_id,
+			game_id,
+			player_id,
+			question_id,
+			answer_id,
+			is_correct,
+			created_at,
+			idempotency_key
+		from easy_quizy_game_session
+		where session_id = $1
+		order by id
+	`
+
+	var result []sqlxGameSession
+	if err := r.db(ctx).SelectContext(ctx, &result, query, sessionID); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// HasFinishedSession есть ли у игрока хотя бы одна завершенная попытка игры
+func (r *DefaultRepository) HasFinishedSession(ctx context.Context, gameID uuid.UUID, playerID uuid.UUID) (bool, error) {
+	const query = `
+		select exists (
+			select 1
+			from easy_quizy_session
+			where game_id = $1 and player_id = $2 and finished_at is not null
+		)
+	`
+
+	var result bool
+	if err := r.db(ctx).GetContext(ctx, &result, query, gameID, playerID); err != nil {
+		return false, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,194 @@
+package outbox
+
+import (
+	"context"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"sort"
+	"sync"
+	"time"
+)
+
+type (
+	// MemoryRepository outbox в памяти процесса для демо-режима. Транзакций нет, поэтому
+	// NextEvent не блокирует событие: диспетчер должен быть единственным.
+	MemoryRepository struct {
+		mu          sync.Mutex
+		nextID      int64
+		events      []*memoryEvent
+		subscribers map[subscriberKey]*model.SubscriberDelivery
+	}
+
+	subscriberKey struct {
+		eventID    int64
+		subscriber string
+	}
+
+	memoryEvent struct {
+		model.Event
+		availableAt time.Time
+		lastError   *string
+		processedAt *time.Time
+		failedAt    *time.Time
+	}
+)
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{subscribers: make(map[subscriberKey]*model.SubscriberDelivery)}
+}
+
+func (r *MemoryRepository) InsertEvents(_ context.Context, events []model.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, event := range events {
+		r.nextID++
+		event.ID = r.nextID
+		event.CreatedAt = now
+		r.events = append(r.events, &memoryEvent{Event: event, availableAt: now})
+	}
+
+	return nil
+}
+
+func (r *MemoryRepository) NextEvent(_ context.Context, now time.Time) (model.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var next *memoryEvent
+	for _, event := range r.events {
+		if event.processedAt != nil || event.failedAt != nil || event.availableAt.After(now) {
+			continue
+		}
+		if next == nil || event.availableAt.Before(next.availableAt) {
+			next = event
+		}
+	}
+	if next == nil {
+		return model.Event{}, contracts.ErrEventNotFound
+	}
+
+	return next.Event, nil
+}
+
+func (r *MemoryRepository) MarkProcessed(_ context.Context, id int64, processedAt time.Time) error {
+	return r.update(id, func(event *memoryEvent) {
+		event.processedAt = &processedAt
+		event.lastError = nil
+	})
+}
+
+func (r *MemoryRepository) ScheduleRetry(_ context.Context, id int64, availableAt time.Time, lastError string) error {
+	return r.update(id, func(event *memoryEvent) {
+		event.Attempts++
+		event.availableAt = availableAt
+		event.lastError = &lastError
+	})
+}
+
+func (r *MemoryRepository) MarkFailed(_ context.Context, id int64, failedAt time.Time, lastError string) error {
+	return r.update(id, func(event *memoryEvent) {
+		event.Attempts++
+		event.failedAt = &failedAt
+		event.lastError = &lastError
+	})
+}
+
+func (r *MemoryRepository) DeleteProcessed(_ context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.events[:0]
+	for _, event := range r.events {
+		if event.processedAt != nil && event.processedAt.Before(before) {
+			for key := range r.subscribers {
+				if key.eventID == event.ID {
+					delete(r.subscribers, key)
+				}
+			}
+			continue
+		}
+
+		kept = append(kept, event)
+	}
+
+	deleted := int64(len(r.events) - len(kept))
+	r.events = kept
+	return deleted, nil
+}
+
+func (r *MemoryRepository) GetSubscriberDeliveries(_ context.Context, eventID int64) ([]model.SubscriberDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var result []model.SubscriberDelivery
+	for key, delivery := range r.subscribers {
+		if key.eventID == eventID {
+			result = append(result, *delivery)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Subscriber < result[j].Subscriber
+	})
+
+	return result, nil
+}
+
+func (r *MemoryRepository) MarkSubscriberProcessed(_ context.Context, eventID int64, subscriber string, processedAt time.Time) error {
+	r.updateSubscriber(eventID, subscriber, func(delivery *model.SubscriberDelivery) {
+		delivery.ProcessedAt = &processedAt
+		delivery.LastError = nil
+	})
+
+	return nil
+}
+
+func (r *MemoryRepository) ScheduleSubscriberRetry(_ context.Context, eventID int64, subscriber string, availableAt time.Time, lastError string) error {
+	r.updateSubscriber(eventID, subscriber, func(delivery *model.SubscriberDelivery) {
+		delivery.Attempts++
+		delivery.AvailableAt = availableAt
+		delivery.LastError = &lastError
+	})
+
+	return nil
+}
+
+func (r *MemoryRepository) MarkSubscriberFailed(_ context.Context, eventID int64, subscriber string, failedAt time.Time, lastError string) error {
+	r.updateSubscriber(eventID, subscriber, func(delivery *model.SubscriberDelivery) {
+		delivery.Attempts++
+		delivery.FailedAt = &failedAt
+		delivery.LastError = &lastError
+	})
+
+	return nil
+}
+
+// updateSubscriber создает состояние доставки подписчику при первом обращении, как upsert в DefaultRepository
+func (r *MemoryRepository) updateSubscriber(eventID int64, subscriber string, apply func(delivery *model.SubscriberDelivery)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := subscriberKey{eventID: eventID, subscriber: subscriber}
+	delivery, ok := r.subscribers[key]
+	if !ok {
+		delivery = &model.SubscriberDelivery{EventID: eventID, Subscriber: subscriber, AvailableAt: time.Now()}
+		r.subscribers[key] = delivery
+	}
+
+	apply(delivery)
+}
+
+func (r *MemoryRepository) update(id int64, apply func(event *memoryEvent)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, event := range r.events {
+		if event.ID == id {
+			apply(event)
+			return nil
+		}
+	}
+
+	return contracts.ErrEventNotFound
+}
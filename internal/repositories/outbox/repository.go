@@ -0,0 +1,214 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"errors"
+	"time"
+
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/jmoiron/sqlx"
+)
+
+type (
+	DefaultRepository struct {
+		sqlx *sqlx.DB
+		tx   *trmsqlx.CtxGetter
+	}
+
+	sqlxEvent struct {
+		ID        int64     `db:"id"`
+		EventType string    `db:"event_type"`
+		Payload   []byte    `db:"payload"`
+		CreatedAt time.Time `db:"created_at"`
+		Attempts  int64     `db:"attempts"`
+	}
+
+	sqlxSubscriberDelivery struct {
+		EventID     int64      `db:"event_id"`
+		Subscriber  string     `db:"subscriber"`
+		Attempts    int64      `db:"attempts"`
+		AvailableAt time.Time  `db:"available_at"`
+		LastError   *string    `db:"last_error"`
+		ProcessedAt *time.Time `db:"processed_at"`
+		FailedAt    *time.Time `db:"failed_at"`
+	}
+)
+
+func NewRepository(sqlx *sqlx.DB, tx *trmsqlx.CtxGetter) *DefaultRepository {
+	return &DefaultRepository{sqlx: sqlx, tx: tx}
+}
+
+func (r *DefaultRepository) db(ctx context.Context) trmsqlx.Tr {
+	return r.tx.DefaultTrOrDB(ctx, r.sqlx)
+}
+
+// InsertEvents пишет события в outbox, вызывается в транзакции изменения, породившего события
+func (r *DefaultRepository) InsertEvents(ctx context.Context, events []model.Event) error {
+	const query = `
+		insert into easy_quizy_outbox (event_type, payload)
+		values ($1, $2)
+	`
+
+	for _, event := range events {
+		if _, err := r.db(ctx).ExecContext(ctx, query, event.Type, []byte(event.Payload)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// NextEvent блокирует до конца транзакции самое старое готовое к доставке событие,
+// события, заблокированные другими диспетчерами, пропускаются
+func (r *DefaultRepository) NextEvent(ctx context.Context, now time.Time) (model.Event, error) {
+	const query = `
+		select id, event_type, payload, created_at, attempts
+		from easy_quizy_outbox
+		where processed_at is null and failed_at is null and available_at <= $1
+		order by available_at, id
+		limit 1
+		for update skip locked
+	`
+
+	var result sqlxEvent
+	if err := r.db(ctx).GetContext(ctx, &result, query, now); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Event{}, contracts.ErrEventNotFound
+		}
+
+		return model.Event{}, err
+	}
+
+	return convertToEvent(result), nil
+}
+
+func (r *DefaultRepository) MarkProcessed(ctx context.Context, id int64, processedAt time.Time) error {
+	const query = `
+		update easy_quizy_outbox
+		set processed_at = $2, last_error = null
+		where id = $1
+	`
+
+	_, err := r.db(ctx).ExecContext(ctx, query, id, processedAt)
+	return err
+}
+
+// ScheduleRetry откладывает событие до ближайшего повтора у подписчиков после неудачной доставки
+func (r *DefaultRepository) ScheduleRetry(ctx context.Context, id int64, availableAt time.Time, lastError string) error {
+	const query = `
+		update easy_quizy_outbox
+		set attempts = attempts + 1, available_at = $2, last_error = $3
+		where id = $1
+	`
+
+	_, err := r.db(ctx).ExecContext(ctx, query, id, availableAt, lastError)
+	return err
+}
+
+// MarkFailed снимает событие с доставки, когда ждать больше некого, но хотя бы один подписчик исчерпал попытки
+func (r *DefaultRepository) MarkFailed(ctx context.Context, id int64, failedAt time.Time, lastError string) error {
+	const query = `
+		update easy_quizy_outbox
+		set attempts = attempts + 1, failed_at = $2, last_error = $3
+		where id = $1
+	`
+
+	_, err := r.db(ctx).ExecContext(ctx, query, id, failedAt, lastError)
+	return err
+}
+
+// DeleteProcessed удаляет доставленные события старше before, недоставленные остаются для разбора
+func (r *DefaultRepository) DeleteProcessed(ctx context.Context, before time.Time) (int64, error) {
+	const query = `
+		delete from easy_quizy_outbox
+		where processed_at is not null and processed_at < $1
+	`
+
+	res, err := r.db(ctx).ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// GetSubscriberDeliveries состояние доставки события подписчикам; подписчиков, которым событие еще
+// не доставлялось, в ответе нет
+func (r *DefaultRepository) GetSubscriberDeliveries(ctx context.Context, eventID int64) ([]model.SubscriberDelivery, error) {
+	const query = `
+		select event_id, subscriber, attempts, available_at, last_error, processed_at, failed_at
+		from easy_quizy_outbox_subscriber
+		where event_id = $1
+		order by subscriber
+	`
+
+	var rows []sqlxSubscriberDelivery
+	if err := r.db(ctx).SelectContext(ctx, &rows, query, eventID); err != nil {
+		return nil, err
+	}
+
+	result := make([]model.SubscriberDelivery, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, model.SubscriberDelivery(row))
+	}
+
+	return result, nil
+}
+
+// MarkSubscriberProcessed отмечает событие доставленным подписчику, вызывается в транзакции обработчика
+func (r *DefaultRepository) MarkSubscriberProcessed(ctx context.Context, eventID int64, subscriber string, processedAt time.Time) error {
+	const query = `
+		insert into easy_quizy_outbox_subscriber (event_id, subscriber, processed_at)
+		values ($1, $2, $3)
+		on conflict (event_id, subscriber) do update
+		set processed_at = excluded.processed_at, last_error = null
+	`
+
+	_, err := r.db(ctx).ExecContext(ctx, query, eventID, subscriber, processedAt)
+	return err
+}
+
+// ScheduleSubscriberRetry откладывает доставку подписчику до availableAt после ошибки его обработчика
+func (r *DefaultRepository) ScheduleSubscriberRetry(ctx context.Context, eventID int64, subscriber string, availableAt time.Time, lastError string) error {
+	const query = `
+		insert into easy_quizy_outbox_subscriber (event_id, subscriber, attempts, available_at, last_error)
+		values ($1, $2, 1, $3, $4)
+		on conflict (event_id, subscriber) do update
+		set
+			attempts = easy_quizy_outbox_subscriber.attempts + 1,
+			available_at = excluded.available_at,
+			last_error = excluded.last_error
+	`
+
+	_, err := r.db(ctx).ExecContext(ctx, query, eventID, subscriber, availableAt, lastError)
+	return err
+}
+
+// MarkSubscriberFailed снимает событие с доставки подписчику после исчерпания его попыток
+func (r *DefaultRepository) MarkSubscriberFailed(ctx context.Context, eventID int64, subscriber string, failedAt time.Time, lastError string) error {
+	const query = `
+		insert into easy_quizy_outbox_subscriber (event_id, subscriber, attempts, failed_at, last_error)
+		values ($1, $2, 1, $3, $4)
+		on conflict (event_id, subscriber) do update
+		set
+			attempts = easy_quizy_outbox_subscriber.attempts + 1,
+			failed_at = excluded.failed_at,
+			last_error = excluded.last_error
+	`
+
+	_, err := r.db(ctx).ExecContext(ctx, query, eventID, subscriber, failedAt, lastError)
+	return err
+}
+
+func convertToEvent(in sqlxEvent) model.Event {
+	return model.Event{
+		ID:        in.ID,
+		Type:      model.EventType(in.EventType),
+		Payload:   in.Payload,
+		CreatedAt: in.CreatedAt,
+		Attempts:  in.Attempts,
+	}
+}
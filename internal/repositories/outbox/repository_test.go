@@ -0,0 +1,251 @@
+package outbox
+
+import (
+	"context"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"easy-quizy/internal/pgtest"
+	"errors"
+	"testing"
+	"time"
+
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/jmoiron/sqlx"
+)
+
+func newTestRepository(t *testing.T) (*DefaultRepository, *sqlx.DB) {
+	t.Helper()
+
+	db := pgtest.Start(t)
+	return NewRepository(db, trmsqlx.DefaultCtxGetter), db
+}
+
+func insertEvents(t *testing.T, repo *DefaultRepository, types ...model.EventType) {
+	t.Helper()
+
+	events := make([]model.Event, 0, len(types))
+	for _, eventType := range types {
+		event, err := model.NewEvent(eventType, map[string]string{"type": string(eventType)})
+		if err != nil {
+			t.Fatalf("NewEvent: %v", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := repo.InsertEvents(context.Background(), events); err != nil {
+		t.Fatalf("InsertEvents: %v", err)
+	}
+}
+
+func TestNextEvent(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	// available_at ставит база, запас в минуту покрывает расхождение часов
+	now := time.Now().Add(time.Minute)
+
+	tests := []struct {
+		name     string
+		apply    func(t *testing.T, first model.Event)
+		wantType model.EventType
+		wantErr  error
+	}{
+		{
+			name:     "oldest event first",
+			apply:    func(t *testing.T, first model.Event) {},
+			wantType: model.EventAnswerAccepted,
+		},
+		{
+			name: "processed event is skipped",
+			apply: func(t *testing.T, first model.Event) {
+				if err := repo.MarkProcessed(ctx, first.ID, now); err != nil {
+					t.Fatalf("MarkProcessed: %v", err)
+				}
+			},
+			wantType: model.EventGameCompleted,
+		},
+		{
+			name: "retried event waits until available",
+			apply: func(t *testing.T, first model.Event) {
+				if err := repo.ScheduleRetry(ctx, first.ID, now.Add(time.Hour), "timeout"); err != nil {
+					t.Fatalf("ScheduleRetry: %v", err)
+				}
+			},
+			wantType: model.EventGameCompleted,
+		},
+		{
+			name: "failed event is skipped",
+			apply: func(t *testing.T, first model.Event) {
+				if err := repo.MarkFailed(ctx, first.ID, now, "boom"); err != nil {
+					t.Fatalf("MarkFailed: %v", err)
+				}
+			},
+			wantType: model.EventGameCompleted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pgtest.Truncate(t, db)
+			insertEvents(t, repo, model.EventAnswerAccepted, model.EventGameCompleted)
+
+			first, err := repo.NextEvent(ctx, now)
+			if err != nil {
+				t.Fatalf("NextEvent: %v", err)
+			}
+			tt.apply(t, first)
+
+			got, err := repo.NextEvent(ctx, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NextEvent error = %v, want %v", err, tt.wantErr)
+			}
+			if got.Type != tt.wantType {
+				t.Errorf("NextEvent = %s, want %s", got.Type, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestNextEventEmpty(t *testing.T) {
+	repo, db := newTestRepository(t)
+	pgtest.Truncate(t, db)
+
+	if _, err := repo.NextEvent(context.Background(), time.Now()); !errors.Is(err, contracts.ErrEventNotFound) {
+		t.Fatalf("NextEvent error = %v, want %v", err, contracts.ErrEventNotFound)
+	}
+}
+
+func TestScheduleRetryCountsAttempts(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	now := time.Now().Add(time.Minute)
+
+	tests := []struct {
+		name    string
+		retries int
+		want    int64
+	}{
+		{name: "no retries", retries: 0, want: 0},
+		{name: "two retries", retries: 2, want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pgtest.Truncate(t, db)
+			insertEvents(t, repo, model.EventAnswerAccepted)
+
+			event, err := repo.NextEvent(ctx, now)
+			if err != nil {
+				t.Fatalf("NextEvent: %v", err)
+			}
+			for range tt.retries {
+				if err := repo.ScheduleRetry(ctx, event.ID, now, "timeout"); err != nil {
+					t.Fatalf("ScheduleRetry: %v", err)
+				}
+			}
+
+			got, err := repo.NextEvent(ctx, now)
+			if err != nil {
+				t.Fatalf("NextEvent: %v", err)
+			}
+			if got.Attempts != tt.want {
+				t.Errorf("Attempts = %d, want %d", got.Attempts, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeleteProcessed(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	now := time.Now().Add(time.Minute)
+
+	tests := []struct {
+		name        string
+		processedAt time.Time
+		before      time.Time
+		want        int64
+	}{
+		{name: "old processed event", processedAt: now.Add(-2 * time.Hour), before: now.Add(-time.Hour), want: 1},
+		{name: "recent processed event", processedAt: now, before: now.Add(-time.Hour), want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pgtest.Truncate(t, db)
+			insertEvents(t, repo, model.EventAnswerAccepted, model.EventGameCompleted)
+
+			event, err := repo.NextEvent(ctx, now)
+			if err != nil {
+				t.Fatalf("NextEvent: %v", err)
+			}
+			if err := repo.MarkProcessed(ctx, event.ID, tt.processedAt); err != nil {
+				t.Fatalf("MarkProcessed: %v", err)
+			}
+
+			deleted, err := repo.DeleteProcessed(ctx, tt.before)
+			if err != nil {
+				t.Fatalf("DeleteProcessed: %v", err)
+			}
+			if deleted != tt.want {
+				t.Errorf("DeleteProcessed = %d, want %d", deleted, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubscriberDeliveries(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	now := time.Now().Add(time.Minute)
+
+	pgtest.Truncate(t, db)
+	insertEvents(t, repo, model.EventGameCompleted)
+	event, err := repo.NextEvent(ctx, now)
+	if err != nil {
+		t.Fatalf("NextEvent: %v", err)
+	}
+
+	if err := repo.MarkSubscriberProcessed(ctx, event.ID, "anticheat", now); err != nil {
+		t.Fatalf("MarkSubscriberProcessed: %v", err)
+	}
+	for range 2 {
+		if err := repo.ScheduleSubscriberRetry(ctx, event.ID, "webhooks", now.Add(time.Hour), "timeout"); err != nil {
+			t.Fatalf("ScheduleSubscriberRetry: %v", err)
+		}
+	}
+	if err := repo.MarkSubscriberFailed(ctx, event.ID, "achievements", now, "boom"); err != nil {
+		t.Fatalf("MarkSubscriberFailed: %v", err)
+	}
+
+	got, err := repo.GetSubscriberDeliveries(ctx, event.ID)
+	if err != nil {
+		t.Fatalf("GetSubscriberDeliveries: %v", err)
+	}
+
+	tests := []struct {
+		subscriber    string
+		wantAttempts  int64
+		wantProcessed bool
+		wantFailed    bool
+	}{
+		{subscriber: "achievements", wantAttempts: 1, wantFailed: true},
+		{subscriber: "anticheat", wantAttempts: 0, wantProcessed: true},
+		{subscriber: "webhooks", wantAttempts: 2},
+	}
+
+	if len(got) != len(tests) {
+		t.Fatalf("GetSubscriberDeliveries = %+v, want %d subscribers", got, len(tests))
+	}
+	for i, tt := range tests {
+		t.Run(tt.subscriber, func(t *testing.T) {
+			delivery := got[i]
+			if delivery.Subscriber != tt.subscriber ||
+				delivery.Attempts != tt.wantAttempts ||
+				(delivery.ProcessedAt != nil) != tt.wantProcessed ||
+				(delivery.FailedAt != nil) != tt.wantFailed {
+				t.Errorf("delivery = %+v, want %s after %d attempts, processed %v, failed %v",
+					delivery, tt.subscriber, tt.wantAttempts, tt.wantProcessed, tt.wantFailed)
+			}
+		})
+	}
+}
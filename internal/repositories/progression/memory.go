@@ -0,0 +1,101 @@
+package progression
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// MemoryRepository журнал опыта в памяти процесса для демо-режима
+	MemoryRepository struct {
+		mu      sync.RWMutex
+		nextID  int64
+		entries []model.XPEntry
+	}
+)
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{}
+}
+
+func (r *MemoryRepository) InsertEntries(_ context.Context, entries []model.XPEntry) ([]model.XPEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]model.XPEntry, 0, len(entries))
+	for _, entry := range entries {
+		if r.exists(entry) {
+			continue
+		}
+
+		r.nextID++
+		entry.ID = r.nextID
+		entry.CreatedAt = time.Now()
+		r.entries = append(r.entries, entry)
+		result = append(result, entry)
+	}
+
+	return result, nil
+}
+
+func (r *MemoryRepository) GetTotalXP(_ context.Context, userIDs []uuid.UUID, excludeSessions []uuid.UUID) (map[uuid.UUID]int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	wanted := make(map[uuid.UUID]struct{}, len(userIDs))
+	for _, userID := range userIDs {
+		wanted[userID] = struct{}{}
+	}
+
+	result := make(map[uuid.UUID]int64, len(userIDs))
+	for _, entry := range r.entries {
+		if _, ok := wanted[entry.UserID]; ok && !slices.Contains(excludeSessions, entry.SessionID) {
+			result[entry.UserID] += entry.Amount
+		}
+	}
+
+	return result, nil
+}
+
+// exists повторяет проверки DefaultRepository.InsertEntries: начисление за ответ и завершение уникально
+// в пределах игры игрока, бонус серии — в пределах попытки
+func (r *MemoryRepository) exists(entry model.XPEntry) bool {
+	for _, existing := range r.entries {
+		if existing.Reason != entry.Reason {
+			continue
+		}
+		sameScope := existing.SessionID == entry.SessionID
+		if entry.Reason != model.XPReasonDailyStreak {
+			sameScope = sameScope || (existing.UserID == entry.UserID && existing.GameID == entry.GameID)
+		}
+		if !sameScope {
+			continue
+		}
+		if existing.QuestionID == nil && entry.QuestionID == nil {
+			return true
+		}
+		if existing.QuestionID != nil && entry.QuestionID != nil && *existing.QuestionID == *entry.QuestionID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *MemoryRepository) ReassignEntries(_ context.Context, from uuid.UUID, to uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.entries {
+		if r.entries[i].UserID == from {
+			r.entries[i].UserID = to
+		}
+	}
+
+	return nil
+}
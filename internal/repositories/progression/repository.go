@@ -0,0 +1,112 @@
+package progression
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"time"
+
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type (
+	DefaultRepository struct {
+		sqlx *sqlx.DB
+		tx   *trmsqlx.CtxGetter
+	}
+
+	sqlxInsertedEntry struct {
+		ID        int64     `db:"id"`
+		CreatedAt time.Time `db:"created_at"`
+	}
+
+	sqlxUserXP struct {
+		UserID uuid.UUID `db:"user_id"`
+		XP     int64     `db:"xp"`
+	}
+)
+
+func NewRepository(sqlx *sqlx.DB, tx *trmsqlx.CtxGetter) *DefaultRepository {
+	return &DefaultRepository{sqlx: sqlx, tx: tx}
+}
+
+func (r *DefaultRepository) db(ctx context.Context) trmsqlx.Tr {
+	return r.tx.DefaultTrOrDB(ctx, r.sqlx)
+}
+
+// InsertEntries пишет начисления в журнал и возвращает записанные. Опыт за правильный ответ дается один раз
+// на вопрос игры, а за завершение — один раз на игру, какой бы ни была попытка: повторные прохождения
+// опыт не фармят. Бонус серии ежедневных игр ограничен только попыткой.
+func (r *DefaultRepository) InsertEntries(ctx context.Context, entries []model.XPEntry) ([]model.XPEntry, error) {
+	const query = `
+		insert into easy_quizy_xp_ledger (user_id, amount, reason, game_id, session_id, question_id)
+		select $1::uuid, $2::bigint, $3::text, $4::uuid, $5::uuid, $6::bigint
+		where $3::text = 'daily_streak' or not exists (
+			select 1
+			from easy_quizy_xp_ledger l
+			where l.user_id = $1 and l.game_id = $4 and l.reason = $3 and coalesce(l.question_id, -1) = coalesce($6, -1)
+		)
+		on conflict (session_id, reason, coalesce(question_id, -1)) do nothing
+		returning id, created_at
+	`
+
+	result := make([]model.XPEntry, 0, len(entries))
+	for _, entry := range entries {
+		var rows []sqlxInsertedEntry
+		err := r.db(ctx).SelectContext(ctx, &rows, query,
+			entry.UserID, entry.Amount, entry.Reason, entry.GameID, entry.SessionID, entry.QuestionID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == 0 {
+			continue
+		}
+
+		entry.ID = rows[0].ID
+		entry.CreatedAt = rows[0].CreatedAt
+		result = append(result, entry)
+	}
+
+	return result, nil
+}
+
+// GetTotalXP опыт игроков без начислений за excludeSessions; игроки без начислений в результат не попадают
+func (r *DefaultRepository) GetTotalXP(ctx context.Context, userIDs []uuid.UUID, excludeSessions []uuid.UUID) (map[uuid.UUID]int64, error) {
+	result := make(map[uuid.UUID]int64, len(userIDs))
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	const query = `
+		select user_id, sum(amount)::bigint as xp
+		from easy_quizy_xp_ledger
+		where user_id = any($1) and session_id <> all(coalesce($2::uuid[], '{}'))
+		group by user_id
+	`
+
+	var rows []sqlxUserXP
+	if err := r.db(ctx).SelectContext(ctx, &rows, query, pq.Array(userIDs), pq.Array(excludeSessions)); err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		result[row.UserID] = row.XP
+	}
+
+	return result, nil
+}
+
+// ReassignEntries переносит начисления пользователя from на пользователя to
+func (r *DefaultRepository) ReassignEntries(ctx context.Context, from uuid.UUID, to uuid.UUID) error {
+	const query = `
+		update easy_quizy_xp_ledger
+		set user_id = $2
+		where user_id = $1
+	`
+
+	_, err := r.db(ctx).ExecContext(ctx, query, from, to)
+	return err
+}
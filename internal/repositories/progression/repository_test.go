@@ -0,0 +1,207 @@
+package progression
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"easy-quizy/internal/pgtest"
+	"maps"
+	"testing"
+
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+func newTestRepository(t *testing.T) (*DefaultRepository, *sqlx.DB) {
+	t.Helper()
+
+	db := pgtest.Start(t)
+	return NewRepository(db, trmsqlx.DefaultCtxGetter), db
+}
+
+func answerEntry(userID, gameID, sessionID uuid.UUID, questionID int64, amount int64) model.XPEntry {
+	return model.XPEntry{
+		UserID:     userID,
+		Amount:     amount,
+		Reason:     model.XPReasonCorrectAnswer,
+		GameID:     gameID,
+		SessionID:  sessionID,
+		QuestionID: &questionID,
+	}
+}
+
+func completionEntry(userID, gameID, sessionID uuid.UUID, amount int64) model.XPEntry {
+	return model.XPEntry{
+		UserID:    userID,
+		Amount:    amount,
+		Reason:    model.XPReasonCompletion,
+		GameID:    gameID,
+		SessionID: sessionID,
+	}
+}
+
+func TestInsertEntries(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	userID, sessionID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name    string
+		entries func(gameID uuid.UUID) []model.XPEntry
+		want    int
+	}{
+		{
+			name: "answers and completion",
+			entries: func(gameID uuid.UUID) []model.XPEntry {
+				return []model.XPEntry{
+					answerEntry(userID, gameID, sessionID, 0, 10),
+					answerEntry(userID, gameID, sessionID, 1, 10),
+					completionEntry(userID, gameID, sessionID, 50),
+				}
+			},
+			want: 3,
+		},
+		{
+			name: "repeated answer entry is skipped",
+			entries: func(gameID uuid.UUID) []model.XPEntry {
+				return []model.XPEntry{
+					answerEntry(userID, gameID, sessionID, 0, 10),
+					answerEntry(userID, gameID, sessionID, 0, 10),
+				}
+			},
+			want: 1,
+		},
+		{
+			name: "another attempt at the same game is skipped",
+			entries: func(gameID uuid.UUID) []model.XPEntry {
+				return []model.XPEntry{
+					answerEntry(userID, gameID, sessionID, 0, 10),
+					completionEntry(userID, gameID, sessionID, 50),
+					answerEntry(userID, gameID, uuid.New(), 0, 10),
+					completionEntry(userID, gameID, uuid.New(), 50),
+				}
+			},
+			want: 2,
+		},
+		{
+			name: "other players are not affected",
+			entries: func(gameID uuid.UUID) []model.XPEntry {
+				return []model.XPEntry{
+					answerEntry(userID, gameID, sessionID, 0, 10),
+					answerEntry(uuid.New(), gameID, uuid.New(), 0, 10),
+				}
+			},
+			want: 2,
+		},
+		{
+			name: "repeated completion entry is skipped",
+			entries: func(gameID uuid.UUID) []model.XPEntry {
+				return []model.XPEntry{
+					completionEntry(userID, gameID, sessionID, 50),
+					completionEntry(userID, gameID, sessionID, 50),
+				}
+			},
+			want: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pgtest.Truncate(t, db)
+			gameID := pgtest.InsertGame(t, db, model.GameTypeClassic, 2)
+
+			inserted, err := repo.InsertEntries(ctx, tt.entries(gameID))
+			if err != nil {
+				t.Fatalf("InsertEntries: %v", err)
+			}
+			if len(inserted) != tt.want {
+				t.Errorf("InsertEntries inserted %d entries, want %d", len(inserted), tt.want)
+			}
+			for _, entry := range inserted {
+				if entry.ID == 0 || entry.CreatedAt.IsZero() {
+					t.Errorf("inserted entry %+v has no id or creation time", entry)
+				}
+			}
+		})
+	}
+}
+
+func TestGetTotalXP(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+	aliceFirst, aliceSecond, bobSession := uuid.New(), uuid.New(), uuid.New()
+
+	pgtest.Truncate(t, db)
+	gameID := pgtest.InsertGame(t, db, model.GameTypeClassic, 2)
+	if _, err := repo.InsertEntries(ctx, []model.XPEntry{
+		answerEntry(alice, gameID, aliceFirst, 0, 10),
+		completionEntry(alice, gameID, aliceFirst, 50),
+		answerEntry(alice, gameID, aliceSecond, 1, 10),
+		answerEntry(bob, gameID, bobSession, 1, 10),
+	}); err != nil {
+		t.Fatalf("InsertEntries: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		userIDs []uuid.UUID
+		exclude []uuid.UUID
+		want    map[uuid.UUID]int64
+	}{
+		{
+			name:    "sum per player, players without entries are absent",
+			userIDs: []uuid.UUID{alice, bob, carol},
+			want:    map[uuid.UUID]int64{alice: 70, bob: 10},
+		},
+		{
+			name:    "excluded sessions",
+			userIDs: []uuid.UUID{alice, bob},
+			exclude: []uuid.UUID{aliceFirst},
+			want:    map[uuid.UUID]int64{alice: 10, bob: 10},
+		},
+		{
+			name: "no players",
+			want: map[uuid.UUID]int64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.GetTotalXP(ctx, tt.userIDs, tt.exclude)
+			if err != nil {
+				t.Fatalf("GetTotalXP: %v", err)
+			}
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("GetTotalXP = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReassignEntries(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	from, to := uuid.New(), uuid.New()
+
+	pgtest.Truncate(t, db)
+	gameID := pgtest.InsertGame(t, db, model.GameTypeClassic, 2)
+	if _, err := repo.InsertEntries(ctx, []model.XPEntry{
+		completionEntry(from, gameID, uuid.New(), 50),
+		completionEntry(to, gameID, uuid.New(), 50),
+	}); err != nil {
+		t.Fatalf("InsertEntries: %v", err)
+	}
+
+	if err := repo.ReassignEntries(ctx, from, to); err != nil {
+		t.Fatalf("ReassignEntries: %v", err)
+	}
+
+	got, err := repo.GetTotalXP(ctx, []uuid.UUID{from, to}, nil)
+	if err != nil {
+		t.Fatalf("GetTotalXP: %v", err)
+	}
+	if want := map[uuid.UUID]int64{to: 100}; !maps.Equal(got, want) {
+		t.Errorf("GetTotalXP = %v, want %v", got, want)
+	}
+}
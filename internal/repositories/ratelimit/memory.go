@@ -0,0 +1,52 @@
+package ratelimit
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"sync"
+	"time"
+)
+
+type (
+	// MemoryRepository бакеты в памяти процесса, у каждой реплики свои
+	MemoryRepository struct {
+		mu      sync.Mutex
+		buckets map[string]memoryBucket
+	}
+
+	memoryBucket struct {
+		model.TokenBucket
+		expiresAt time.Time
+	}
+)
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{buckets: make(map[string]memoryBucket)}
+}
+
+func (r *MemoryRepository) Take(_ context.Context, key string, limit model.RateLimit, now time.Time) (model.RateDecision, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bucket, decision := r.buckets[key].Take(limit, now)
+	if decision.Allowed {
+		r.buckets[key] = memoryBucket{TokenBucket: bucket, expiresAt: now.Add(limit.Period)}
+	}
+
+	return decision, nil
+}
+
+func (r *MemoryRepository) DeleteExpired(_ context.Context, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for key, bucket := range r.buckets {
+		if bucket.expiresAt.Before(now) {
+			delete(r.buckets, key)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"time"
+
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/jmoiron/sqlx"
+)
+
+type (
+	// DefaultRepository бакеты в Postgres, общие для всех реплик
+	DefaultRepository struct {
+		sqlx *sqlx.DB
+		tx   *trmsqlx.CtxGetter
+	}
+
+	sqlxBucket struct {
+		Tokens    float64   `db:"tokens"`
+		UpdatedAt time.Time `db:"updated_at"`
+	}
+)
+
+func NewRepository(sqlx *sqlx.DB, tx *trmsqlx.CtxGetter) *DefaultRepository {
+	return &DefaultRepository{sqlx: sqlx, tx: tx}
+}
+
+func (r *DefaultRepository) db(ctx context.Context) trmsqlx.Tr {
+	return r.tx.DefaultTrOrDB(ctx, r.sqlx)
+}
+
+// Take забирает токен одним запросом: пополнение и списание считаются в базе под блокировкой строки,
+// как в model.TokenBucket.Take. Если токена нет, строка не меняется и запрос ничего не возвращает.
+func (r *DefaultRepository) Take(ctx context.Context, key string, limit model.RateLimit, now time.Time) (model.RateDecision, error) {
+	const query = `
+		insert into easy_quizy_rate_bucket as b (key, tokens, updated_at, expires_at)
+		values ($1, $2::double precision - 1, $3::timestamptz, $5::timestamptz)
+		on conflict (key) do update
+		set
+			tokens = least($2::double precision, b.tokens + greatest(extract(epoch from ($3 - b.updated_at))::double precision, 0) * $4::double precision) - 1,
+			updated_at = $3,
+			expires_at = $5
+		where least($2::double precision, b.tokens + greatest(extract(epoch from ($3 - b.updated_at))::double precision, 0) * $4::double precision) >= 1
+		returning tokens
+	`
+
+	var taken []float64
+	err := r.db(ctx).SelectContext(ctx, &taken, query, key, limit.Requests, now, limit.Rate(), now.Add(limit.Period))
+	if err != nil {
+		return model.RateDecision{}, err
+	}
+	if len(taken) > 0 {
+		return model.RateDecision{Allowed: true}, nil
+	}
+
+	const selectQuery = `
+		select tokens, updated_at
+		from easy_quizy_rate_bucket
+		where key = $1
+	`
+
+	var row sqlxBucket
+	if err := r.db(ctx).GetContext(ctx, &row, selectQuery, key); err != nil {
+		return model.RateDecision{}, err
+	}
+
+	bucket := model.TokenBucket{Tokens: row.Tokens, UpdatedAt: row.UpdatedAt}.Refill(limit, now)
+	return model.RateDecision{RetryAfter: bucket.RetryAfter(limit)}, nil
+}
+
+// DeleteExpired удаляет бакеты, которые к now полностью восстановились: они неотличимы от новых
+func (r *DefaultRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	const query = `delete from easy_quizy_rate_bucket where expires_at < $1`
+
+	result, err := r.db(ctx).ExecContext(ctx, query, now)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
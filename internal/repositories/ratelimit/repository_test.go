@@ -0,0 +1,144 @@
+package ratelimit
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"easy-quizy/internal/pgtest"
+	"testing"
+	"time"
+
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/jmoiron/sqlx"
+)
+
+func newTestRepository(t *testing.T) (*DefaultRepository, *sqlx.DB) {
+	t.Helper()
+
+	db := pgtest.Start(t)
+	return NewRepository(db, trmsqlx.DefaultCtxGetter), db
+}
+
+func TestTake(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	limit := model.RateLimit{Requests: 2, Period: time.Minute}
+	now := time.Now().Truncate(time.Second)
+
+	tests := []struct {
+		name           string
+		takes          []time.Time
+		wantAllowed    bool
+		wantRetryAfter time.Duration
+	}{
+		{
+			name:        "new bucket is full",
+			takes:       []time.Time{now},
+			wantAllowed: true,
+		},
+		{
+			name:        "capacity is spent",
+			takes:       []time.Time{now, now},
+			wantAllowed: true,
+		},
+		{
+			name:           "empty bucket refuses with retry after",
+			takes:          []time.Time{now, now, now},
+			wantRetryAfter: 30 * time.Second,
+		},
+		{
+			name:        "token is refilled",
+			takes:       []time.Time{now, now, now.Add(31 * time.Second)},
+			wantAllowed: true,
+		},
+		{
+			name:           "partial refill",
+			takes:          []time.Time{now, now, now.Add(20 * time.Second)},
+			wantRetryAfter: 10 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pgtest.Truncate(t, db)
+
+			var (
+				decision model.RateDecision
+				err      error
+			)
+			for _, at := range tt.takes {
+				decision, err = repo.Take(ctx, "player:1", limit, at)
+				if err != nil {
+					t.Fatalf("Take: %v", err)
+				}
+			}
+
+			if decision.Allowed != tt.wantAllowed {
+				t.Errorf("Allowed = %v, want %v", decision.Allowed, tt.wantAllowed)
+			}
+			// RetryAfter округляется вверх до наносекунды, погрешность float не важна
+			if decision.RetryAfter.Round(time.Second) != tt.wantRetryAfter {
+				t.Errorf("RetryAfter = %s, want %s", decision.RetryAfter, tt.wantRetryAfter)
+			}
+		})
+	}
+}
+
+func TestTakeKeysAreIndependent(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	limit := model.RateLimit{Requests: 1, Period: time.Minute}
+	now := time.Now()
+	pgtest.Truncate(t, db)
+
+	tests := []struct {
+		key         string
+		wantAllowed bool
+	}{
+		{key: "ip:1", wantAllowed: true},
+		{key: "ip:1", wantAllowed: false},
+		{key: "ip:2", wantAllowed: true},
+	}
+
+	for _, tt := range tests {
+		decision, err := repo.Take(ctx, tt.key, limit, now)
+		if err != nil {
+			t.Fatalf("Take: %v", err)
+		}
+		if decision.Allowed != tt.wantAllowed {
+			t.Errorf("Take(%s) Allowed = %v, want %v", tt.key, decision.Allowed, tt.wantAllowed)
+		}
+	}
+}
+
+func TestDeleteExpired(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	limit := model.RateLimit{Requests: 1, Period: time.Minute}
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want int64
+	}{
+		{name: "bucket still refilling", at: now.Add(30 * time.Second), want: 0},
+		{name: "bucket refilled", at: now.Add(2 * time.Minute), want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pgtest.Truncate(t, db)
+			if _, err := repo.Take(ctx, "ip:1", limit, now); err != nil {
+				t.Fatalf("Take: %v", err)
+			}
+
+			deleted, err := repo.DeleteExpired(ctx, tt.at)
+			if err != nil {
+				t.Fatalf("DeleteExpired: %v", err)
+			}
+			if deleted != tt.want {
+				t.Errorf("DeleteExpired = %d, want %d", deleted, tt.want)
+			}
+		})
+	}
+}
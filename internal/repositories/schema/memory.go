@@ -0,0 +1,22 @@
+package schema
+
+import "context"
+
+type (
+	// MemoryRepository схема для хранилища в памяти: всегда доступна и всегда актуальна
+	MemoryRepository struct {
+		version uint
+	}
+)
+
+func NewMemoryRepository(version uint) *MemoryRepository {
+	return &MemoryRepository{version: version}
+}
+
+func (r *MemoryRepository) Ping(_ context.Context) error {
+	return nil
+}
+
+func (r *MemoryRepository) Version(_ context.Context) (uint, bool, error) {
+	return r.version, false, nil
+}
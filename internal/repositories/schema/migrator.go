@@ -0,0 +1,150 @@
+package schema
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jmoiron/sqlx"
+)
+
+type (
+	// Migrator применяет встроенные миграции; таблица версий совместима с образом migrate/migrate
+	Migrator struct {
+		sqlx   *sqlx.DB
+		source fs.FS
+	}
+)
+
+func NewMigrator(sqlx *sqlx.DB, source fs.FS) *Migrator {
+	return &Migrator{sqlx: sqlx, source: source}
+}
+
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.run(ctx, func(mm *migrate.Migrate) error {
+		return mm.Up()
+	})
+}
+
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("steps must be positive, got %d", steps)
+	}
+
+	return m.run(ctx, func(mm *migrate.Migrate) error {
+		return mm.Steps(-steps)
+	})
+}
+
+func (m *Migrator) Status(ctx context.Context) (model.SchemaStatus, error) {
+	migrations, err := m.migrations()
+	if err != nil {
+		return model.SchemaStatus{}, err
+	}
+
+	var result model.SchemaStatus
+	err = m.run(ctx, func(mm *migrate.Migrate) error {
+		version, dirty, err := mm.Version()
+		if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+			return err
+		}
+
+		result.Version = version
+		result.Dirty = dirty
+		return nil
+	})
+	if err != nil {
+		return model.SchemaStatus{}, err
+	}
+
+	for i := range migrations {
+		migrations[i].Applied = migrations[i].Version <= result.Version
+	}
+	if len(migrations) > 0 {
+		result.Latest = migrations[len(migrations)-1].Version
+	}
+	result.Migrations = migrations
+
+	return result, nil
+}
+
+// Latest возвращает номер последней встроенной миграции
+func (m *Migrator) Latest() (uint, error) {
+	migrations, err := m.migrations()
+	if err != nil {
+		return 0, err
+	}
+	if len(migrations) == 0 {
+		return 0, nil
+	}
+
+	return migrations[len(migrations)-1].Version, nil
+}
+
+func (m *Migrator) migrations() ([]model.SchemaMigration, error) {
+	entries, err := fs.ReadDir(m.source, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	var result []model.SchemaMigration
+	for _, entry := range entries {
+		parsed, err := source.DefaultParse(entry.Name())
+		if err != nil || parsed.Direction != source.Up {
+			continue
+		}
+
+		result = append(result, model.SchemaMigration{
+			Version: parsed.Version,
+			Name:    parsed.Identifier,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+
+	return result, nil
+}
+
+// run открывает отдельное соединение, чтобы закрытие migrate не закрывало общий пул
+func (m *Migrator) run(ctx context.Context, fn func(mm *migrate.Migrate) error) error {
+	conn, err := m.sqlx.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	driver, err := postgres.WithConnection(ctx, conn, &postgres.Config{})
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	src, err := iofs.New(m.source, ".")
+	if err != nil {
+		_ = driver.Close()
+		return err
+	}
+
+	mm, err := migrate.NewWithInstance("iofs", src, "postgres", driver)
+	if err != nil {
+		_ = src.Close()
+		_ = driver.Close()
+		return err
+	}
+	defer mm.Close()
+
+	err = fn(mm)
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,48 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type (
+	DefaultRepository struct {
+		sqlx *sqlx.DB
+	}
+
+	sqlxSchemaMigration struct {
+		Version int64 `db:"version"`
+		Dirty   bool  `db:"dirty"`
+	}
+)
+
+func NewRepository(sqlx *sqlx.DB) *DefaultRepository {
+	return &DefaultRepository{sqlx: sqlx}
+}
+
+func (r *DefaultRepository) Ping(ctx context.Context) error {
+	return r.sqlx.PingContext(ctx)
+}
+
+// Version возвращает версию схемы из таблицы golang-migrate
+func (r *DefaultRepository) Version(ctx context.Context) (uint, bool, error) {
+	const query = `
+		select version, dirty
+		from schema_migrations
+		limit 1
+	`
+
+	var result sqlxSchemaMigration
+	if err := r.sqlx.GetContext(ctx, &result, query); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, false, nil
+		}
+
+		return 0, false, err
+	}
+
+	return uint(result.Version), result.Dirty, nil
+}
@@ -0,0 +1,71 @@
+package schema_test
+
+import (
+	"context"
+	"easy-quizy/internal/pgtest"
+	schemaRepo "easy-quizy/internal/repositories/schema"
+	"easy-quizy/migrations"
+	"testing"
+)
+
+func TestVersion(t *testing.T) {
+	db := pgtest.Start(t)
+	ctx := context.Background()
+	repo := schemaRepo.NewRepository(db)
+	migrator := schemaRepo.NewMigrator(db, migrations.FS)
+
+	latest, err := migrator.Latest()
+	if err != nil {
+		t.Fatalf("Latest: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		apply func() error
+		want  uint
+	}{
+		{
+			name:  "migrated by pgtest",
+			apply: func() error { return nil },
+			want:  latest,
+		},
+		{
+			name:  "one step down",
+			apply: func() error { return migrator.Down(ctx, 1) },
+			want:  latest - 1,
+		},
+		{
+			name:  "up again",
+			apply: func() error { return migrator.Up(ctx) },
+			want:  latest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.apply(); err != nil {
+				t.Fatalf("apply: %v", err)
+			}
+
+			if err := repo.Ping(ctx); err != nil {
+				t.Fatalf("Ping: %v", err)
+			}
+
+			version, dirty, err := repo.Version(ctx)
+			if err != nil {
+				t.Fatalf("Version: %v", err)
+			}
+			if version != tt.want || dirty {
+				t.Errorf("Version = %d (dirty %v), want %d", version, dirty, tt.want)
+			}
+
+			status, err := migrator.Status(ctx)
+			if err != nil {
+				t.Fatalf("Status: %v", err)
+			}
+			if status.Version != tt.want || status.Latest != latest {
+				t.Errorf("Status = %d of %d, want %d of %d", status.Version, status.Latest, tt.want, latest)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package user
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+// SourceMovedChannel канал Postgres, в который ReassignSources и MoveSource пишут перенесенные источники
+const SourceMovedChannel = "easy_quizy_user_source_moved"
+
+const (
+	listenerMinReconnect = time.Second
+	listenerMaxReconnect = time.Minute
+	listenerPingInterval = time.Minute
+)
+
+type (
+	// SourceListener получает уведомления о перенесенных источниках от всех реплик
+	SourceListener struct {
+		dsn string
+	}
+
+	sourceMovedPayload struct {
+		IDext  string `json:"idExt"`
+		Source string `json:"source"`
+	}
+)
+
+func NewSourceListener(dsn string) *SourceListener {
+	return &SourceListener{dsn: dsn}
+}
+
+// Listen передает в moved источники, сменившие пользователя, до отмены ctx.
+// Пока соединение было потеряно, уведомления могли пропасть, поэтому после разрыва и переподключения вызывается lost
+func (l *SourceListener) Listen(ctx context.Context, moved func(sources []model.UserSource), lost func()) error {
+	listener := pq.NewListener(l.dsn, listenerMinReconnect, listenerMaxReconnect, func(event pq.ListenerEventType, err error) {
+		switch event {
+		case pq.ListenerEventDisconnected:
+			logrus.Warnf("User source listener disconnected: %v", err)
+			lost()
+		case pq.ListenerEventReconnected:
+			lost()
+		case pq.ListenerEventConnectionAttemptFailed:
+			logrus.Warnf("User source listener failed to connect: %v", err)
+		}
+	})
+	defer listener.Close()
+
+	// Listen ждет соединения с базой, закрытие слушателя прерывает ожидание при остановке
+	stop := context.AfterFunc(ctx, func() { _ = listener.Close() })
+	defer stop()
+
+	if err := listener.Listen(SourceMovedChannel); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+
+	ticker := time.NewTicker(listenerPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case notification := <-listener.Notify:
+			// nil приходит после переподключения, lost уже вызван из обработчика событий
+			if notification == nil {
+				continue
+			}
+
+			source, err := decodeSourceMoved(notification.Extra)
+			if err != nil {
+				logrus.Errorf("Failed to decode moved user source %q: %v", notification.Extra, err)
+				lost()
+				continue
+			}
+			moved([]model.UserSource{source})
+		case <-ticker.C:
+			// Ping находит разрыв соединения, когда уведомлений долго нет
+			if err := listener.Ping(); err != nil {
+				logrus.Warnf("User source listener ping failed: %v", err)
+			}
+		}
+	}
+}
+
+func decodeSourceMoved(payload string) (model.UserSource, error) {
+	var moved sourceMovedPayload
+	if err := json.Unmarshal([]byte(payload), &moved); err != nil {
+		return model.UserSource{}, err
+	}
+
+	return model.UserSource{IDext: moved.IDext, Source: moved.Source}, nil
+}
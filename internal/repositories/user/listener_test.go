@@ -0,0 +1,36 @@
+package user
+
+import (
+	"easy-quizy/internal/model"
+	"encoding/json"
+	"testing"
+)
+
+func TestDecodeSourceMoved(t *testing.T) {
+	payload, err := json.Marshal(sourceMovedPayload{IDext: "42", Source: "telegram"})
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		payload string
+		want    model.UserSource
+		wantErr bool
+	}{
+		{name: "source", payload: string(payload), want: model.UserSource{IDext: "42", Source: "telegram"}},
+		{name: "not json", payload: "42:telegram", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeSourceMoved(tt.payload)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeSourceMoved(%q) error = %v, wantErr %v", tt.payload, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Fatalf("decodeSourceMoved(%q) = %+v, want %+v", tt.payload, got, tt.want)
+			}
+		})
+	}
+}
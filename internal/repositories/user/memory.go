@@ -0,0 +1,221 @@
+package user
+
+import (
+	"context"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// MemoryRepository хранит пользователей в памяти процесса, для тестов и демо-режима
+	MemoryRepository struct {
+		mu      sync.RWMutex
+		sources map[sourceKey]model.UserSource
+		chats   map[chatKey]model.UserChat
+	}
+
+	sourceKey struct {
+		userIDext string
+		source    string
+	}
+
+	chatKey struct {
+		userID uuid.UUID
+		chatID int64
+	}
+)
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		sources: make(map[sourceKey]model.UserSource),
+		chats:   make(map[chatKey]model.UserChat),
+	}
+}
+
+func (r *MemoryRepository) CreateSource(_ context.Context, user model.UserSource) (model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := sourceKey{userIDext: user.IDext, source: user.Source}
+	if existing, exists := r.sources[key]; exists {
+		return existing.User, nil
+	}
+
+	user.CreatedAt = time.Now()
+	r.sources[key] = user
+	return user.User, nil
+}
+
+func (r *MemoryRepository) GetUserBySource(_ context.Context, userIDext string, source string) (model.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.sources[sourceKey{userIDext: userIDext, source: source}]
+	if !ok {
+		return model.User{}, contracts.ErrUserNotFound
+	}
+
+	return user.User, nil
+}
+
+func (r *MemoryRepository) InsertUserChat(_ context.Context, user model.UserChat) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := chatKey{userID: user.ID, chatID: user.ChatID}
+	if _, exists := r.chats[key]; exists {
+		return nil
+	}
+
+	r.chats[key] = user
+	return nil
+}
+
+func (r *MemoryRepository) GetUserChat(_ context.Context, userID uuid.UUID, chatID int64) (model.UserChat, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	chat, ok := r.chats[chatKey{userID: userID, chatID: chatID}]
+	if !ok {
+		return model.UserChat{}, contracts.ErrUserChatNotFound
+	}
+
+	return chat, nil
+}
+
+func (r *MemoryRepository) GetUserSources(_ context.Context, userID uuid.UUID) ([]model.UserSource, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []model.UserSource
+	for _, user := range r.sources {
+		if user.ID == userID {
+			result = append(result, user)
+		}
+	}
+
+	return result, nil
+}
+
+func (r *MemoryRepository) GetSourcesCreatedBetween(_ context.Context, from time.Time, to time.Time) ([]model.UserSource, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []model.UserSource
+	for _, user := range r.sources {
+		if !user.CreatedAt.Before(from) && !user.CreatedAt.After(to) {
+			result = append(result, user)
+		}
+	}
+
+	return result, nil
+}
+
+func (r *MemoryRepository) ReassignChats(_ context.Context, from uuid.UUID, to uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, chat := range r.chats {
+		if key.userID != from {
+			continue
+		}
+
+		delete(r.chats, key)
+		target := chatKey{userID: to, chatID: key.chatID}
+		if _, exists := r.chats[target]; !exists {
+			chat.User = model.User{ID: to}
+			r.chats[target] = chat
+		}
+	}
+
+	return nil
+}
+
+func (r *MemoryRepository) GetUserChats(_ context.Context, userID uuid.UUID) ([]model.UserChat, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []model.UserChat
+	for key, chat := range r.chats {
+		if key.userID != userID {
+			continue
+		}
+
+		result = append(result, chat)
+	}
+
+	return result, nil
+}
+
+func (r *MemoryRepository) GetChatMates(_ context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	chats := make(map[int64]struct{})
+	for key := range r.chats {
+		if key.userID == userID {
+			chats[key.chatID] = struct{}{}
+		}
+	}
+
+	mates := make(map[uuid.UUID]struct{})
+	for key := range r.chats {
+		if _, ok := chats[key.chatID]; ok && key.userID != userID {
+			mates[key.userID] = struct{}{}
+		}
+	}
+
+	result := make([]uuid.UUID, 0, len(mates))
+	for mate := range mates {
+		result = append(result, mate)
+	}
+
+	return result, nil
+}
+
+func (r *MemoryRepository) GetChatMembers(_ context.Context, chatID int64) ([]uuid.UUID, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]uuid.UUID, 0)
+	for key := range r.chats {
+		if key.chatID == chatID {
+			result = append(result, key.userID)
+		}
+	}
+
+	return result, nil
+}
+
+func (r *MemoryRepository) ReassignSources(_ context.Context, from uuid.UUID, to uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, user := range r.sources {
+		if user.ID == from {
+			user.User = model.User{ID: to}
+			r.sources[key] = user
+		}
+	}
+
+	return nil
+}
+
+func (r *MemoryRepository) MoveSource(_ context.Context, source model.UserSource, to uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := sourceKey{userIDext: source.IDext, source: source.Source}
+	stored, ok := r.sources[key]
+	if !ok || stored.ID != source.ID {
+		return contracts.ErrUserSourceNotFound
+	}
+
+	stored.User = model.User{ID: to}
+	r.sources[key] = stored
+	return nil
+}
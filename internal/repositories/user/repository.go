@@ -4,6 +4,9 @@ import (
 	"context"
 	"easy-quizy/internal/contracts"
 	"easy-quizy/internal/model"
+	"easy-quizy/pkg/structs/collections/slices"
+	"encoding/json"
+	"time"
 
 	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
 	"github.com/google/uuid"
@@ -20,6 +23,12 @@ type (
 		UserIDint uuid.UUID `db:"user_id_int"`
 		UserIDext string    `db:"user_id_ext"`
 		Source    string    `db:"source"`
+		CreatedAt time.Time `db:"created_at"`
+	}
+
+	sqlxMovedSource struct {
+		UserIDext string `db:"user_id_ext"`
+		Source    string `db:"source"`
 	}
 
 	sqlxUserChat struct {
@@ -37,28 +46,36 @@ func (r *DefaultRepository) db(ctx context.Context) trmsqlx.Tr {
 	return r.tx.DefaultTrOrDB(ctx, r.sqlx)
 }
 
-func (r *DefaultRepository) InsertSource(ctx context.Context, user model.UserSource) error {
+// CreateSource создает источник пользователя, а если его уже создал параллельный запрос, возвращает сохраненного пользователя
+func (r *DefaultRepository) CreateSource(ctx context.Context, user model.UserSource) (model.User, error) {
+	// do update вместо do nothing, чтобы returning вернул строку и при конфликте
 	const query = `
 	   insert into easy_quizy_user_source
 	   (user_id_int, user_id_ext, "source")
 	   values ($1, $2, $3)
-	   on conflict (user_id_ext, "source") do nothing
+	   on conflict (user_id_ext, "source") do update set "source" = excluded."source"
+	   returning user_id_int
 	`
 
-	_, err := r.db(ctx).ExecContext(
+	var userID uuid.UUID
+	err := r.db(ctx).GetContext(
 		ctx,
+		&userID,
 		query,
 		user.ID,
 		user.IDext,
 		user.Source,
 	)
+	if err != nil {
+		return model.User{}, err
+	}
 
-	return err
+	return model.User{ID: userID}, nil
 }
 
 func (r *DefaultRepository) GetUserBySource(ctx context.Context, userIDext string, source string) (model.User, error) {
 	const query = `
-  	   select user_id_int, user_id_ext, "source"
+  	   select user_id_int, user_id_ext, "source", created_at
   	   from easy_quizy_user_source
   	   where user_id_ext= $1 and source = $2
 	`
@@ -120,3 +137,172 @@ func (r *DefaultRepository) GetUserChat(ctx context.Context, userID uuid.UUID, c
 		ChatType: result[0].ChatType,
 	}, nil
 }
+
+func (r *DefaultRepository) GetUserSources(ctx context.Context, userID uuid.UUID) ([]model.UserSource, error) {
+	const query = `
+	   select user_id_int, user_id_ext, "source", created_at
+	   from easy_quizy_user_source
+	   where user_id_int = $1
+	   order by id
+	`
+
+	var result []sqlxUserSource
+	if err := r.db(ctx).SelectContext(ctx, &result, query, userID); err != nil {
+		return nil, err
+	}
+
+	return slices.SafeMap(result, convertToUserSource), nil
+}
+
+// GetSourcesCreatedBetween возвращает источники, созданные в промежутке [from, to]
+func (r *DefaultRepository) GetSourcesCreatedBetween(ctx context.Context, from time.Time, to time.Time) ([]model.UserSource, error) {
+	const query = `
+	   select user_id_int, user_id_ext, "source", created_at
+	   from easy_quizy_user_source
+	   where created_at between $1 and $2
+	   order by id
+	`
+
+	var result []sqlxUserSource
+	if err := r.db(ctx).SelectContext(ctx, &result, query, from, to); err != nil {
+		return nil, err
+	}
+
+	return slices.SafeMap(result, convertToUserSource), nil
+}
+
+// ReassignChats переносит чаты пользователя from на пользователя to, уже известные to чаты не дублируются
+func (r *DefaultRepository) ReassignChats(ctx context.Context, from uuid.UUID, to uuid.UUID) error {
+	const query = `
+	   with moved as (
+	       delete from easy_quizy_user_chat
+	       where user_id = $1
+	       returning chat_id, chat_type, created_at
+	   )
+	   insert into easy_quizy_user_chat (user_id, chat_id, chat_type, created_at)
+	   select $2, chat_id, chat_type, created_at
+	   from moved
+	   on conflict (user_id, chat_id) do nothing
+	`
+
+	_, err := r.db(ctx).ExecContext(ctx, query, from, to)
+	return err
+}
+
+// ReassignSources переносит все источники пользователя from на пользователя to
+func (r *DefaultRepository) ReassignSources(ctx context.Context, from uuid.UUID, to uuid.UUID) error {
+	const query = `
+	   update easy_quizy_user_source
+	   set user_id_int = $2
+	   where user_id_int = $1
+	   returning user_id_ext, "source"
+	`
+
+	var moved []sqlxMovedSource
+	if err := r.db(ctx).SelectContext(ctx, &moved, query, from, to); err != nil {
+		return err
+	}
+
+	return r.notifySourcesMoved(ctx, moved)
+}
+
+// MoveSource переносит источник пользователя на пользователя to
+func (r *DefaultRepository) MoveSource(ctx context.Context, source model.UserSource, to uuid.UUID) error {
+	const query = `
+	   update easy_quizy_user_source
+	   set user_id_int = $4
+	   where user_id_ext = $1 and "source" = $2 and user_id_int = $3
+	   returning user_id_ext, "source"
+	`
+
+	var moved []sqlxMovedSource
+	if err := r.db(ctx).SelectContext(ctx, &moved, query, source.IDext, source.Source, source.ID, to); err != nil {
+		return err
+	}
+	if len(moved) == 0 {
+		return contracts.ErrUserSourceNotFound
+	}
+
+	return r.notifySourcesMoved(ctx, moved)
+}
+
+// notifySourcesMoved сообщает другим репликам о перенесенных источниках, в транзакции уведомление уходит после коммита
+func (r *DefaultRepository) notifySourcesMoved(ctx context.Context, moved []sqlxMovedSource) error {
+	const query = `select pg_notify($1, $2)`
+
+	for _, source := range moved {
+		payload, err := json.Marshal(sourceMovedPayload{IDext: source.UserIDext, Source: source.Source})
+		if err != nil {
+			return err
+		}
+		if _, err := r.db(ctx).ExecContext(ctx, query, SourceMovedChannel, string(payload)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func convertToUserSource(item sqlxUserSource) model.UserSource {
+	return model.UserSource{
+		User:      model.User{ID: item.UserIDint},
+		IDext:     item.UserIDext,
+		Source:    item.Source,
+		CreatedAt: item.CreatedAt,
+	}
+}
+
+func (r *DefaultRepository) GetUserChats(ctx context.Context, userID uuid.UUID) ([]model.UserChat, error) {
+	const query = `
+	   select user_id, chat_id, chat_type
+	   from easy_quizy_user_chat
+	   where user_id = $1
+	   order by id
+	`
+
+	var result []sqlxUserChat
+	if err := r.db(ctx).SelectContext(ctx, &result, query, userID); err != nil {
+		return nil, err
+	}
+
+	return slices.SafeMap(result, func(item sqlxUserChat) model.UserChat {
+		return model.UserChat{
+			User:     model.User{ID: item.UserID},
+			ChatID:   item.ChatID,
+			ChatType: item.ChatType,
+		}
+	}), nil
+}
+
+// GetChatMembers возвращает всех известных участников чата
+func (r *DefaultRepository) GetChatMembers(ctx context.Context, chatID int64) ([]uuid.UUID, error) {
+	const query = `
+	   select user_id
+	   from easy_quizy_user_chat
+	   where chat_id = $1
+	`
+
+	var result []uuid.UUID
+	if err := r.db(ctx).SelectContext(ctx, &result, query, chatID); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetChatMates возвращает пользователей, с которыми userID состоит хотя бы в одном общем чате
+func (r *DefaultRepository) GetChatMates(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	const query = `
+	   select distinct mate.user_id
+	   from easy_quizy_user_chat own
+	   inner join easy_quizy_user_chat mate on mate.chat_id = own.chat_id
+	   where own.user_id = $1 and mate.user_id <> $1
+	`
+
+	var result []uuid.UUID
+	if err := r.db(ctx).SelectContext(ctx, &result, query, userID); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
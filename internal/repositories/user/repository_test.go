@@ -0,0 +1,349 @@
+package user
+
+import (
+	"context"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"easy-quizy/internal/pgtest"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+func newTestRepository(t *testing.T) (*DefaultRepository, *sqlx.DB) {
+	t.Helper()
+
+	db := pgtest.Start(t)
+	return NewRepository(db, trmsqlx.DefaultCtxGetter), db
+}
+
+func source(userID uuid.UUID, ext string) model.UserSource {
+	return model.UserSource{User: model.User{ID: userID}, IDext: ext, Source: "telegram"}
+}
+
+func TestCreateSource(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	first, second := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name    string
+		sources []model.UserSource
+		want    []uuid.UUID
+	}{
+		{
+			name:    "new source",
+			sources: []model.UserSource{source(first, "1")},
+			want:    []uuid.UUID{first},
+		},
+		{
+			name:    "existing source keeps its user",
+			sources: []model.UserSource{source(first, "1"), source(second, "1")},
+			want:    []uuid.UUID{first, first},
+		},
+		{
+			name:    "different sources",
+			sources: []model.UserSource{source(first, "1"), source(second, "2")},
+			want:    []uuid.UUID{first, second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pgtest.Truncate(t, db)
+
+			for i, item := range tt.sources {
+				user, err := repo.CreateSource(ctx, item)
+				if err != nil {
+					t.Fatalf("CreateSource: %v", err)
+				}
+				if user.ID != tt.want[i] {
+					t.Errorf("CreateSource #%d = %s, want %s", i, user.ID, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGetUserBySource(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	tests := []struct {
+		name    string
+		ext     string
+		source  string
+		want    uuid.UUID
+		wantErr error
+	}{
+		{name: "found", ext: "1", source: "telegram", want: userID},
+		{name: "unknown id", ext: "2", source: "telegram", wantErr: contracts.ErrUserNotFound},
+		{name: "other source", ext: "1", source: "web", wantErr: contracts.ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pgtest.Truncate(t, db)
+			if _, err := repo.CreateSource(ctx, source(userID, "1")); err != nil {
+				t.Fatalf("CreateSource: %v", err)
+			}
+
+			user, err := repo.GetUserBySource(ctx, tt.ext, tt.source)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetUserBySource error = %v, want %v", err, tt.wantErr)
+			}
+			if user.ID != tt.want {
+				t.Errorf("GetUserBySource = %s, want %s", user.ID, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserChats(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+
+	chats := []model.UserChat{
+		{User: model.User{ID: alice}, ChatID: 1, ChatType: "group"},
+		{User: model.User{ID: bob}, ChatID: 1, ChatType: "group"},
+		{User: model.User{ID: alice}, ChatID: 2, ChatType: "private"},
+		{User: model.User{ID: carol}, ChatID: 3, ChatType: "group"},
+		// повтор не дублирует чат
+		{User: model.User{ID: alice}, ChatID: 1, ChatType: "group"},
+	}
+
+	tests := []struct {
+		name  string
+		check func(t *testing.T)
+	}{
+		{
+			name: "GetUserChat",
+			check: func(t *testing.T) {
+				chat, err := repo.GetUserChat(ctx, alice, 1)
+				if err != nil {
+					t.Fatalf("GetUserChat: %v", err)
+				}
+				if chat.ChatType != "group" {
+					t.Errorf("ChatType = %q, want group", chat.ChatType)
+				}
+
+				if _, err := repo.GetUserChat(ctx, carol, 1); !errors.Is(err, contracts.ErrUserChatNotFound) {
+					t.Errorf("GetUserChat of non-member error = %v, want %v", err, contracts.ErrUserChatNotFound)
+				}
+			},
+		},
+		{
+			name: "GetUserChats",
+			check: func(t *testing.T) {
+				got, err := repo.GetUserChats(ctx, alice)
+				if err != nil {
+					t.Fatalf("GetUserChats: %v", err)
+				}
+				if len(got) != 2 || got[0].ChatID != 1 || got[1].ChatID != 2 {
+					t.Errorf("GetUserChats = %v, want chats 1 and 2", got)
+				}
+			},
+		},
+		{
+			name: "GetChatMembers",
+			check: func(t *testing.T) {
+				got, err := repo.GetChatMembers(ctx, 1)
+				if err != nil {
+					t.Fatalf("GetChatMembers: %v", err)
+				}
+				if !sameIDs(got, []uuid.UUID{alice, bob}) {
+					t.Errorf("GetChatMembers = %v, want %v", got, []uuid.UUID{alice, bob})
+				}
+			},
+		},
+		{
+			name: "GetChatMates",
+			check: func(t *testing.T) {
+				got, err := repo.GetChatMates(ctx, alice)
+				if err != nil {
+					t.Fatalf("GetChatMates: %v", err)
+				}
+				if !sameIDs(got, []uuid.UUID{bob}) {
+					t.Errorf("GetChatMates = %v, want %v", got, []uuid.UUID{bob})
+				}
+			},
+		},
+	}
+
+	pgtest.Truncate(t, db)
+	for _, chat := range chats {
+		if err := repo.InsertUserChat(ctx, chat); err != nil {
+			t.Fatalf("InsertUserChat: %v", err)
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, tt.check)
+	}
+}
+
+func TestGetSourcesCreatedBetween(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	pgtest.Truncate(t, db)
+
+	userID := uuid.New()
+	if _, err := repo.CreateSource(ctx, source(userID, "1")); err != nil {
+		t.Fatalf("CreateSource: %v", err)
+	}
+
+	now := time.Now()
+	tests := []struct {
+		name      string
+		from      time.Time
+		to        time.Time
+		wantCount int
+	}{
+		{name: "window around creation", from: now.Add(-time.Minute), to: now.Add(time.Minute), wantCount: 1},
+		{name: "window before creation", from: now.Add(-time.Hour), to: now.Add(-time.Minute), wantCount: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.GetSourcesCreatedBetween(ctx, tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("GetSourcesCreatedBetween: %v", err)
+			}
+			if len(got) != tt.wantCount {
+				t.Errorf("GetSourcesCreatedBetween = %v, want %d sources", got, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestReassign(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	from, to := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name  string
+		apply func(t *testing.T) error
+		check func(t *testing.T)
+	}{
+		{
+			name: "ReassignChats keeps chats of the target once",
+			apply: func(t *testing.T) error {
+				return repo.ReassignChats(ctx, from, to)
+			},
+			check: func(t *testing.T) {
+				got, err := repo.GetUserChats(ctx, to)
+				if err != nil {
+					t.Fatalf("GetUserChats: %v", err)
+				}
+				if len(got) != 2 {
+					t.Errorf("target chats = %v, want chats 1 and 2", got)
+				}
+
+				left, err := repo.GetUserChats(ctx, from)
+				if err != nil {
+					t.Fatalf("GetUserChats: %v", err)
+				}
+				if len(left) != 0 {
+					t.Errorf("source chats = %v, want none", left)
+				}
+			},
+		},
+		{
+			name: "ReassignSources moves every source",
+			apply: func(t *testing.T) error {
+				return repo.ReassignSources(ctx, from, to)
+			},
+			check: func(t *testing.T) {
+				got, err := repo.GetUserSources(ctx, to)
+				if err != nil {
+					t.Fatalf("GetUserSources: %v", err)
+				}
+				if len(got) != 3 {
+					t.Errorf("target sources = %v, want 3", got)
+				}
+			},
+		},
+		{
+			name: "MoveSource moves one source",
+			apply: func(t *testing.T) error {
+				return repo.MoveSource(ctx, source(from, "from-1"), to)
+			},
+			check: func(t *testing.T) {
+				user, err := repo.GetUserBySource(ctx, "from-1", "telegram")
+				if err != nil {
+					t.Fatalf("GetUserBySource: %v", err)
+				}
+				if user.ID != to {
+					t.Errorf("moved source belongs to %s, want %s", user.ID, to)
+				}
+
+				rest, err := repo.GetUserSources(ctx, from)
+				if err != nil {
+					t.Fatalf("GetUserSources: %v", err)
+				}
+				if len(rest) != 1 {
+					t.Errorf("source user keeps %v, want one source", rest)
+				}
+			},
+		},
+		{
+			name: "MoveSource of a foreign source",
+			apply: func(t *testing.T) error {
+				err := repo.MoveSource(ctx, source(to, "from-1"), from)
+				if !errors.Is(err, contracts.ErrUserSourceNotFound) {
+					t.Errorf("MoveSource error = %v, want %v", err, contracts.ErrUserSourceNotFound)
+				}
+
+				return nil
+			},
+			check: func(t *testing.T) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pgtest.Truncate(t, db)
+			for _, item := range []model.UserSource{source(from, "from-1"), source(from, "from-2"), source(to, "to-1")} {
+				if _, err := repo.CreateSource(ctx, item); err != nil {
+					t.Fatalf("CreateSource: %v", err)
+				}
+			}
+			for _, chat := range []model.UserChat{
+				{User: model.User{ID: from}, ChatID: 1, ChatType: "group"},
+				{User: model.User{ID: from}, ChatID: 2, ChatType: "group"},
+				{User: model.User{ID: to}, ChatID: 1, ChatType: "group"},
+			} {
+				if err := repo.InsertUserChat(ctx, chat); err != nil {
+					t.Fatalf("InsertUserChat: %v", err)
+				}
+			}
+
+			if err := tt.apply(t); err != nil {
+				t.Fatalf("apply: %v", err)
+			}
+			tt.check(t)
+		})
+	}
+}
+
+func sameIDs(got []uuid.UUID, want []uuid.UUID) bool {
+	if len(got) != len(want) {
+		return false
+	}
+
+	for _, id := range want {
+		if !slices.Contains(got, id) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,214 @@
+package webhook
+
+import (
+	"context"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// MemoryRepository вебхуки и журнал доставок в памяти процесса для демо-режима
+	MemoryRepository struct {
+		mu         sync.Mutex
+		nextID     int64
+		webhooks   []model.Webhook
+		deliveries []*model.WebhookDelivery
+	}
+)
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{}
+}
+
+func (r *MemoryRepository) InsertWebhook(_ context.Context, webhook model.Webhook) (model.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	webhook.EventTypes = slices.Clone(webhook.EventTypes)
+	webhook.Active = true
+	webhook.CreatedAt = time.Now()
+	r.webhooks = append(r.webhooks, webhook)
+
+	return webhook, nil
+}
+
+func (r *MemoryRepository) GetWebhook(_ context.Context, id uuid.UUID) (model.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, webhook := range r.webhooks {
+		if webhook.ID == id {
+			return webhook, nil
+		}
+	}
+
+	return model.Webhook{}, contracts.ErrWebhookNotFound
+}
+
+func (r *MemoryRepository) GetWebhooks(_ context.Context, gameID uuid.UUID) ([]model.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]model.Webhook, 0)
+	for _, webhook := range r.webhooks {
+		if webhook.GameID == gameID && webhook.Active {
+			result = append(result, webhook)
+		}
+	}
+
+	return result, nil
+}
+
+func (r *MemoryRepository) DisableWebhook(_ context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.webhooks {
+		if r.webhooks[i].ID == id && r.webhooks[i].Active {
+			r.webhooks[i].Active = false
+			return nil
+		}
+	}
+
+	return contracts.ErrWebhookNotFound
+}
+
+func (r *MemoryRepository) InsertDeliveries(_ context.Context, deliveries []model.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, delivery := range deliveries {
+		exists := slices.ContainsFunc(r.deliveries, func(existing *model.WebhookDelivery) bool {
+			return existing.WebhookID == delivery.WebhookID && existing.EventID == delivery.EventID
+		})
+		if exists {
+			continue
+		}
+
+		r.nextID++
+		r.deliveries = append(r.deliveries, &model.WebhookDelivery{
+			ID:            r.nextID,
+			WebhookID:     delivery.WebhookID,
+			EventID:       delivery.EventID,
+			EventType:     delivery.EventType,
+			Body:          delivery.Body,
+			Status:        model.DeliveryStatusPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+	}
+
+	return nil
+}
+
+func (r *MemoryRepository) ClaimDelivery(_ context.Context, now, leaseUntil time.Time) (model.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var next *model.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.Status != model.DeliveryStatusPending || delivery.NextAttemptAt.After(now) {
+			continue
+		}
+		if next == nil || delivery.NextAttemptAt.Before(next.NextAttemptAt) {
+			next = delivery
+		}
+	}
+	if next == nil {
+		return model.WebhookDelivery{}, contracts.ErrNoPendingDelivery
+	}
+
+	next.NextAttemptAt = leaseUntil
+	return *next, nil
+}
+
+func (r *MemoryRepository) MarkDelivered(_ context.Context, id int64, statusCode int64, deliveredAt time.Time) error {
+	return r.update(id, func(delivery *model.WebhookDelivery) {
+		delivery.Status = model.DeliveryStatusSucceeded
+		delivery.Attempts++
+		delivery.LastStatusCode = &statusCode
+		delivery.LastError = nil
+		delivery.DeliveredAt = &deliveredAt
+	})
+}
+
+func (r *MemoryRepository) ScheduleRetry(
+	_ context.Context,
+	id int64,
+	nextAttemptAt time.Time,
+	statusCode *int64,
+	lastError string,
+) error {
+	return r.update(id, func(delivery *model.WebhookDelivery) {
+		delivery.Attempts++
+		delivery.NextAttemptAt = nextAttemptAt
+		delivery.LastStatusCode = statusCode
+		delivery.LastError = &lastError
+	})
+}
+
+func (r *MemoryRepository) MarkFailed(_ context.Context, id int64, statusCode *int64, lastError string) error {
+	return r.update(id, func(delivery *model.WebhookDelivery) {
+		delivery.Status = model.DeliveryStatusFailed
+		delivery.Attempts++
+		delivery.LastStatusCode = statusCode
+		delivery.LastError = &lastError
+	})
+}
+
+func (r *MemoryRepository) ResetDelivery(_ context.Context, id int64, now time.Time) (model.WebhookDelivery, error) {
+	var result model.WebhookDelivery
+	err := r.update(id, func(delivery *model.WebhookDelivery) {
+		delivery.Status = model.DeliveryStatusPending
+		delivery.Attempts = 0
+		delivery.NextAttemptAt = now
+		delivery.DeliveredAt = nil
+		result = *delivery
+	})
+
+	return result, err
+}
+
+func (r *MemoryRepository) GetDeliveries(
+	_ context.Context,
+	webhookID uuid.UUID,
+	page model.Page,
+) (model.WebhookDeliveries, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items := make([]model.WebhookDelivery, 0)
+	for _, delivery := range slices.Backward(r.deliveries) {
+		if delivery.WebhookID == webhookID {
+			items = append(items, *delivery)
+		}
+	}
+
+	result := model.WebhookDeliveries{Items: []model.WebhookDelivery{}, Total: int64(len(items))}
+	if page.Offset < int64(len(items)) {
+		end := min(page.Offset+page.Limit, int64(len(items)))
+		result.Items = items[page.Offset:end]
+	}
+
+	return result, nil
+}
+
+func (r *MemoryRepository) update(id int64, apply func(delivery *model.WebhookDelivery)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, delivery := range r.deliveries {
+		if delivery.ID == id {
+			apply(delivery)
+			return nil
+		}
+	}
+
+	return contracts.ErrWebhookDeliveryNotFound
+}
@@ -0,0 +1,333 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"errors"
+	"time"
+
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type (
+	DefaultRepository struct {
+		sqlx *sqlx.DB
+		tx   *trmsqlx.CtxGetter
+	}
+
+	sqlxWebhook struct {
+		ID         uuid.UUID      `db:"id"`
+		GameID     uuid.UUID      `db:"game_id"`
+		URL        string         `db:"url"`
+		Secret     string         `db:"secret"`
+		EventTypes pq.StringArray `db:"event_types"`
+		Active     bool           `db:"active"`
+		CreatedAt  time.Time      `db:"created_at"`
+	}
+
+	sqlxDelivery struct {
+		ID             int64      `db:"id"`
+		WebhookID      uuid.UUID  `db:"webhook_id"`
+		EventID        int64      `db:"event_id"`
+		EventType      string     `db:"event_type"`
+		Body           []byte     `db:"body"`
+		Status         string     `db:"status"`
+		Attempts       int64      `db:"attempts"`
+		NextAttemptAt  time.Time  `db:"next_attempt_at"`
+		LastStatusCode *int64     `db:"last_status_code"`
+		LastError      *string    `db:"last_error"`
+		CreatedAt      time.Time  `db:"created_at"`
+		DeliveredAt    *time.Time `db:"delivered_at"`
+	}
+)
+
+const deliveryColumns = `
+	id, webhook_id, event_id, event_type, body, status, attempts, next_attempt_at,
+	last_status_code, last_error, created_at, delivered_at
+`
+
+func NewRepository(sqlx *sqlx.DB, tx *trmsqlx.CtxGetter) *DefaultRepository {
+	return &DefaultRepository{sqlx: sqlx, tx: tx}
+}
+
+func (r *DefaultRepository) db(ctx context.Context) trmsqlx.Tr {
+	return r.tx.DefaultTrOrDB(ctx, r.sqlx)
+}
+
+func (r *DefaultRepository) InsertWebhook(ctx context.Context, webhook model.Webhook) (model.Webhook, error) {
+	const query = `
+		insert into easy_quizy_webhook (id, game_id, url, secret, event_types)
+		values ($1, $2, $3, $4, $5)
+		returning id, game_id, url, secret, event_types, active, created_at
+	`
+
+	var result sqlxWebhook
+	err := r.db(ctx).GetContext(ctx, &result, query,
+		webhook.ID, webhook.GameID, webhook.URL, webhook.Secret, pq.Array(eventTypesToStrings(webhook.EventTypes)),
+	)
+	if err != nil {
+		return model.Webhook{}, err
+	}
+
+	return convertToWebhook(result), nil
+}
+
+func (r *DefaultRepository) GetWebhook(ctx context.Context, id uuid.UUID) (model.Webhook, error) {
+	const query = `
+		select id, game_id, url, secret, event_types, active, created_at
+		from easy_quizy_webhook
+		where id = $1
+	`
+
+	var result sqlxWebhook
+	if err := r.db(ctx).GetContext(ctx, &result, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Webhook{}, contracts.ErrWebhookNotFound
+		}
+
+		return model.Webhook{}, err
+	}
+
+	return convertToWebhook(result), nil
+}
+
+// GetWebhooks активные вебхуки игры
+func (r *DefaultRepository) GetWebhooks(ctx context.Context, gameID uuid.UUID) ([]model.Webhook, error) {
+	const query = `
+		select id, game_id, url, secret, event_types, active, created_at
+		from easy_quizy_webhook
+		where game_id = $1 and active
+		order by created_at, id
+	`
+
+	var rows []sqlxWebhook
+	if err := r.db(ctx).SelectContext(ctx, &rows, query, gameID); err != nil {
+		return nil, err
+	}
+
+	result := make([]model.Webhook, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, convertToWebhook(row))
+	}
+
+	return result, nil
+}
+
+// DisableWebhook отключает вебхук, журнал его доставок сохраняется
+func (r *DefaultRepository) DisableWebhook(ctx context.Context, id uuid.UUID) error {
+	const query = `
+		update easy_quizy_webhook
+		set active = false
+		where id = $1 and active
+	`
+
+	res, err := r.db(ctx).ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return contracts.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// InsertDeliveries ставит доставки в очередь, повторная обработка того же события outbox дублей не создает
+func (r *DefaultRepository) InsertDeliveries(ctx context.Context, deliveries []model.WebhookDelivery) error {
+	const query = `
+		insert into easy_quizy_webhook_delivery (webhook_id, event_id, event_type, body)
+		values ($1, $2, $3, $4)
+		on conflict (webhook_id, event_id) do nothing
+	`
+
+	for _, delivery := range deliveries {
+		_, err := r.db(ctx).ExecContext(ctx, query,
+			delivery.WebhookID, delivery.EventID, delivery.EventType, []byte(delivery.Body),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ClaimDelivery забирает самую старую готовую доставку и откладывает ее до leaseUntil,
+// чтобы запрос партнеру выполнялся без открытой транзакции и не достался другому экземпляру
+func (r *DefaultRepository) ClaimDelivery(ctx context.Context, now, leaseUntil time.Time) (model.WebhookDelivery, error) {
+	const query = `
+		update easy_quizy_webhook_delivery
+		set next_attempt_at = $2
+		where id = (
+			select id
+			from easy_quizy_webhook_delivery
+			where status = 'pending' and next_attempt_at <= $1
+			order by next_attempt_at, id
+			limit 1
+			for update skip locked
+		)
+		returning ` + deliveryColumns
+
+	var result sqlxDelivery
+	if err := r.db(ctx).GetContext(ctx, &result, query, now, leaseUntil); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.WebhookDelivery{}, contracts.ErrNoPendingDelivery
+		}
+
+		return model.WebhookDelivery{}, err
+	}
+
+	return convertToDelivery(result), nil
+}
+
+func (r *DefaultRepository) MarkDelivered(ctx context.Context, id int64, statusCode int64, deliveredAt time.Time) error {
+	const query = `
+		update easy_quizy_webhook_delivery
+		set status = 'succeeded', attempts = attempts + 1, last_status_code = $2, last_error = null, delivered_at = $3
+		where id = $1
+	`
+
+	_, err := r.db(ctx).ExecContext(ctx, query, id, statusCode, deliveredAt)
+	return err
+}
+
+// ScheduleRetry записывает неудачную попытку и откладывает доставку до nextAttemptAt
+func (r *DefaultRepository) ScheduleRetry(
+	ctx context.Context,
+	id int64,
+	nextAttemptAt time.Time,
+	statusCode *int64,
+	lastError string,
+) error {
+	const query = `
+		update easy_quizy_webhook_delivery
+		set attempts = attempts + 1, next_attempt_at = $2, last_status_code = $3, last_error = $4
+		where id = $1
+	`
+
+	_, err := r.db(ctx).ExecContext(ctx, query, id, nextAttemptAt, statusCode, lastError)
+	return err
+}
+
+// MarkFailed записывает последнюю неудачную попытку и снимает доставку с очереди
+func (r *DefaultRepository) MarkFailed(ctx context.Context, id int64, statusCode *int64, lastError string) error {
+	const query = `
+		update easy_quizy_webhook_delivery
+		set status = 'failed', attempts = attempts + 1, last_status_code = $2, last_error = $3
+		where id = $1
+	`
+
+	_, err := r.db(ctx).ExecContext(ctx, query, id, statusCode, lastError)
+	return err
+}
+
+// ResetDelivery возвращает доставку в очередь с обнуленным счетчиком попыток
+func (r *DefaultRepository) ResetDelivery(ctx context.Context, id int64, now time.Time) (model.WebhookDelivery, error) {
+	const query = `
+		update easy_quizy_webhook_delivery
+		set status = 'pending', attempts = 0, next_attempt_at = $2, delivered_at = null
+		where id = $1
+		returning ` + deliveryColumns
+
+	var result sqlxDelivery
+	if err := r.db(ctx).GetContext(ctx, &result, query, id, now); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.WebhookDelivery{}, contracts.ErrWebhookDeliveryNotFound
+		}
+
+		return model.WebhookDelivery{}, err
+	}
+
+	return convertToDelivery(result), nil
+}
+
+func (r *DefaultRepository) GetDeliveries(
+	ctx context.Context,
+	webhookID uuid.UUID,
+	page model.Page,
+) (model.WebhookDeliveries, error) {
+	const (
+		query = `
+			select ` + deliveryColumns + `
+			from easy_quizy_webhook_delivery
+			where webhook_id = $1
+			order by id desc
+			limit $2 offset $3
+		`
+		countQuery = `
+			select count(*)
+			from easy_quizy_webhook_delivery
+			where webhook_id = $1
+		`
+	)
+
+	var rows []sqlxDelivery
+	if err := r.db(ctx).SelectContext(ctx, &rows, query, webhookID, page.Limit, page.Offset); err != nil {
+		return model.WebhookDeliveries{}, err
+	}
+
+	var total int64
+	if err := r.db(ctx).GetContext(ctx, &total, countQuery, webhookID); err != nil {
+		return model.WebhookDeliveries{}, err
+	}
+
+	items := make([]model.WebhookDelivery, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, convertToDelivery(row))
+	}
+
+	return model.WebhookDeliveries{Items: items, Total: total}, nil
+}
+
+func convertToWebhook(in sqlxWebhook) model.Webhook {
+	eventTypes := make([]model.EventType, 0, len(in.EventTypes))
+	for _, eventType := range in.EventTypes {
+		eventTypes = append(eventTypes, model.EventType(eventType))
+	}
+
+	return model.Webhook{
+		ID:         in.ID,
+		GameID:     in.GameID,
+		URL:        in.URL,
+		Secret:     in.Secret,
+		EventTypes: eventTypes,
+		Active:     in.Active,
+		CreatedAt:  in.CreatedAt,
+	}
+}
+
+func convertToDelivery(in sqlxDelivery) model.WebhookDelivery {
+	return model.WebhookDelivery{
+		ID:             in.ID,
+		WebhookID:      in.WebhookID,
+		EventID:        in.EventID,
+		EventType:      model.EventType(in.EventType),
+		Body:           in.Body,
+		Status:         model.DeliveryStatus(in.Status),
+		Attempts:       in.Attempts,
+		NextAttemptAt:  in.NextAttemptAt,
+		LastStatusCode: in.LastStatusCode,
+		LastError:      in.LastError,
+		CreatedAt:      in.CreatedAt,
+		DeliveredAt:    in.DeliveredAt,
+	}
+}
+
+func eventTypesToStrings(in []model.EventType) []string {
+	result := make([]string, 0, len(in))
+	for _, eventType := range in {
+		result = append(result, string(eventType))
+	}
+
+	return result
+}
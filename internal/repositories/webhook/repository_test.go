@@ -0,0 +1,301 @@
+package webhook
+
+import (
+	"context"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"easy-quizy/internal/pgtest"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+func newTestRepository(t *testing.T) (*DefaultRepository, *sqlx.DB) {
+	t.Helper()
+
+	db := pgtest.Start(t)
+	return NewRepository(db, trmsqlx.DefaultCtxGetter), db
+}
+
+func insertWebhook(t *testing.T, repo *DefaultRepository, gameID uuid.UUID) model.Webhook {
+	t.Helper()
+
+	webhook, err := repo.InsertWebhook(context.Background(), model.Webhook{
+		ID:         uuid.New(),
+		GameID:     gameID,
+		URL:        "https://partner.example/hook",
+		Secret:     "secret",
+		EventTypes: []model.EventType{model.EventGameCompleted},
+	})
+	if err != nil {
+		t.Fatalf("InsertWebhook: %v", err)
+	}
+
+	return webhook
+}
+
+func delivery(webhookID uuid.UUID, eventID int64) model.WebhookDelivery {
+	return model.WebhookDelivery{
+		WebhookID: webhookID,
+		EventID:   eventID,
+		EventType: model.EventGameCompleted,
+		Body:      json.RawMessage(`{"score":3}`),
+	}
+}
+
+func TestWebhooks(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+
+	pgtest.Truncate(t, db)
+	gameID := pgtest.InsertGame(t, db, model.GameTypeClassic, 1)
+	active := insertWebhook(t, repo, gameID)
+	disabled := insertWebhook(t, repo, gameID)
+	if err := repo.DisableWebhook(ctx, disabled.ID); err != nil {
+		t.Fatalf("DisableWebhook: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		check func(t *testing.T)
+	}{
+		{
+			name: "GetWebhook",
+			check: func(t *testing.T) {
+				got, err := repo.GetWebhook(ctx, active.ID)
+				if err != nil {
+					t.Fatalf("GetWebhook: %v", err)
+				}
+				if !got.Active || len(got.EventTypes) != 1 || got.EventTypes[0] != model.EventGameCompleted {
+					t.Errorf("GetWebhook = %+v, want active webhook for %s", got, model.EventGameCompleted)
+				}
+
+				if _, err := repo.GetWebhook(ctx, uuid.New()); !errors.Is(err, contracts.ErrWebhookNotFound) {
+					t.Errorf("GetWebhook of unknown id error = %v, want %v", err, contracts.ErrWebhookNotFound)
+				}
+			},
+		},
+		{
+			name: "GetWebhooks returns active only",
+			check: func(t *testing.T) {
+				got, err := repo.GetWebhooks(ctx, gameID)
+				if err != nil {
+					t.Fatalf("GetWebhooks: %v", err)
+				}
+				if len(got) != 1 || got[0].ID != active.ID {
+					t.Errorf("GetWebhooks = %v, want only %s", got, active.ID)
+				}
+			},
+		},
+		{
+			name: "DisableWebhook twice",
+			check: func(t *testing.T) {
+				if err := repo.DisableWebhook(ctx, disabled.ID); !errors.Is(err, contracts.ErrWebhookNotFound) {
+					t.Errorf("DisableWebhook error = %v, want %v", err, contracts.ErrWebhookNotFound)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, tt.check)
+	}
+}
+
+func TestClaimDelivery(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	// next_attempt_at ставит база, запас в минуту покрывает расхождение часов
+	now := time.Now().Add(time.Minute)
+	lease := now.Add(time.Minute)
+
+	tests := []struct {
+		name        string
+		apply       func(t *testing.T, claimed model.WebhookDelivery)
+		wantEventID int64
+		wantErr     error
+	}{
+		{
+			name:        "leased delivery is not claimed twice",
+			apply:       func(t *testing.T, claimed model.WebhookDelivery) {},
+			wantEventID: 2,
+		},
+		{
+			name: "delivered delivery leaves the queue",
+			apply: func(t *testing.T, claimed model.WebhookDelivery) {
+				if err := repo.MarkDelivered(ctx, claimed.ID, 200, now); err != nil {
+					t.Fatalf("MarkDelivered: %v", err)
+				}
+			},
+			wantEventID: 2,
+		},
+		{
+			name: "retry comes back when due",
+			apply: func(t *testing.T, claimed model.WebhookDelivery) {
+				status := int64(502)
+				if err := repo.ScheduleRetry(ctx, claimed.ID, now.Add(-time.Second), &status, "bad gateway"); err != nil {
+					t.Fatalf("ScheduleRetry: %v", err)
+				}
+			},
+			wantEventID: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pgtest.Truncate(t, db)
+			webhook := insertWebhook(t, repo, pgtest.InsertGame(t, db, model.GameTypeClassic, 1))
+			if err := repo.InsertDeliveries(ctx, []model.WebhookDelivery{delivery(webhook.ID, 1), delivery(webhook.ID, 2)}); err != nil {
+				t.Fatalf("InsertDeliveries: %v", err)
+			}
+
+			claimed, err := repo.ClaimDelivery(ctx, now, lease)
+			if err != nil {
+				t.Fatalf("ClaimDelivery: %v", err)
+			}
+			if claimed.EventID != 1 {
+				t.Fatalf("first claim = event %d, want 1", claimed.EventID)
+			}
+			tt.apply(t, claimed)
+
+			got, err := repo.ClaimDelivery(ctx, now, lease)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ClaimDelivery error = %v, want %v", err, tt.wantErr)
+			}
+			if got.EventID != tt.wantEventID {
+				t.Errorf("second claim = event %d, want %d", got.EventID, tt.wantEventID)
+			}
+		})
+	}
+}
+
+func TestDeliveryStatus(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	now := time.Now().Add(time.Minute)
+	status := int64(500)
+
+	tests := []struct {
+		name         string
+		apply        func(t *testing.T, id int64)
+		wantStatus   model.DeliveryStatus
+		wantAttempts int64
+	}{
+		{
+			name: "delivered",
+			apply: func(t *testing.T, id int64) {
+				if err := repo.MarkDelivered(ctx, id, 204, now); err != nil {
+					t.Fatalf("MarkDelivered: %v", err)
+				}
+			},
+			wantStatus:   model.DeliveryStatusSucceeded,
+			wantAttempts: 1,
+		},
+		{
+			name: "retried then failed",
+			apply: func(t *testing.T, id int64) {
+				if err := repo.ScheduleRetry(ctx, id, now, &status, "internal error"); err != nil {
+					t.Fatalf("ScheduleRetry: %v", err)
+				}
+				if err := repo.MarkFailed(ctx, id, &status, "internal error"); err != nil {
+					t.Fatalf("MarkFailed: %v", err)
+				}
+			},
+			wantStatus:   model.DeliveryStatusFailed,
+			wantAttempts: 2,
+		},
+		{
+			name: "failed then reset",
+			apply: func(t *testing.T, id int64) {
+				if err := repo.MarkFailed(ctx, id, nil, "connection refused"); err != nil {
+					t.Fatalf("MarkFailed: %v", err)
+				}
+				if _, err := repo.ResetDelivery(ctx, id, now); err != nil {
+					t.Fatalf("ResetDelivery: %v", err)
+				}
+			},
+			wantStatus:   model.DeliveryStatusPending,
+			wantAttempts: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pgtest.Truncate(t, db)
+			webhook := insertWebhook(t, repo, pgtest.InsertGame(t, db, model.GameTypeClassic, 1))
+			if err := repo.InsertDeliveries(ctx, []model.WebhookDelivery{delivery(webhook.ID, 1)}); err != nil {
+				t.Fatalf("InsertDeliveries: %v", err)
+			}
+
+			claimed, err := repo.ClaimDelivery(ctx, now, now)
+			if err != nil {
+				t.Fatalf("ClaimDelivery: %v", err)
+			}
+			tt.apply(t, claimed.ID)
+
+			got, err := repo.GetDeliveries(ctx, webhook.ID, model.Page{Limit: 10})
+			if err != nil {
+				t.Fatalf("GetDeliveries: %v", err)
+			}
+			if got.Total != 1 || len(got.Items) != 1 {
+				t.Fatalf("GetDeliveries = %+v, want one delivery", got)
+			}
+			if got.Items[0].Status != tt.wantStatus || got.Items[0].Attempts != tt.wantAttempts {
+				t.Errorf("delivery status %s after %d attempts, want %s after %d",
+					got.Items[0].Status, got.Items[0].Attempts, tt.wantStatus, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestInsertDeliveriesIsIdempotent(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+
+	pgtest.Truncate(t, db)
+	webhook := insertWebhook(t, repo, pgtest.InsertGame(t, db, model.GameTypeClassic, 1))
+
+	tests := []struct {
+		name      string
+		eventIDs  []int64
+		wantTotal int64
+	}{
+		{name: "first event", eventIDs: []int64{1}, wantTotal: 1},
+		{name: "same event again", eventIDs: []int64{1}, wantTotal: 1},
+		{name: "next event", eventIDs: []int64{2}, wantTotal: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deliveries := make([]model.WebhookDelivery, 0, len(tt.eventIDs))
+			for _, eventID := range tt.eventIDs {
+				deliveries = append(deliveries, delivery(webhook.ID, eventID))
+			}
+			if err := repo.InsertDeliveries(ctx, deliveries); err != nil {
+				t.Fatalf("InsertDeliveries: %v", err)
+			}
+
+			got, err := repo.GetDeliveries(ctx, webhook.ID, model.Page{Limit: 10})
+			if err != nil {
+				t.Fatalf("GetDeliveries: %v", err)
+			}
+			if got.Total != tt.wantTotal {
+				t.Errorf("Total = %d, want %d", got.Total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestResetUnknownDelivery(t *testing.T) {
+	repo, db := newTestRepository(t)
+	pgtest.Truncate(t, db)
+
+	if _, err := repo.ResetDelivery(context.Background(), 42, time.Now()); !errors.Is(err, contracts.ErrWebhookDeliveryNotFound) {
+		t.Fatalf("ResetDelivery error = %v, want %v", err, contracts.ErrWebhookDeliveryNotFound)
+	}
+}
@@ -0,0 +1,51 @@
+package account
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	repository interface {
+		InsertLinkCode(ctx context.Context, code model.LinkCode) (bool, error)
+		UseLinkCode(ctx context.Context, code string, usedBy uuid.UUID, now time.Time) (model.LinkCode, error)
+		InsertMerge(ctx context.Context, from uuid.UUID, to uuid.UUID) error
+		GetMergedInto(ctx context.Context, from uuid.UUID) (uuid.UUID, error)
+	}
+
+	userRepository interface {
+		GetUserSources(ctx context.Context, userID uuid.UUID) ([]model.UserSource, error)
+		GetSourcesCreatedBetween(ctx context.Context, from time.Time, to time.Time) ([]model.UserSource, error)
+		GetUserChats(ctx context.Context, userID uuid.UUID) ([]model.UserChat, error)
+		ReassignChats(ctx context.Context, from uuid.UUID, to uuid.UUID) error
+		ReassignSources(ctx context.Context, from uuid.UUID, to uuid.UUID) error
+		MoveSource(ctx context.Context, source model.UserSource, to uuid.UUID) error
+	}
+
+	// userCache кэш пользователей из AuthMiddleware, источники после смены пользователя из него убираются
+	userCache interface {
+		Forget(sources []model.UserSource)
+	}
+
+	gameRepository interface {
+		GetOrphanPlayers(ctx context.Context) ([]model.OrphanPlayer, error)
+		GetLastGameSession(ctx context.Context, gameID uuid.UUID, playerID uuid.UUID) (model.GameSession, error)
+		ReassignSessions(ctx context.Context, from uuid.UUID, to uuid.UUID) (int64, error)
+		MergeSessions(ctx context.Context, from uuid.UUID, to uuid.UUID) (int64, error)
+	}
+
+	ledgerRepository interface {
+		ReassignEntries(ctx context.Context, from uuid.UUID, to uuid.UUID) error
+	}
+
+	achievementRepository interface {
+		ReassignAchievements(ctx context.Context, from uuid.UUID, to uuid.UUID) error
+	}
+
+	flagRepository interface {
+		ReassignFlags(ctx context.Context, from uuid.UUID, to uuid.UUID) error
+	}
+)
@@ -0,0 +1,128 @@
+package account
+
+import (
+	"context"
+	"crypto/rand"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// linkCodeAlphabet без похожих друг на друга символов, код набирают руками
+	linkCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	linkCodeLength   = 8
+	// linkCodeAttempts сколько раз перегенерировать код при совпадении с уже выданным
+	linkCodeAttempts = 3
+)
+
+func (u *Usecase) CreateLinkCode(ctx context.Context, userID uuid.UUID) (model.LinkCode, error) {
+	for range linkCodeAttempts {
+		code, err := generateLinkCode()
+		if err != nil {
+			return model.LinkCode{}, err
+		}
+
+		now := time.Now()
+		linkCode := model.LinkCode{
+			Code:      code,
+			UserID:    userID,
+			CreatedAt: now,
+			ExpiresAt: now.Add(u.cfg.LinkCodeTTL),
+		}
+
+		inserted, err := u.accounts.InsertLinkCode(ctx, linkCode)
+		if err != nil {
+			return model.LinkCode{}, err
+		}
+		if inserted {
+			return linkCode, nil
+		}
+	}
+
+	return model.LinkCode{}, fmt.Errorf("failed to generate unique link code in %d attempts", linkCodeAttempts)
+}
+
+// Link сливает пользователя userID в владельца кода: источники, попытки, чаты, опыт, достижения и пометки
+// переходят к владельцу, в общих играх текущей становится лучшая попытка
+func (u *Usecase) Link(ctx context.Context, userID uuid.UUID, code string) (model.User, error) {
+	var (
+		owner   uuid.UUID
+		sources []model.UserSource
+	)
+	err := u.trm.Do(ctx, func(ctx context.Context) error {
+		linkCode, err := u.accounts.UseLinkCode(ctx, strings.ToUpper(strings.TrimSpace(code)), userID, time.Now())
+		if err != nil {
+			return err
+		}
+
+		// Владелец кода мог сам успеть слиться с кем-то еще
+		owner = linkCode.UserID
+		mergedInto, err := u.accounts.GetMergedInto(ctx, owner)
+		if err != nil && !errors.Is(err, contracts.ErrUserMergeNotFound) {
+			return err
+		}
+		if err == nil {
+			owner = mergedInto
+		}
+
+		if owner == userID {
+			return contracts.ErrAccountsAlreadyLinked
+		}
+
+		if sources, err = u.users.GetUserSources(ctx, userID); err != nil {
+			return err
+		}
+		if _, err = u.games.MergeSessions(ctx, userID, owner); err != nil {
+			return err
+		}
+		if err = u.reassignUserData(ctx, userID, owner); err != nil {
+			return err
+		}
+		if err = u.users.ReassignSources(ctx, userID, owner); err != nil {
+			return err
+		}
+
+		return u.accounts.InsertMerge(ctx, userID, owner)
+	})
+	if err != nil {
+		return model.User{}, err
+	}
+
+	u.cache.Forget(sources)
+	return model.User{ID: owner}, nil
+}
+
+// Unlink отделяет источник target в нового пользователя без данных, отделить источник текущего запроса нельзя
+func (u *Usecase) Unlink(ctx context.Context, userID uuid.UUID, current model.UserSource, target model.UserSource) error {
+	if current.IDext == target.IDext && current.Source == target.Source {
+		return contracts.ErrCannotUnlinkCurrentSource
+	}
+
+	target.User = model.User{ID: userID}
+	if err := u.users.MoveSource(ctx, target, uuid.New()); err != nil {
+		return err
+	}
+
+	u.cache.Forget([]model.UserSource{target})
+	return nil
+}
+
+func generateLinkCode() (string, error) {
+	random := make([]byte, linkCodeLength)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+
+	code := make([]byte, linkCodeLength)
+	for i, value := range random {
+		code[i] = linkCodeAlphabet[int(value)%len(linkCodeAlphabet)]
+	}
+
+	return string(code), nil
+}
@@ -0,0 +1,166 @@
+package account
+
+import (
+	"context"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	accountRepo "easy-quizy/internal/repositories/account"
+	achievementRepo "easy-quizy/internal/repositories/achievement"
+	antiCheatRepo "easy-quizy/internal/repositories/anticheat"
+	gameRepo "easy-quizy/internal/repositories/game"
+	progressionRepo "easy-quizy/internal/repositories/progression"
+	userRepo "easy-quizy/internal/repositories/user"
+	userUC "easy-quizy/internal/usecase/user"
+	"easy-quizy/pkg/transaction"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type testEnv struct {
+	usecase *Usecase
+	users   *userUC.Usecase
+	games   *gameRepo.MemoryRepository
+}
+
+// newTestEnv usecase на репозиториях в памяти с настоящим кэшем пользователей
+func newTestEnv(t *testing.T) testEnv {
+	t.Helper()
+
+	trm := transaction.NewNoopManager()
+	userRepository := userRepo.NewMemoryRepository()
+	users := userUC.NewUsecase(userRepository, trm, userUC.Config{CacheSize: 10, CacheTTL: time.Minute})
+	games := gameRepo.NewMemoryRepository()
+
+	return testEnv{
+		usecase: NewUsecase(
+			accountRepo.NewMemoryRepository(),
+			userRepository,
+			users,
+			games,
+			progressionRepo.NewMemoryRepository(),
+			achievementRepo.NewMemoryRepository(),
+			antiCheatRepo.NewMemoryRepository(),
+			trm,
+			Config{LinkCodeTTL: time.Minute},
+		),
+		users: users,
+		games: games,
+	}
+}
+
+func (e testEnv) retrieve(t *testing.T, idExt string, source string) uuid.UUID {
+	t.Helper()
+
+	user, err := e.users.RetrieveUser(context.Background(), contracts.UserData{UserIDext: idExt, Source: source})
+	if err != nil {
+		t.Fatalf("RetrieveUser(%s, %s): %v", source, idExt, err)
+	}
+
+	return user.ID
+}
+
+// play сохраняет попытку игрока; score nil — попытка не завершена
+func (e testEnv) play(t *testing.T, gameID uuid.UUID, playerID uuid.UUID, attempt int64, startedAt time.Time, score *int64) model.GameSession {
+	t.Helper()
+	ctx := context.Background()
+
+	session, err := e.games.CreateGameSession(ctx, model.GameSession{
+		ID:        uuid.New(),
+		GameID:    gameID,
+		PlayerID:  playerID,
+		Attempt:   attempt,
+		StartedAt: startedAt,
+	})
+	if err != nil {
+		t.Fatalf("CreateGameSession: %v", err)
+	}
+	if score == nil {
+		return session
+	}
+
+	if _, err := e.games.FinishGameSession(ctx, session.ID, startedAt.Add(time.Minute), model.Result{TotalScore: *score}); err != nil {
+		t.Fatalf("FinishGameSession: %v", err)
+	}
+
+	return session
+}
+
+func TestLink(t *testing.T) {
+	low, high := int64(1), int64(2)
+
+	tests := []struct {
+		name            string
+		guestUnfinished bool
+		// wantLast какая попытка гостя окажется последней у владельца
+		wantLast string
+	}{
+		{name: "best finished attempt becomes last", wantLast: "finished"},
+		{name: "unfinished attempt stays last", guestUnfinished: true, wantLast: "unfinished"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			env := newTestEnv(t)
+			now := time.Now()
+			gameID := uuid.New()
+
+			owner := env.retrieve(t, "1", "telegram")
+			guest := env.retrieve(t, "2", "web")
+
+			env.play(t, gameID, owner, 1, now.Add(-3*time.Hour), &low)
+			guestSessions := map[string]model.GameSession{
+				"finished": env.play(t, gameID, guest, 1, now.Add(-2*time.Hour), &high),
+			}
+			if tt.guestUnfinished {
+				guestSessions["unfinished"] = env.play(t, gameID, guest, 2, now.Add(-time.Hour), nil)
+			}
+
+			code, err := env.usecase.CreateLinkCode(ctx, owner)
+			if err != nil {
+				t.Fatalf("CreateLinkCode: %v", err)
+			}
+			linked, err := env.usecase.Link(ctx, guest, code.Code)
+			if err != nil {
+				t.Fatalf("Link: %v", err)
+			}
+			if linked.ID != owner {
+				t.Errorf("Link = %s, want owner %s", linked.ID, owner)
+			}
+
+			// Источник гостя сразу узнается как владелец, старый id не остался в кэше
+			if got := env.retrieve(t, "2", "web"); got != owner {
+				t.Errorf("guest source resolves to %s, want owner %s", got, owner)
+			}
+
+			last, err := env.games.GetLastGameSession(ctx, gameID, owner)
+			if err != nil {
+				t.Fatalf("GetLastGameSession: %v", err)
+			}
+			if want := guestSessions[tt.wantLast]; last.ID != want.ID {
+				t.Errorf("last session = %s #%d, want the %s guest attempt %s", last.ID, last.Attempt, tt.wantLast, want.ID)
+			}
+			if wantAttempt := int64(1 + len(guestSessions)); last.Attempt != wantAttempt {
+				t.Errorf("last attempt = %d, want %d", last.Attempt, wantAttempt)
+			}
+
+			own, err := env.games.CountOwnAttempts(ctx, gameID, owner)
+			if err != nil {
+				t.Fatalf("CountOwnAttempts: %v", err)
+			}
+			if own != 1 {
+				t.Errorf("CountOwnAttempts = %d, want only the owner's attempt", own)
+			}
+
+			if _, err := env.games.GetLastGameSession(ctx, gameID, guest); !errors.Is(err, contracts.ErrSessionNotFound) {
+				t.Errorf("guest sessions error = %v, want %v", err, contracts.ErrSessionNotFound)
+			}
+			if _, err := env.usecase.Link(ctx, guest, code.Code); !errors.Is(err, contracts.ErrLinkCodeNotFound) {
+				t.Errorf("second Link error = %v, want %v", err, contracts.ErrLinkCodeNotFound)
+			}
+		})
+	}
+}
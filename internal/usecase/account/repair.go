@@ -0,0 +1,131 @@
+package account
+
+import (
+	"context"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// orphanOwnerWindow в гонке первых запросов источник владельца и попытка сироты создаются почти одновременно
+const orphanOwnerWindow = time.Minute
+
+func (u *Usecase) RepairOrphans(ctx context.Context, apply bool) ([]model.OrphanRepair, error) {
+	orphans, err := u.games.GetOrphanPlayers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.OrphanRepair, 0, len(orphans))
+	for _, orphan := range orphans {
+		// Слитый при привязке пользователь: попытки записала реплика, до которой еще не дошло уведомление о переносе источника
+		move := u.games.ReassignSessions
+		candidates, err := u.findMergedOwner(ctx, orphan)
+		if err != nil {
+			return nil, err
+		}
+		if len(candidates) > 0 {
+			move = u.games.MergeSessions
+		} else if candidates, err = u.findOwners(ctx, orphan); err != nil {
+			return nil, err
+		}
+
+		repair := model.OrphanRepair{OrphanPlayer: orphan, Candidates: int64(len(candidates))}
+		if len(candidates) == 1 {
+			repair.UserID = &candidates[0]
+			if apply {
+				if repair.Sessions, err = u.reassign(ctx, orphan.PlayerID, candidates[0], move); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		result = append(result, repair)
+	}
+
+	return result, nil
+}
+
+func (u *Usecase) findMergedOwner(ctx context.Context, orphan model.OrphanPlayer) ([]uuid.UUID, error) {
+	owner, err := u.accounts.GetMergedInto(ctx, orphan.PlayerID)
+	if errors.Is(err, contracts.ErrUserMergeNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return []uuid.UUID{owner}, nil
+}
+
+// findOwners пользователи, созданные рядом с первой попыткой сироты. Если их несколько,
+// остаются игравшие в ту же игру, а затем состоящие с сиротой в общем чате
+func (u *Usecase) findOwners(ctx context.Context, orphan model.OrphanPlayer) ([]uuid.UUID, error) {
+	sources, err := u.users.GetSourcesCreatedBetween(
+		ctx,
+		orphan.FirstSeenAt.Add(-orphanOwnerWindow),
+		orphan.FirstSeenAt.Add(orphanOwnerWindow),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []uuid.UUID
+	for _, source := range sources {
+		if !slices.Contains(candidates, source.ID) {
+			candidates = append(candidates, source.ID)
+		}
+	}
+
+	if len(candidates) > 1 {
+		played := make([]uuid.UUID, 0, len(candidates))
+		for _, candidate := range candidates {
+			_, err := u.games.GetLastGameSession(ctx, orphan.FirstGameID, candidate)
+			if errors.Is(err, contracts.ErrSessionNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			played = append(played, candidate)
+		}
+		candidates = played
+	}
+
+	if len(candidates) > 1 {
+		orphanChats, err := u.users.GetUserChats(ctx, orphan.PlayerID)
+		if err != nil {
+			return nil, err
+		}
+		if len(orphanChats) == 0 {
+			return candidates, nil
+		}
+
+		shared := make([]uuid.UUID, 0, len(candidates))
+		for _, candidate := range candidates {
+			chats, err := u.users.GetUserChats(ctx, candidate)
+			if err != nil {
+				return nil, err
+			}
+
+			if sharesChat(orphanChats, chats) {
+				shared = append(shared, candidate)
+			}
+		}
+		candidates = shared
+	}
+
+	return candidates, nil
+}
+
+func sharesChat(left []model.UserChat, right []model.UserChat) bool {
+	return slices.ContainsFunc(left, func(chat model.UserChat) bool {
+		return slices.ContainsFunc(right, func(other model.UserChat) bool {
+			return other.ChatID == chat.ChatID
+		})
+	})
+}
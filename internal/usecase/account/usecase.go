@@ -0,0 +1,85 @@
+package account
+
+import (
+	"context"
+	"time"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+	"github.com/google/uuid"
+)
+
+type (
+	Config struct {
+		// LinkCodeTTL сколько действует код привязки
+		LinkCodeTTL time.Duration
+	}
+
+	Usecase struct {
+		accounts     repository
+		users        userRepository
+		cache        userCache
+		games        gameRepository
+		ledger       ledgerRepository
+		achievements achievementRepository
+		flags        flagRepository
+		trm          trm.Manager
+		cfg          Config
+	}
+)
+
+func NewUsecase(
+	accounts repository,
+	users userRepository,
+	cache userCache,
+	games gameRepository,
+	ledger ledgerRepository,
+	achievements achievementRepository,
+	flags flagRepository,
+	trm trm.Manager,
+	cfg Config,
+) *Usecase {
+	return &Usecase{
+		accounts:     accounts,
+		users:        users,
+		cache:        cache,
+		games:        games,
+		ledger:       ledger,
+		achievements: achievements,
+		flags:        flags,
+		trm:          trm,
+		cfg:          cfg,
+	}
+}
+
+// moveSessions переносит попытки, возвращает их число
+type moveSessions func(ctx context.Context, from uuid.UUID, to uuid.UUID) (int64, error)
+
+// reassign переносит попытки способом move и остальные данные игрока from на игрока to одной транзакцией
+func (u *Usecase) reassign(ctx context.Context, from uuid.UUID, to uuid.UUID, move moveSessions) (int64, error) {
+	var moved int64
+	err := u.trm.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if moved, err = move(ctx, from, to); err != nil {
+			return err
+		}
+
+		return u.reassignUserData(ctx, from, to)
+	})
+
+	return moved, err
+}
+
+// reassignUserData переносит чаты, опыт, достижения и пометки античита, вызывается внутри транзакции
+func (u *Usecase) reassignUserData(ctx context.Context, from uuid.UUID, to uuid.UUID) error {
+	if err := u.users.ReassignChats(ctx, from, to); err != nil {
+		return err
+	}
+	if err := u.ledger.ReassignEntries(ctx, from, to); err != nil {
+		return err
+	}
+	if err := u.achievements.ReassignAchievements(ctx, from, to); err != nil {
+		return err
+	}
+
+	return u.flags.ReassignFlags(ctx, from, to)
+}
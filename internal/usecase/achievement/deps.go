@@ -0,0 +1,27 @@
+package achievement
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	repository interface {
+		InsertAchievement(ctx context.Context, achievement model.Achievement) (model.Achievement, bool, error)
+		GetUserAchievements(ctx context.Context, userID uuid.UUID) ([]model.Achievement, error)
+		GetSessionAchievements(ctx context.Context, sessionID uuid.UUID) ([]model.Achievement, error)
+	}
+
+	gameRepository interface {
+		CountCorrectAnswers(ctx context.Context, playerID uuid.UUID) (int64, error)
+		GetDailyCompletionDays(ctx context.Context, playerID uuid.UUID, since time.Time) ([]time.Time, error)
+		CountCompletions(ctx context.Context, gameIDs []uuid.UUID, playerIDs []uuid.UUID) (map[uuid.UUID]int64, error)
+	}
+
+	userRepository interface {
+		GetChatMates(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+	}
+)
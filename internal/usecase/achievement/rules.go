@@ -0,0 +1,121 @@
+package achievement
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	dailyStreakDays        = 10
+	correctAnswersToUnlock = 100
+)
+
+type (
+	// rule условие достижения, проверяется только для событий из triggers
+	rule struct {
+		definition model.AchievementDefinition
+		triggers   []model.PlayTriggerType
+		check      func(ctx context.Context, u *Usecase, trigger model.PlayTrigger) (bool, error)
+	}
+)
+
+func defaultRules() []rule {
+	return []rule{
+		{
+			definition: model.AchievementDefinition{
+				Code:        model.AchievementPerfectScore,
+				Title:       "Без единой ошибки",
+				Description: "Ответить правильно на все вопросы квиза",
+			},
+			triggers: []model.PlayTriggerType{model.TriggerGameCompleted},
+			check:    checkPerfectScore,
+		},
+		{
+			definition: model.AchievementDefinition{
+				Code:        model.AchievementDailyStreak,
+				Title:       "Десять дней подряд",
+				Description: "Пройти ежедневный квиз 10 дней подряд",
+			},
+			triggers: []model.PlayTriggerType{model.TriggerGameCompleted},
+			check:    checkDailyStreak,
+		},
+		{
+			definition: model.AchievementDefinition{
+				Code:        model.AchievementCorrectAnswers,
+				Title:       "Сотня",
+				Description: "Дать 100 правильных ответов",
+			},
+			triggers: []model.PlayTriggerType{model.TriggerAnswerAccepted},
+			check:    checkCorrectAnswers,
+		},
+		{
+			definition: model.AchievementDefinition{
+				Code:        model.AchievementFirstInChat,
+				Title:       "Первый в чате",
+				Description: "Пройти квиз раньше всех участников своего чата",
+			},
+			triggers: []model.PlayTriggerType{model.TriggerGameCompleted},
+			check:    checkFirstInChat,
+		},
+	}
+}
+
+func (r rule) handles(triggerType model.PlayTriggerType) bool {
+	return slices.Contains(r.triggers, triggerType)
+}
+
+func checkPerfectScore(_ context.Context, _ *Usecase, trigger model.PlayTrigger) (bool, error) {
+	questions := int64(len(trigger.Game.Questions))
+	return questions > 0 && trigger.Session.Score != nil && *trigger.Session.Score == questions, nil
+}
+
+// checkDailyStreak считает подряд идущие дни с завершенным ежедневным квизом, заканчивая днем завершения
+func checkDailyStreak(ctx context.Context, u *Usecase, trigger model.PlayTrigger) (bool, error) {
+	if trigger.Game.Type != model.GameTypeDaily || trigger.Session.FinishedAt == nil {
+		return false, nil
+	}
+
+	lastDay := trigger.Session.FinishedAt.UTC().Truncate(24 * time.Hour)
+	days, err := u.games.GetDailyCompletionDays(ctx, trigger.Session.PlayerID, lastDay.AddDate(0, 0, -(dailyStreakDays-1)))
+	if err != nil {
+		return false, err
+	}
+
+	return model.DailyStreak(days, lastDay) >= dailyStreakDays, nil
+}
+
+func checkCorrectAnswers(ctx context.Context, u *Usecase, trigger model.PlayTrigger) (bool, error) {
+	if trigger.Answer == nil || !trigger.Answer.IsCorrect {
+		return false, nil
+	}
+
+	correct, err := u.games.CountCorrectAnswers(ctx, trigger.Session.PlayerID)
+	if err != nil {
+		return false, err
+	}
+
+	return correct >= correctAnswersToUnlock, nil
+}
+
+// checkFirstInChat засчитывается, если никто из участников общих чатов еще не завершал эту игру.
+// Одновременно завершившие игру соседи по чату могут получить достижение оба.
+func checkFirstInChat(ctx context.Context, u *Usecase, trigger model.PlayTrigger) (bool, error) {
+	mates, err := u.users.GetChatMates(ctx, trigger.Session.PlayerID)
+	if err != nil {
+		return false, err
+	}
+	if len(mates) == 0 {
+		return false, nil
+	}
+
+	completions, err := u.games.CountCompletions(ctx, []uuid.UUID{trigger.Game.ID}, mates)
+	if err != nil {
+		return false, err
+	}
+
+	return completions[trigger.Game.ID] == 0, nil
+}
@@ -0,0 +1,136 @@
+package achievement
+
+import (
+	"context"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"time"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+	"github.com/google/uuid"
+)
+
+type (
+	Usecase struct {
+		achievements repository
+		games        gameRepository
+		users        userRepository
+		trm          trm.Manager
+
+		rules []rule
+	}
+)
+
+func NewUsecase(
+	achievements repository,
+	games gameRepository,
+	users userRepository,
+	trm trm.Manager,
+) contracts.AchievementUsecase {
+	return &Usecase{
+		achievements: achievements,
+		games:        games,
+		users:        users,
+		trm:          trm,
+		rules:        defaultRules(),
+	}
+}
+
+func (u *Usecase) GetAchievements(ctx context.Context, userID uuid.UUID) ([]model.AchievementStatus, error) {
+	unlocked, err := u.achievements.GetUserAchievements(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	unlockedAt := make(map[model.AchievementCode]time.Time, len(unlocked))
+	for _, achievement := range unlocked {
+		unlockedAt[achievement.Code] = achievement.UnlockedAt
+	}
+
+	result := make([]model.AchievementStatus, 0, len(u.rules))
+	for _, r := range u.rules {
+		status := model.AchievementStatus{AchievementDefinition: r.definition}
+		if at, ok := unlockedAt[r.definition.Code]; ok {
+			status.UnlockedAt = &at
+		}
+
+		result = append(result, status)
+	}
+
+	return result, nil
+}
+
+func (u *Usecase) Evaluate(ctx context.Context, trigger model.PlayTrigger) ([]model.Achievement, error) {
+	var result []model.Achievement
+	return result, u.trm.Do(ctx, func(ctx context.Context) error {
+		unlocked, err := u.achievements.GetUserAchievements(ctx, trigger.Session.PlayerID)
+		if err != nil {
+			return err
+		}
+
+		owned := make(map[model.AchievementCode]struct{}, len(unlocked))
+		for _, achievement := range unlocked {
+			owned[achievement.Code] = struct{}{}
+		}
+
+		for _, r := range u.rules {
+			if _, ok := owned[r.definition.Code]; ok || !r.handles(trigger.Type) {
+				continue
+			}
+
+			passed, err := r.check(ctx, u, trigger)
+			if err != nil {
+				return err
+			}
+			if !passed {
+				continue
+			}
+
+			achievement, inserted, err := u.achievements.InsertAchievement(ctx, model.Achievement{
+				AchievementDefinition: r.definition,
+				UserID:                trigger.Session.PlayerID,
+				GameID:                &trigger.Game.ID,
+				SessionID:             &trigger.Session.ID,
+				UnlockedAt:            time.Now(),
+			})
+			if err != nil {
+				return err
+			}
+
+			// Параллельный запрос уже выдал это достижение и сам сообщит о нем
+			if !inserted {
+				continue
+			}
+
+			achievement.AchievementDefinition = r.definition
+			result = append(result, achievement)
+		}
+
+		return nil
+	})
+}
+
+func (u *Usecase) GetSessionAchievements(ctx context.Context, sessionID uuid.UUID) ([]model.Achievement, error) {
+	achievements, err := u.achievements.GetSessionAchievements(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.describe(achievements), nil
+}
+
+// describe подставляет тексты из справочника правил, достижения неизвестных кодов отбрасываются
+func (u *Usecase) describe(achievements []model.Achievement) []model.Achievement {
+	result := make([]model.Achievement, 0, len(achievements))
+	for _, achievement := range achievements {
+		for _, r := range u.rules {
+			if r.definition.Code == achievement.Code {
+				achievement.AchievementDefinition = r.definition
+				result = append(result, achievement)
+				break
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,19 @@
+package analytics
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	repository interface {
+		GetGamesByIDs(ctx context.Context, ids []uuid.UUID) ([]model.Game, error)
+		RefreshQuestionStats(ctx context.Context, abandonedBefore time.Time, computedAt time.Time) error
+		GetQuestionStats(ctx context.Context, gameID uuid.UUID) ([]model.QuestionStatsRow, error)
+		RefreshFunnel(ctx context.Context, from time.Time, computedAt time.Time) error
+		GetFunnel(ctx context.Context, filter model.FunnelFilter) ([]model.FunnelDay, error)
+	}
+)
@@ -0,0 +1,65 @@
+package analytics
+
+import (
+	"context"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const day = 24 * time.Hour
+
+func (u *Usecase) RefreshFunnel(ctx context.Context) error {
+	return u.RebuildFunnel(ctx, time.Now().Add(-u.funnelLookback))
+}
+
+func (u *Usecase) RebuildFunnel(ctx context.Context, from time.Time) error {
+	now := time.Now()
+	return u.trm.Do(ctx, func(ctx context.Context) error {
+		return u.repo.RefreshFunnel(ctx, from.UTC().Truncate(day), now)
+	})
+}
+
+// GetFunnel возвращает непрерывный ряд по дням, дни без событий заполняются нулями
+func (u *Usecase) GetFunnel(ctx context.Context, filter model.FunnelFilter) (model.Funnel, error) {
+	filter.From = filter.From.UTC().Truncate(day)
+	filter.To = filter.To.UTC().Truncate(day)
+	if filter.To.Before(filter.From) || filter.To.Sub(filter.From) >= model.MaxFunnelDays*day {
+		return model.Funnel{}, contracts.ErrInvalidFunnelRange
+	}
+
+	if filter.GameID != nil {
+		games, err := u.repo.GetGamesByIDs(ctx, []uuid.UUID{*filter.GameID})
+		if err != nil {
+			return model.Funnel{}, err
+		}
+		if len(games) == 0 {
+			return model.Funnel{}, contracts.ErrGameNotFound
+		}
+	}
+
+	days, err := u.repo.GetFunnel(ctx, filter)
+	if err != nil {
+		return model.Funnel{}, err
+	}
+
+	byDay := make(map[time.Time]model.FunnelDay, len(days))
+	for _, item := range days {
+		byDay[item.Day.UTC().Truncate(day)] = item
+	}
+
+	result := model.Funnel{
+		GameID: filter.GameID,
+		From:   filter.From,
+		To:     filter.To,
+	}
+	for current := filter.From; !current.After(filter.To); current = current.Add(day) {
+		item := byDay[current]
+		item.Day = current
+		result.Days = append(result.Days, item)
+	}
+
+	return result, nil
+}
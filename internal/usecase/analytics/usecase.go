@@ -0,0 +1,115 @@
+package analytics
+
+import (
+	"context"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"time"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+	"github.com/google/uuid"
+)
+
+type (
+	Usecase struct {
+		repo repository
+		trm  trm.Manager
+
+		// abandonAfter через сколько без активности незавершенная попытка считается брошенной
+		abandonAfter time.Duration
+		// funnelLookback за какой период задача пересобирает воронку, с запасом на поздние события
+		funnelLookback time.Duration
+	}
+)
+
+func NewUsecase(
+	repo repository,
+	trm trm.Manager,
+	abandonAfter time.Duration,
+	funnelLookback time.Duration,
+) contracts.AnalyticsUsecase {
+	return &Usecase{
+		repo:           repo,
+		trm:            trm,
+		abandonAfter:   abandonAfter,
+		funnelLookback: funnelLookback,
+	}
+}
+
+func (u *Usecase) RefreshQuestionStats(ctx context.Context) error {
+	now := time.Now()
+	return u.trm.Do(ctx, func(ctx context.Context) error {
+		return u.repo.RefreshQuestionStats(ctx, now.Add(-u.abandonAfter), now)
+	})
+}
+
+// GetQuestionStats дополняет сохраненную статистику текстами вопросов и вариантов,
+// вопросы без ответов тоже попадают в отчет
+func (u *Usecase) GetQuestionStats(ctx context.Context, gameID uuid.UUID) (model.GameQuestionStats, error) {
+	games, err := u.repo.GetGamesByIDs(ctx, []uuid.UUID{gameID})
+	if err != nil {
+		return model.GameQuestionStats{}, err
+	}
+	if len(games) == 0 {
+		return model.GameQuestionStats{}, contracts.ErrGameNotFound
+	}
+	game := games[0]
+
+	rows, err := u.repo.GetQuestionStats(ctx, gameID)
+	if err != nil {
+		return model.GameQuestionStats{}, err
+	}
+
+	byQuestion := make(map[int64]model.QuestionStatsRow, len(rows))
+	for _, row := range rows {
+		byQuestion[row.QuestionID] = row
+	}
+
+	result := model.GameQuestionStats{
+		GameID:    game.ID,
+		Title:     game.Title,
+		Questions: make([]model.QuestionStats, 0, len(game.Questions)),
+	}
+	if len(rows) > 0 {
+		result.ComputedAt = &rows[0].ComputedAt
+	}
+
+	for _, question := range game.Questions {
+		row := byQuestion[question.ID]
+		stats := model.QuestionStats{
+			QuestionID:       question.ID,
+			Text:             question.Text,
+			Options:          make([]model.OptionStats, 0, len(question.AnswerOptions)),
+			Answers:          row.Answers,
+			Correct:          row.Correct,
+			CorrectRate:      ratio(row.Correct, row.Answers),
+			MedianAnswerTime: row.MedianAnswerTime,
+			Reached:          row.Reached,
+			Dropped:          row.Dropped,
+			DropOffRate:      ratio(row.Dropped, row.Reached),
+		}
+
+		for _, option := range question.AnswerOptions {
+			count := row.OptionCounts[option.ID]
+			stats.Options = append(stats.Options, model.OptionStats{
+				OptionID:  option.ID,
+				Text:      option.Answer,
+				IsCorrect: option.IsCorrect,
+				Count:     count,
+				Share:     ratio(count, row.Answers),
+			})
+		}
+
+		result.Questions = append(result.Questions, stats)
+	}
+
+	return result, nil
+}
+
+func ratio(part int64, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return float64(part) / float64(total)
+}
@@ -0,0 +1,28 @@
+package anticheat
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	repository interface {
+		InsertFlags(ctx context.Context, flags []model.SessionFlag) ([]model.SessionFlag, error)
+		GetFlags(ctx context.Context, page model.Page) (model.SessionFlags, error)
+	}
+
+	gameRepository interface {
+		GetGamesByIDs(ctx context.Context, ids []uuid.UUID) ([]model.Game, error)
+		GetGameSession(ctx context.Context, sessionID uuid.UUID) (model.GameSession, error)
+		CountPlayerAnswers(ctx context.Context, playerID uuid.UUID, since time.Time) (int64, error)
+		CountGameSessions(ctx context.Context, gameID uuid.UUID, playerID uuid.UUID, since time.Time) (int64, error)
+		GetFinishedSessions(ctx context.Context, gameID uuid.UUID, playerIDs []uuid.UUID, since time.Time) ([]model.GameSession, error)
+	}
+
+	userRepository interface {
+		GetChatMates(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+	}
+)
@@ -0,0 +1,265 @@
+package anticheat
+
+import (
+	"context"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"fmt"
+	"time"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+	"github.com/google/uuid"
+)
+
+type (
+	Config struct {
+		// RateWindow и MaxAnswersPerWindow больше ответов за окно во всех играх вручную не дать
+		RateWindow          time.Duration
+		MaxAnswersPerWindow int64
+		// MinAnswerTime быстрее нельзя прочитать вопрос и ответить
+		MinAnswerTime time.Duration
+		// ResetWindow и MaxResets идеальная попытка после стольких попыток той же игры за окно
+		ResetWindow time.Duration
+		MaxResets   int64
+		// SharedWindow и MinSharedAnswers столько одинаковых неправильных ответов с соседом по чату,
+		// завершившим игру за окно до этого; совпадение правильных ответов само по себе ничего не значит
+		SharedWindow     time.Duration
+		MinSharedAnswers int64
+	}
+
+	Usecase struct {
+		flags repository
+		games gameRepository
+		users userRepository
+		trm   trm.Manager
+		cfg   Config
+	}
+)
+
+func NewUsecase(
+	flags repository,
+	games gameRepository,
+	users userRepository,
+	trm trm.Manager,
+	cfg Config,
+) contracts.AntiCheatUsecase {
+	return &Usecase{
+		flags: flags,
+		games: games,
+		users: users,
+		trm:   trm,
+		cfg:   cfg,
+	}
+}
+
+func (u *Usecase) HandleEvent(ctx context.Context, event model.Event) error {
+	return u.trm.Do(ctx, func(ctx context.Context) error {
+		var (
+			flags []model.SessionFlag
+			err   error
+		)
+
+		switch event.Type {
+		case model.EventAnswerAccepted:
+			var payload model.AnswerAcceptedPayload
+			if err := event.Decode(&payload); err != nil {
+				return fmt.Errorf("decode %s payload: %w", event.Type, err)
+			}
+
+			flags, err = u.checkAnswer(ctx, payload)
+		case model.EventGameCompleted:
+			var payload model.GameCompletedPayload
+			if err := event.Decode(&payload); err != nil {
+				return fmt.Errorf("decode %s payload: %w", event.Type, err)
+			}
+
+			flags, err = u.checkCompletion(ctx, payload)
+		default:
+			return nil
+		}
+		if err != nil || len(flags) == 0 {
+			return err
+		}
+
+		_, err = u.flags.InsertFlags(ctx, flags)
+		return err
+	})
+}
+
+func (u *Usecase) GetFlags(ctx context.Context, page model.Page) (model.SessionFlags, error) {
+	return u.flags.GetFlags(ctx, page)
+}
+
+// checkAnswer частота ответов игрока и время на ответ
+func (u *Usecase) checkAnswer(ctx context.Context, payload model.AnswerAcceptedPayload) ([]model.SessionFlag, error) {
+	newFlag := func(reason model.CheatReason, details string) model.SessionFlag {
+		return model.SessionFlag{
+			SessionID: payload.SessionID,
+			UserID:    payload.PlayerID,
+			GameID:    payload.GameID,
+			Reason:    reason,
+			Details:   details,
+		}
+	}
+
+	var result []model.SessionFlag
+	if u.cfg.MaxAnswersPerWindow > 0 {
+		answers, err := u.games.CountPlayerAnswers(ctx, payload.PlayerID, payload.AnsweredAt.Add(-u.cfg.RateWindow))
+		if err != nil {
+			return nil, err
+		}
+		if answers > u.cfg.MaxAnswersPerWindow {
+			result = append(result, newFlag(model.CheatAnswerRate,
+				fmt.Sprintf("%d answers within %s", answers, u.cfg.RateWindow)))
+		}
+	}
+
+	if u.cfg.MinAnswerTime > 0 {
+		session, err := u.games.GetGameSession(ctx, payload.SessionID)
+		if err != nil {
+			return nil, err
+		}
+
+		// Если попытку начал сам ответ, отсчитывать время не от чего
+		from := lastActivity(session, payload.AnsweredAt)
+		if elapsed := payload.AnsweredAt.Sub(from); from.Before(payload.AnsweredAt) && elapsed < u.cfg.MinAnswerTime {
+			result = append(result, newFlag(model.CheatFastAnswer,
+				fmt.Sprintf("question %d answered in %s", payload.QuestionID, elapsed)))
+		}
+	}
+
+	return result, nil
+}
+
+// checkCompletion идеальная попытка после серии сбросов и совпадение ответов с соседями по чату
+func (u *Usecase) checkCompletion(ctx context.Context, payload model.GameCompletedPayload) ([]model.SessionFlag, error) {
+	games, err := u.games.GetGamesByIDs(ctx, []uuid.UUID{payload.GameID})
+	if err != nil {
+		return nil, err
+	}
+	if len(games) == 0 {
+		return nil, contracts.ErrGameNotFound
+	}
+
+	newFlag := func(reason model.CheatReason, details string) model.SessionFlag {
+		return model.SessionFlag{
+			SessionID: payload.SessionID,
+			UserID:    payload.PlayerID,
+			GameID:    payload.GameID,
+			Reason:    reason,
+			Details:   details,
+		}
+	}
+
+	var result []model.SessionFlag
+	perfect := payload.Score == int64(len(games[0].Questions))
+	if perfect && u.cfg.MaxResets > 0 {
+		attempts, err := u.games.CountGameSessions(ctx, payload.GameID, payload.PlayerID, payload.FinishedAt.Add(-u.cfg.ResetWindow))
+		if err != nil {
+			return nil, err
+		}
+
+		// Текущая попытка тоже попадает в окно
+		if resets := attempts - 1; resets >= u.cfg.MaxResets {
+			result = append(result, newFlag(model.CheatResetThenPerfect,
+				fmt.Sprintf("perfect score after %d attempts within %s", resets, u.cfg.ResetWindow)))
+		}
+	}
+
+	if u.cfg.MinSharedAnswers > 0 {
+		match, err := u.findSharedAnswers(ctx, payload)
+		if err != nil {
+			return nil, err
+		}
+		if match != nil {
+			result = append(result, newFlag(model.CheatSharedAnswers,
+				fmt.Sprintf("%d identical wrong answers as session %s of player %s", match.shared, match.session.ID, match.session.PlayerID)))
+		}
+	}
+
+	return result, nil
+}
+
+// sharedMatch попытка соседа по чату и сколько неправильных ответов в ней совпало
+type sharedMatch struct {
+	session model.GameSession
+	shared  int64
+}
+
+// findSharedAnswers ищет попытку соседа по чату, завершенную раньше текущей, с теми же неправильными ответами.
+// Правильные ответы у честных игроков совпадают сами, поэтому две независимые идеальные попытки не помечаются.
+func (u *Usecase) findSharedAnswers(ctx context.Context, payload model.GameCompletedPayload) (*sharedMatch, error) {
+	session, err := u.games.GetGameSession(ctx, payload.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if wrongAnswers(session) < u.cfg.MinSharedAnswers {
+		return nil, nil
+	}
+
+	mates, err := u.users.GetChatMates(ctx, payload.PlayerID)
+	if err != nil || len(mates) == 0 {
+		return nil, err
+	}
+
+	candidates, err := u.games.GetFinishedSessions(ctx, payload.GameID, mates, payload.FinishedAt.Add(-u.cfg.SharedWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, candidate := range candidates {
+		if candidate.FinishedAt.After(payload.FinishedAt) {
+			continue
+		}
+		if shared := sharedWrongAnswers(session, candidate); shared >= u.cfg.MinSharedAnswers {
+			return &sharedMatch{session: candidate, shared: shared}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// lastActivity когда игрок в последний раз действовал в попытке до ответа: начало попытки, ответ или подсказка
+func lastActivity(session model.GameSession, answeredAt time.Time) time.Time {
+	result := session.StartedAt
+	for _, answer := range session.Answers {
+		if answer.CreatedAt.Before(answeredAt) && answer.CreatedAt.After(result) {
+			result = answer.CreatedAt
+		}
+	}
+	for _, usage := range session.Lifelines {
+		if usage.CreatedAt.Before(answeredAt) && usage.CreatedAt.After(result) {
+			result = usage.CreatedAt
+		}
+	}
+
+	return result
+}
+
+func wrongAnswers(session model.GameSession) int64 {
+	var result int64
+	for _, answer := range session.Answers {
+		if !answer.IsCorrect {
+			result++
+		}
+	}
+
+	return result
+}
+
+// sharedWrongAnswers сколько вопросов обе попытки ответили одним и тем же неправильным вариантом
+func sharedWrongAnswers(a model.GameSession, b model.GameSession) int64 {
+	var result int64
+	for _, answer := range a.Answers {
+		if answer.IsCorrect {
+			continue
+		}
+
+		other, ok := b.IsAnswered(answer.QuestionID)
+		if ok && other.AnswerID == answer.AnswerID {
+			result++
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,125 @@
+package anticheat
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	anticheatRepo "easy-quizy/internal/repositories/anticheat"
+	gameRepo "easy-quizy/internal/repositories/game"
+	userRepo "easy-quizy/internal/repositories/user"
+	"easy-quizy/pkg/transaction"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// questions в тестовой игре; правильный вариант — 0, остальные неправильные
+const questions = 5
+
+// finishSession сохраняет завершенную попытку игрока с ответами answers по порядку вопросов
+func finishSession(t *testing.T, games *gameRepo.MemoryRepository, gameID uuid.UUID, playerID uuid.UUID, answers []int64, finishedAt time.Time) model.GameCompletedPayload {
+	t.Helper()
+	ctx := context.Background()
+
+	session, err := games.CreateGameSession(ctx, model.GameSession{
+		ID:        uuid.New(),
+		GameID:    gameID,
+		PlayerID:  playerID,
+		Attempt:   1,
+		StartedAt: finishedAt.Add(-time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("CreateGameSession: %v", err)
+	}
+
+	var score int64
+	for i, answerID := range answers {
+		answer := model.GameSessionAnswer{QuestionID: int64(i), AnswerID: answerID, IsCorrect: answerID == 0, CreatedAt: finishedAt}
+		if _, _, err := games.InsertGameSessionAnswer(ctx, session, answer); err != nil {
+			t.Fatalf("InsertGameSessionAnswer: %v", err)
+		}
+		if answer.IsCorrect {
+			score++
+		}
+	}
+
+	if _, err := games.FinishGameSession(ctx, session.ID, finishedAt, model.Result{TotalScore: score}); err != nil {
+		t.Fatalf("FinishGameSession: %v", err)
+	}
+
+	return model.GameCompletedPayload{
+		GameID:     gameID,
+		PlayerID:   playerID,
+		SessionID:  session.ID,
+		Attempt:    session.Attempt,
+		Score:      score,
+		StartedAt:  session.StartedAt,
+		FinishedAt: finishedAt,
+	}
+}
+
+func TestSharedAnswers(t *testing.T) {
+	tests := []struct {
+		name     string
+		first    []int64
+		second   []int64
+		wantFlag bool
+	}{
+		{name: "independent perfect runs", first: []int64{0, 0, 0, 0, 0}, second: []int64{0, 0, 0, 0, 0}},
+		{name: "same wrong answers", first: []int64{1, 2, 0, 3, 0}, second: []int64{1, 2, 0, 3, 0}, wantFlag: true},
+		{name: "too few same wrong answers", first: []int64{1, 2, 0, 0, 0}, second: []int64{1, 2, 0, 0, 0}},
+		{name: "different wrong answers", first: []int64{1, 2, 3, 1, 0}, second: []int64{2, 1, 1, 3, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			flags := anticheatRepo.NewMemoryRepository()
+			games := gameRepo.NewMemoryRepository()
+			users := userRepo.NewMemoryRepository()
+			usecase := NewUsecase(flags, games, users, transaction.NewNoopManager(), Config{
+				SharedWindow:     10 * time.Minute,
+				MinSharedAnswers: 3,
+			})
+
+			game := model.Game{ID: uuid.New(), Type: model.GameTypeClassic, Title: "quiz"}
+			for i := range questions {
+				game.Questions = append(game.Questions, model.Question{ID: int64(i)})
+			}
+			games.AddGame(game)
+
+			first, second := uuid.New(), uuid.New()
+			for _, playerID := range []uuid.UUID{first, second} {
+				if err := users.InsertUserChat(ctx, model.UserChat{User: model.User{ID: playerID}, ChatID: 42}); err != nil {
+					t.Fatalf("InsertUserChat: %v", err)
+				}
+			}
+
+			now := time.Now()
+			finishSession(t, games, game.ID, first, tt.first, now.Add(-time.Minute))
+			payload := finishSession(t, games, game.ID, second, tt.second, now)
+
+			event, err := model.NewEvent(model.EventGameCompleted, payload)
+			if err != nil {
+				t.Fatalf("NewEvent: %v", err)
+			}
+			if err := usecase.HandleEvent(ctx, event); err != nil {
+				t.Fatalf("HandleEvent: %v", err)
+			}
+
+			got, err := usecase.GetFlags(ctx, model.Page{Limit: 10})
+			if err != nil {
+				t.Fatalf("GetFlags: %v", err)
+			}
+			var flagged bool
+			for _, flag := range got.Items {
+				if flag.Reason == model.CheatSharedAnswers && flag.SessionID == payload.SessionID {
+					flagged = true
+				}
+			}
+			if flagged != tt.wantFlag {
+				t.Errorf("flagged = %v, want %v (flags %+v)", flagged, tt.wantFlag, got.Items)
+			}
+		})
+	}
+}
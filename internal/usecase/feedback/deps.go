@@ -0,0 +1,22 @@
+package feedback
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+
+	"github.com/google/uuid"
+)
+
+type (
+	repository interface {
+		UpsertGameRating(ctx context.Context, rating model.GameRating) (model.GameRating, error)
+		UpsertQuestionFlag(ctx context.Context, flag model.QuestionFlag) (model.QuestionFlag, error)
+		GetFlaggedQuestions(ctx context.Context, filter model.FlaggedQuestionsFilter) (model.FlaggedQuestions, error)
+		GetRatingSummaries(ctx context.Context, page model.Page) (model.GameRatingSummaries, error)
+	}
+
+	gameRepository interface {
+		GetGamesByIDs(ctx context.Context, ids []uuid.UUID) ([]model.Game, error)
+		HasFinishedSession(ctx context.Context, gameID uuid.UUID, playerID uuid.UUID) (bool, error)
+	}
+)
@@ -0,0 +1,196 @@
+package feedback
+
+import (
+	"context"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+	"github.com/google/uuid"
+)
+
+// maxCommentLength ограничение длины комментария к жалобе в символах
+const maxCommentLength = 1000
+
+type (
+	Usecase struct {
+		feedback repository
+		games    gameRepository
+		trm      trm.Manager
+	}
+)
+
+func NewUsecase(
+	feedback repository,
+	games gameRepository,
+	trm trm.Manager,
+) contracts.FeedbackUsecase {
+	return &Usecase{
+		feedback: feedback,
+		games:    games,
+		trm:      trm,
+	}
+}
+
+func (u *Usecase) RateGame(ctx context.Context, in contracts.RateGameIn) (model.GameRating, error) {
+	if in.Rating < model.MinGameRating || in.Rating > model.MaxGameRating {
+		return model.GameRating{}, contracts.ErrInvalidRating
+	}
+
+	var result model.GameRating
+	return result, u.trm.Do(ctx, func(ctx context.Context) error {
+		if _, err := u.getCompletedGame(ctx, in.GameID, in.PlayerID); err != nil {
+			return err
+		}
+
+		var err error
+		result, err = u.feedback.UpsertGameRating(ctx, model.GameRating{
+			GameID:   in.GameID,
+			PlayerID: in.PlayerID,
+			Rating:   in.Rating,
+		})
+		return err
+	})
+}
+
+func (u *Usecase) FlagQuestion(ctx context.Context, in contracts.FlagQuestionIn) (model.QuestionFlag, error) {
+	if !in.Reason.IsValid() {
+		return model.QuestionFlag{}, contracts.ErrInvalidFlagReason
+	}
+
+	// Пустой комментарий не храним
+	if in.Comment != nil {
+		comment := strings.TrimSpace(*in.Comment)
+		in.Comment = &comment
+		if comment == "" {
+			in.Comment = nil
+		} else if utf8.RuneCountInString(comment) > maxCommentLength {
+			return model.QuestionFlag{}, contracts.ErrFlagCommentTooLong
+		}
+	}
+
+	var result model.QuestionFlag
+	return result, u.trm.Do(ctx, func(ctx context.Context) error {
+		game, err := u.getCompletedGame(ctx, in.GameID, in.PlayerID)
+		if err != nil {
+			return err
+		}
+
+		if findQuestion(game, in.QuestionID) == nil {
+			return contracts.ErrQuestionNotFound
+		}
+
+		result, err = u.feedback.UpsertQuestionFlag(ctx, model.QuestionFlag{
+			GameID:     in.GameID,
+			PlayerID:   in.PlayerID,
+			QuestionID: in.QuestionID,
+			Reason:     in.Reason,
+			Comment:    in.Comment,
+		})
+		return err
+	})
+}
+
+func (u *Usecase) GetFlaggedQuestions(ctx context.Context, filter model.FlaggedQuestionsFilter) (model.FlaggedQuestions, error) {
+	filter.Page = filter.Page.Normalize()
+
+	result, err := u.feedback.GetFlaggedQuestions(ctx, filter)
+	if err != nil {
+		return model.FlaggedQuestions{}, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(result.Items))
+	for _, item := range result.Items {
+		ids = append(ids, item.GameID)
+	}
+
+	games, err := u.getGames(ctx, ids)
+	if err != nil {
+		return model.FlaggedQuestions{}, err
+	}
+
+	for i, item := range result.Items {
+		game := games[item.GameID]
+		result.Items[i].GameTitle = game.Title
+		if question := findQuestion(game, item.QuestionID); question != nil {
+			result.Items[i].QuestionText = question.Text
+		}
+	}
+
+	return result, nil
+}
+
+func (u *Usecase) GetRatings(ctx context.Context, page model.Page) (model.GameRatingSummaries, error) {
+	result, err := u.feedback.GetRatingSummaries(ctx, page.Normalize())
+	if err != nil {
+		return model.GameRatingSummaries{}, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(result.Items))
+	for _, item := range result.Items {
+		ids = append(ids, item.GameID)
+	}
+
+	games, err := u.getGames(ctx, ids)
+	if err != nil {
+		return model.GameRatingSummaries{}, err
+	}
+
+	for i, item := range result.Items {
+		result.Items[i].GameTitle = games[item.GameID].Title
+	}
+
+	return result, nil
+}
+
+// getCompletedGame возвращает игру, если у игрока есть завершенная попытка
+func (u *Usecase) getCompletedGame(ctx context.Context, gameID uuid.UUID, playerID uuid.UUID) (model.Game, error) {
+	games, err := u.games.GetGamesByIDs(ctx, []uuid.UUID{gameID})
+	if err != nil {
+		return model.Game{}, err
+	}
+	if len(games) == 0 {
+		return model.Game{}, contracts.ErrGameNotFound
+	}
+
+	completed, err := u.games.HasFinishedSession(ctx, gameID, playerID)
+	if err != nil {
+		return model.Game{}, err
+	}
+	if !completed {
+		return model.Game{}, contracts.ErrGameNotCompleted
+	}
+
+	return games[0], nil
+}
+
+// getGames загружает игры для подстановки названий в отчеты
+func (u *Usecase) getGames(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]model.Game, error) {
+	result := make(map[uuid.UUID]model.Game, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	games, err := u.games.GetGamesByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, game := range games {
+		result[game.ID] = game
+	}
+
+	return result, nil
+}
+
+func findQuestion(game model.Game, questionID int64) *model.Question {
+	for i := range game.Questions {
+		if game.Questions[i].ID == questionID {
+			return &game.Questions[i]
+		}
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"easy-quizy/internal/contracts"
 	"easy-quizy/internal/model"
 	"errors"
+	"time"
 )
 
 func (u *Usecase) AcceptAnswer(ctx context.Context, in *contracts.AcceptAnswersIn) (*contracts.AcceptAnswersOut, error) {
@@ -32,18 +33,36 @@ func (u *Usecase) AcceptAnswer(ctx context.Context, in *contracts.AcceptAnswersI
 			return errors.New("question not found")
 		}
 
-		// Получаем сессию игрока
-		session, err := u.games.GetGameSession(ctx, in.GameID, in.PlayerID)
+		// Повтор запроса с тем же ключом идемпотентности возвращает записанный вердикт
+		if in.IdempotencyKey != nil {
+			gameID, recorded, err := u.games.GetGameSessionAnswerByIdempotencyKey(ctx, in.PlayerID, *in.IdempotencyKey)
+			if err != nil && !errors.Is(err, contracts.ErrAnswerNotFound) {
+				return err
+			}
+
+			if err == nil {
+				if gameID != in.GameID || recorded.QuestionID != in.QuestionID {
+					return contracts.ErrIdempotencyKeyReused
+				}
+
+				result = &contracts.AcceptAnswersOut{
+					IsCorrect:   recorded.IsCorrect,
+					Explanation: question.Explanation,
+				}
+				return nil
+			}
+		}
+
+		// Получаем текущую попытку игрока. Попытка и ответ помечаются одним временем по часам сервиса:
+		// если попытку начинает сам ответ, античиту не от чего отсчитывать время ответа
+		now := time.Now()
+		session, err := u.getOrStartSession(ctx, in.GameID, in.PlayerID, now)
 		if err != nil {
 			return err
 		}
 
 		// Проверяем, отвечал ли игрок на этот вопрос
-		for _, ans := range session.Answers {
-			if ans.QuestionID != in.QuestionID {
-				continue
-			}
-
+		if ans, ok := session.IsAnswered(in.QuestionID); ok {
 			result = &contracts.AcceptAnswersOut{
 				IsCorrect:   ans.IsCorrect,
 				Explanation: question.Explanation,
@@ -51,6 +70,11 @@ func (u *Usecase) AcceptAnswer(ctx context.Context, in *contracts.AcceptAnswersI
 			return nil
 		}
 
+		// Пропущенный вопрос считается пройденным без ответа
+		if session.IsSkipped(in.QuestionID) {
+			return contracts.ErrQuestionSkipped
+		}
+
 		acceptor, ok := u.acceptors[specificGame.Type]
 		if !ok {
 			return errors.New("game type is not supported")
@@ -63,15 +87,66 @@ func (u *Usecase) AcceptAnswer(ctx context.Context, in *contracts.AcceptAnswersI
 
 		result.Explanation = question.Explanation
 
-		return u.games.InsertGameSessionAnswer(
+		// При гонке двойного нажатия сохраняется только первый ответ, возвращаем его вердикт
+		recorded, inserted, err := u.games.InsertGameSessionAnswer(
 			ctx,
-			in.GameID,
-			in.PlayerID,
+			session,
 			model.GameSessionAnswer{
-				QuestionID: in.QuestionID,
-				AnswerID:   in.Answer,
-				IsCorrect:  result.IsCorrect,
+				QuestionID:     in.QuestionID,
+				AnswerID:       in.Answer,
+				IsCorrect:      result.IsCorrect,
+				IdempotencyKey: in.IdempotencyKey,
+				CreatedAt:      now,
 			},
 		)
+		if err != nil {
+			return err
+		}
+
+		result.IsCorrect = recorded.IsCorrect
+
+		var answerReward reward
+		if inserted {
+			answerReward, err = u.grantRewards(ctx, model.PlayTrigger{
+				Type:    model.TriggerAnswerAccepted,
+				Game:    specificGame,
+				Session: session,
+				Answer:  &recorded,
+			})
+			if err != nil {
+				return err
+			}
+
+			event, err := model.NewEvent(model.EventAnswerAccepted, model.AnswerAcceptedPayload{
+				GameID:     in.GameID,
+				PlayerID:   in.PlayerID,
+				SessionID:  session.ID,
+				QuestionID: recorded.QuestionID,
+				AnswerID:   recorded.AnswerID,
+				IsCorrect:  recorded.IsCorrect,
+				AnsweredAt: recorded.CreatedAt,
+			})
+			if err != nil {
+				return err
+			}
+
+			if err := u.events.InsertEvents(ctx, []model.Event{event}); err != nil {
+				return err
+			}
+		}
+
+		// Перечитываем попытку, чтобы увидеть ответы параллельных запросов, и завершаем ее после последнего вопроса
+		session, err = u.games.GetLastGameSession(ctx, in.GameID, in.PlayerID)
+		if err != nil {
+			return err
+		}
+
+		completionReward, err := u.finishIfCompleted(ctx, specificGame, &session)
+		if err != nil {
+			return err
+		}
+
+		answerReward.add(completionReward).apply(result)
+		return nil
 	})
 }
@@ -0,0 +1,16 @@
+package game
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+func (u *Usecase) GetCatalog(ctx context.Context, playerID uuid.UUID, filter model.CatalogFilter) (model.Catalog, error) {
+	filter.Query = strings.TrimSpace(filter.Query)
+	filter.Page = filter.Page.Normalize()
+
+	return u.games.GetCatalog(ctx, playerID, filter)
+}
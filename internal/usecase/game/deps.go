@@ -3,6 +3,7 @@ package game
 import (
 	"context"
 	"easy-quizy/internal/model"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -11,8 +12,39 @@ type (
 	repository interface {
 		GetGamesByIDs(ctx context.Context, ids []uuid.UUID) ([]model.Game, error)
 		GetDailyGame(ctx context.Context) (model.Game, error)
-		GetGameSession(ctx context.Context, gameID uuid.UUID, playerID uuid.UUID) (model.GameSession, error)
-		InsertGameSessionAnswer(ctx context.Context, gameID uuid.UUID, playerID uuid.UUID, data model.GameSessionAnswer) error
-		DeleteGameSessionAnswers(ctx context.Context, gameID uuid.UUID, playerID uuid.UUID) error
+		GetLastGameSession(ctx context.Context, gameID uuid.UUID, playerID uuid.UUID) (model.GameSession, error)
+		CountOwnAttempts(ctx context.Context, gameID uuid.UUID, playerID uuid.UUID) (int64, error)
+		CreateGameSession(ctx context.Context, session model.GameSession) (model.GameSession, error)
+		FinishGameSession(ctx context.Context, sessionID uuid.UUID, finishedAt time.Time, result model.Result) (bool, error)
+		InsertGameSessionAnswer(ctx context.Context, session model.GameSession, data model.GameSessionAnswer) (model.GameSessionAnswer, bool, error)
+		GetGameSessionAnswerByIdempotencyKey(ctx context.Context, playerID uuid.UUID, key string) (uuid.UUID, model.GameSessionAnswer, error)
+		GetPlayerStats(ctx context.Context, playerID uuid.UUID) (model.PlayerStats, error)
+		GetPlayerHistory(ctx context.Context, playerID uuid.UUID, page model.Page) (model.GameHistory, error)
+		GetCatalog(ctx context.Context, playerID uuid.UUID, filter model.CatalogFilter) (model.Catalog, error)
+		GetUnplayedGames(ctx context.Context, playerID uuid.UUID, limit int64) ([]model.Game, error)
+		GetPlayerCompletedGames(ctx context.Context, playerID uuid.UUID) ([]model.CompletedGame, error)
+		CountCompletions(ctx context.Context, gameIDs []uuid.UUID, playerIDs []uuid.UUID) (map[uuid.UUID]int64, error)
+		LockGameSession(ctx context.Context, sessionID uuid.UUID) error
+		InsertLifelineUsage(ctx context.Context, sessionID uuid.UUID, usage model.LifelineUsage) (model.LifelineUsage, bool, error)
+	}
+
+	// eventRepository пишет события в outbox, вызывается внутри транзакции изменения
+	eventRepository interface {
+		InsertEvents(ctx context.Context, events []model.Event) error
+	}
+
+	// achievementEvaluator проверяет правила достижений в транзакции ответа или завершения попытки
+	achievementEvaluator interface {
+		Evaluate(ctx context.Context, trigger model.PlayTrigger) ([]model.Achievement, error)
+		GetSessionAchievements(ctx context.Context, sessionID uuid.UUID) ([]model.Achievement, error)
+	}
+
+	// xpAwarder начисляет опыт в транзакции ответа или завершения попытки
+	xpAwarder interface {
+		Award(ctx context.Context, trigger model.PlayTrigger) (int64, error)
+	}
+
+	userRepository interface {
+		GetChatMates(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
 	}
 )
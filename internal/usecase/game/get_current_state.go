@@ -3,7 +3,7 @@ package game
 import (
 	"context"
 	"easy-quizy/internal/model"
-	"errors"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -17,16 +17,14 @@ func (u *Usecase) GetCurrentState(ctx context.Context, gameID uuid.UUID, playerI
 			return err
 		}
 
-		// Получаем сессию игрока
-		specificSession, err := u.games.GetGameSession(ctx, gameID, playerID)
+		// Получаем текущую попытку игрока, первое открытие игры начинает попытку
+		specificSession, err := u.getOrStartSession(ctx, gameID, playerID, time.Now())
 		if err != nil {
 			return err
 		}
 
-		// Собираем map[QuestionID]AnswerID для быстрых проверок
-		answeredMap := make(map[int64]int64)
-		for _, ans := range specificSession.Answers {
-			answeredMap[ans.QuestionID] = ans.AnswerID
+		if _, err := u.finishIfCompleted(ctx, specificGame, &specificSession); err != nil {
+			return err
 		}
 
 		result = model.State{
@@ -35,60 +33,46 @@ func (u *Usecase) GetCurrentState(ctx context.Context, gameID uuid.UUID, playerI
 				Title: specificGame.Title},
 			Progress: model.Progress{
 				Total:    int64(len(specificGame.Questions)),
-				Answered: int64(len(specificSession.Answers)),
+				Answered: specificSession.DoneCount(),
 			},
 		}
 
-		// Ищем следующий вопрос
-		nextQuestionFound := false
-		for _, item := range specificGame.Questions {
-			if _, ok := answeredMap[item.ID]; ok {
-				continue
+		// Достижения попытки показываются и после перезагрузки, пока игрок не начнет новую
+		result.Achievements, err = u.achievements.GetSessionAchievements(ctx, specificSession.ID)
+		if err != nil {
+			return err
+		}
+
+		if specificSession.IsFinished() {
+			sessionRes, err := sessionResult(specificGame, specificSession)
+			if err != nil {
+				return err
 			}
 
-			// Неотвеченный вопрос найден
-			result.Question = &specificGame.Questions[item.ID]
-			nextQuestionFound = true
-			break
+			result.Result = &sessionRes
+			result.Recommendations, err = u.GetRecommendations(ctx, playerID, stateRecommendations)
+			return err
 		}
 
-		if nextQuestionFound {
-			return nil
-		}
+		result.Lifelines = specificGame.Lifelines.Remaining(specificSession)
 
-		// Все вопросы отвечены, считаем результат
-		totalScore := int64(0)
-		for _, ans := range specificSession.Answers {
-			if int(ans.QuestionID) < 0 || int(ans.QuestionID) >= len(specificGame.Questions) {
-				return errors.New("invalid question id in session answers")
+		// Ищем следующий неотвеченный и непропущенный вопрос
+		for _, item := range specificGame.Questions {
+			if specificSession.IsDone(item.ID) {
+				continue
 			}
-			question := specificGame.Questions[ans.QuestionID]
-
-			for _, opt := range question.AnswerOptions {
-				if opt.ID == ans.AnswerID {
-					if opt.IsCorrect {
-						totalScore++
-					}
 
-					break
-				}
+			// После перезагрузки показываем те же варианты, что остались после 50/50, и открытую подсказку
+			question := item
+			if usage, ok := specificSession.LifelineUsed(item.ID, model.LifelineFiftyFifty); ok {
+				question = item.WithoutAnswers(usage.RemovedAnswerIDs)
 			}
-		}
-
-		// Находим подходящий результат
-		var resultText string
-		for _, res := range specificGame.ScoreResults {
-			if totalScore >= res.From && totalScore <= res.To {
-				resultText = res.Text
-				break
+			if _, ok := specificSession.LifelineUsed(item.ID, model.LifelineHint); ok {
+				result.Hint = item.Hint
 			}
-		}
-		if resultText == "" {
-			return errors.New("no result found for total score")
-		}
-		result.Result = &model.Result{
-			TotalScore: totalScore,
-			ResultText: resultText,
+
+			result.Question = &question
+			break
 		}
 
 		return nil
@@ -0,0 +1,52 @@
+package game
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"easy-quizy/pkg/structs/collections/slices"
+
+	"github.com/google/uuid"
+)
+
+func (u *Usecase) GetPlayerStats(ctx context.Context, playerID uuid.UUID) (model.PlayerStats, error) {
+	return u.games.GetPlayerStats(ctx, playerID)
+}
+
+func (u *Usecase) GetHistory(ctx context.Context, playerID uuid.UUID, page model.Page) (model.GameHistory, error) {
+	history, err := u.games.GetPlayerHistory(ctx, playerID, page.Normalize())
+	if err != nil {
+		return model.GameHistory{}, err
+	}
+
+	// У попыток, перенесенных из старой схемы, нет текста результата: считаем его по очкам
+	var withoutText []uuid.UUID
+	for _, item := range history.Items {
+		if item.ResultText == nil {
+			withoutText = append(withoutText, item.GameID)
+		}
+	}
+	if len(withoutText) == 0 {
+		return history, nil
+	}
+
+	games, err := u.games.GetGamesByIDs(ctx, withoutText)
+	if err != nil {
+		return model.GameHistory{}, err
+	}
+
+	for i, item := range history.Items {
+		if item.ResultText != nil {
+			continue
+		}
+
+		specificGame, err := slices.Single(games, func(g model.Game) bool { return g.ID == item.GameID })
+		if err != nil {
+			continue
+		}
+
+		text := specificGame.ResultText(item.Score)
+		history.Items[i].ResultText = &text
+	}
+
+	return history, nil
+}
@@ -0,0 +1,150 @@
+package game
+
+import (
+	"context"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"math/rand/v2"
+	"time"
+)
+
+// fiftyFiftyRemoved сколько неправильных вариантов убирает 50/50
+const fiftyFiftyRemoved = 2
+
+// UseLifeline применяет подсказку к вопросу текущей попытки. Повторное использование той же подсказки
+// на том же вопросе возвращает записанный результат и не тратит лимит.
+func (u *Usecase) UseLifeline(ctx context.Context, in contracts.UseLifelineIn) (contracts.UseLifelineOut, error) {
+	var result contracts.UseLifelineOut
+	return result, u.trm.Do(ctx, func(ctx context.Context) error {
+		if !in.Type.IsValid() {
+			return contracts.ErrInvalidLifeline
+		}
+
+		specificGame, err := u.Get(ctx, in.GameID)
+		if err != nil {
+			return err
+		}
+
+		allowance := specificGame.Lifelines.Allowance[in.Type]
+		if allowance <= 0 {
+			return contracts.ErrLifelineNotAvailable
+		}
+
+		question, ok := findQuestion(specificGame, in.QuestionID)
+		if !ok {
+			return contracts.ErrQuestionNotFound
+		}
+
+		session, err := u.getOrStartSession(ctx, in.GameID, in.PlayerID, time.Now())
+		if err != nil {
+			return err
+		}
+
+		// Блокируем попытку и перечитываем ее, чтобы параллельные запросы не превысили лимит
+		if err := u.games.LockGameSession(ctx, session.ID); err != nil {
+			return err
+		}
+
+		session, err = u.games.GetLastGameSession(ctx, in.GameID, in.PlayerID)
+		if err != nil {
+			return err
+		}
+		if session.IsFinished() {
+			return contracts.ErrNotActiveSessionNotFound
+		}
+
+		usage, used := session.LifelineUsed(question.ID, in.Type)
+		if !used {
+			if session.IsDone(question.ID) {
+				return contracts.ErrQuestionAlreadyAnswered
+			}
+			if session.LifelinesUsed(in.Type) >= allowance {
+				return contracts.ErrLifelineLimitReached
+			}
+
+			usage, err = prepareLifeline(question, in.Type)
+			if err != nil {
+				return err
+			}
+
+			usage, _, err = u.games.InsertLifelineUsage(ctx, session.ID, usage)
+			if err != nil {
+				return err
+			}
+
+			session.Lifelines = append(session.Lifelines, usage)
+		}
+
+		result = contracts.UseLifelineOut{
+			Type:       in.Type,
+			QuestionID: question.ID,
+			Question:   question,
+			Remaining:  specificGame.Lifelines.Remaining(session),
+		}
+
+		// 50/50 и подсказка действуют вместе, поэтому в ответе учитываются обе
+		if fiftyFifty, ok := session.LifelineUsed(question.ID, model.LifelineFiftyFifty); ok {
+			result.Question = question.WithoutAnswers(fiftyFifty.RemovedAnswerIDs)
+		}
+		if _, ok := session.LifelineUsed(question.ID, model.LifelineHint); ok {
+			result.Hint = question.Hint
+		}
+
+		if usage.Type != model.LifelineSkip {
+			return nil
+		}
+
+		// Пропуск последнего вопроса завершает попытку
+		completionReward, err := u.finishIfCompleted(ctx, specificGame, &session)
+		if err != nil {
+			return err
+		}
+
+		result.XP = completionReward.xp
+		result.Achievements = completionReward.achievements
+		return nil
+	})
+}
+
+// prepareLifeline проверяет, что подсказку можно применить к вопросу, и готовит запись о ней
+func prepareLifeline(question model.Question, lifelineType model.LifelineType) (model.LifelineUsage, error) {
+	usage := model.LifelineUsage{
+		QuestionID: question.ID,
+		Type:       lifelineType,
+	}
+
+	switch lifelineType {
+	case model.LifelineHint:
+		if question.Hint == nil || *question.Hint == "" {
+			return model.LifelineUsage{}, contracts.ErrLifelineNotAvailable
+		}
+	case model.LifelineFiftyFifty:
+		var wrong []int64
+		for _, option := range question.AnswerOptions {
+			if !option.IsCorrect {
+				wrong = append(wrong, option.ID)
+			}
+		}
+
+		// Должен остаться хотя бы один неправильный вариант, иначе 50/50 выдает ответ:
+		// в вопросе с тремя вариантами убирается один
+		if len(wrong) < 2 {
+			return model.LifelineUsage{}, contracts.ErrLifelineNotAvailable
+		}
+
+		rand.Shuffle(len(wrong), func(i, j int) { wrong[i], wrong[j] = wrong[j], wrong[i] })
+		usage.RemovedAnswerIDs = wrong[:min(fiftyFiftyRemoved, len(wrong)-1)]
+	}
+
+	return usage, nil
+}
+
+func findQuestion(specificGame model.Game, questionID int64) (model.Question, bool) {
+	for _, question := range specificGame.Questions {
+		if question.ID == questionID {
+			return question, true
+		}
+	}
+
+	return model.Question{}, false
+}
@@ -0,0 +1,121 @@
+package game
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// recommendationCandidates сколько самых свежих непройденных игр участвует в ранжировании
+	recommendationCandidates = 200
+	defaultRecommendations   = 10
+	// stateRecommendations сколько игр предлагается на экране результата
+	stateRecommendations = 3
+
+	categoryWeight   = 0.5
+	popularityWeight = 0.3
+	freshnessWeight  = 0.2
+
+	// freshnessPeriod за это время вклад свежести падает вдвое
+	freshnessPeriod = 30 * 24 * time.Hour
+)
+
+// GetRecommendations ранжирует игры, в которые игрок еще не играл: по близости к категориям пройденных
+// игр с учетом результата, по популярности среди участников общих чатов и по свежести
+func (u *Usecase) GetRecommendations(ctx context.Context, playerID uuid.UUID, limit int64) ([]model.Recommendation, error) {
+	if limit <= 0 {
+		limit = defaultRecommendations
+	}
+	limit = min(limit, model.MaxPageLimit)
+
+	candidates, err := u.games.GetUnplayedGames(ctx, playerID, recommendationCandidates)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return []model.Recommendation{}, nil
+	}
+
+	completed, err := u.games.GetPlayerCompletedGames(ctx, playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	mates, err := u.users.GetChatMates(ctx, playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	gameIDs := make([]uuid.UUID, 0, len(candidates))
+	for _, game := range candidates {
+		gameIDs = append(gameIDs, game.ID)
+	}
+
+	completions, err := u.games.CountCompletions(ctx, gameIDs, mates)
+	if err != nil {
+		return nil, err
+	}
+
+	var maxCompletions int64
+	for _, count := range completions {
+		maxCompletions = max(maxCompletions, count)
+	}
+
+	affinity := categoryAffinity(completed)
+	now := time.Now()
+
+	result := make([]model.Recommendation, 0, len(candidates))
+	for _, game := range candidates {
+		var score float64
+		if game.Category != nil {
+			score += categoryWeight * affinity[*game.Category]
+		}
+		if maxCompletions > 0 {
+			score += popularityWeight * float64(completions[game.ID]) / float64(maxCompletions)
+		}
+		if !game.CreatedAt.IsZero() {
+			age := max(now.Sub(game.CreatedAt), 0)
+			score += freshnessWeight / (1 + float64(age)/float64(freshnessPeriod))
+		}
+
+		result = append(result, model.Recommendation{
+			CatalogItem: model.CatalogItemFromGame(game),
+			Score:       score,
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Score != result[j].Score {
+			return result[i].Score > result[j].Score
+		}
+
+		return result[i].Title < result[j].Title
+	})
+	if int64(len(result)) > limit {
+		result = result[:limit]
+	}
+
+	return result, nil
+}
+
+// categoryAffinity доля пройденных игр в каждой категории, хорошо пройденные игры весят больше
+func categoryAffinity(completed []model.CompletedGame) map[string]float64 {
+	result := make(map[string]float64)
+	if len(completed) == 0 {
+		return result
+	}
+
+	for _, game := range completed {
+		if game.Category == nil {
+			continue
+		}
+
+		result[*game.Category] += (0.5 + 0.5*game.ScoreRatio()) / float64(len(completed))
+	}
+
+	return result
+}
@@ -2,21 +2,74 @@ package game
 
 import (
 	"context"
+	"easy-quizy/internal/contracts"
 	"easy-quizy/internal/model"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// Reset начинает новую попытку по правилу повтора игры, предыдущая остается в истории
 func (u *Usecase) Reset(ctx context.Context, gameID uuid.UUID, playerID uuid.UUID) error {
-	specificGame, err := u.Get(ctx, gameID)
-	if err != nil {
+	return u.trm.Do(ctx, func(ctx context.Context) error {
+		specificGame, err := u.Get(ctx, gameID)
+		if err != nil {
+			return err
+		}
+
+		session, err := u.games.GetLastGameSession(ctx, gameID, playerID)
+		if errors.Is(err, contracts.ErrSessionNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// Попытка без ответов и так начинается с начала
+		if session.DoneCount() == 0 {
+			return nil
+		}
+
+		// Попытки, перешедшие при слиянии аккаунтов, лимит не расходуют
+		policy := specificGame.ReplayPolicy()
+		var attempts int64
+		if policy.Type == model.ReplayAttempts {
+			if attempts, err = u.games.CountOwnAttempts(ctx, gameID, playerID); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		if err = checkReplay(policy, session, attempts, now); err != nil {
+			return err
+		}
+
+		_, err = u.startSession(ctx, gameID, playerID, session.Attempt+1, now)
 		return err
-	}
+	})
+}
 
-	// Ежедневный квиз нельзя перезапустить
-	if specificGame.Type == model.GameTypeDaily {
-		return nil
+// checkReplay проверяет, можно ли после попытки session начать следующую; attempts — свои попытки игрока в игре
+func checkReplay(policy model.ReplayPolicy, session model.GameSession, attempts int64, now time.Time) error {
+	switch policy.Type {
+	case model.ReplayNever:
+		return contracts.ErrReplayNotAllowed
+	case model.ReplayAttempts:
+		if attempts >= policy.MaxAttempts {
+			return contracts.ErrReplayAttemptsExhausted
+		}
+	case model.ReplayCooldown:
+		// Отсчет от завершения попытки, а для незавершенной — от ее начала
+		from := session.StartedAt
+		if session.FinishedAt != nil {
+			from = *session.FinishedAt
+		}
+		if availableAt := from.Add(policy.Cooldown); now.Before(availableAt) {
+			return fmt.Errorf("%w: available at %s", contracts.ErrReplayCooldown, availableAt.UTC().Format(time.RFC3339))
+		}
 	}
 
-	return u.games.DeleteGameSessionAnswers(ctx, gameID, playerID)
+	return nil
 }
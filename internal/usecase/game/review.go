@@ -0,0 +1,55 @@
+package game
+
+import (
+	"context"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// GetReview доступен только для завершенной последней попытки, иначе правильные ответы утекли бы посреди игры
+func (u *Usecase) GetReview(ctx context.Context, gameID uuid.UUID, playerID uuid.UUID) (model.Review, error) {
+	var result model.Review
+	return result, u.trm.Do(ctx, func(ctx context.Context) error {
+		specificGame, err := u.Get(ctx, gameID)
+		if err != nil {
+			return err
+		}
+
+		session, err := u.games.GetLastGameSession(ctx, gameID, playerID)
+		if err != nil {
+			return err
+		}
+		if !session.IsFinished() {
+			return contracts.ErrSessionNotFinished
+		}
+
+		sessionRes, err := sessionResult(specificGame, session)
+		if err != nil {
+			return err
+		}
+
+		result = model.Review{
+			GameInfo: model.GameInfo{
+				ID:    specificGame.ID,
+				Title: specificGame.Title,
+			},
+			Result: sessionRes,
+			Items:  make([]model.ReviewItem, 0, len(specificGame.Questions)),
+		}
+
+		for _, question := range specificGame.Questions {
+			item := model.ReviewItem{Question: question}
+			if answer, ok := session.IsAnswered(question.ID); ok {
+				item.ChosenAnswerID = &answer.AnswerID
+				item.IsCorrect = answer.IsCorrect
+			}
+			item.Skipped = session.IsSkipped(question.ID)
+
+			result.Items = append(result.Items, item)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,42 @@
+package game
+
+import (
+	"context"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+)
+
+type (
+	// reward опыт и достижения, полученные за событие в игре
+	reward struct {
+		xp           int64
+		achievements []model.Achievement
+	}
+)
+
+// grantRewards начисляет опыт и проверяет достижения в текущей транзакции
+func (u *Usecase) grantRewards(ctx context.Context, trigger model.PlayTrigger) (reward, error) {
+	xp, err := u.progression.Award(ctx, trigger)
+	if err != nil {
+		return reward{}, err
+	}
+
+	achievements, err := u.achievements.Evaluate(ctx, trigger)
+	if err != nil {
+		return reward{}, err
+	}
+
+	return reward{xp: xp, achievements: achievements}, nil
+}
+
+func (r reward) add(other reward) reward {
+	return reward{
+		xp:           r.xp + other.xp,
+		achievements: append(r.achievements, other.achievements...),
+	}
+}
+
+func (r reward) apply(out *contracts.AcceptAnswersOut) {
+	out.XP = r.xp
+	out.Achievements = r.achievements
+}
@@ -0,0 +1,161 @@
+package game
+
+import (
+	"context"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// getOrStartSession возвращает текущую попытку игрока, а если игрок еще не открывал игру — начинает первую в момент now
+func (u *Usecase) getOrStartSession(ctx context.Context, gameID uuid.UUID, playerID uuid.UUID, now time.Time) (model.GameSession, error) {
+	session, err := u.games.GetLastGameSession(ctx, gameID, playerID)
+	if err == nil {
+		return session, nil
+	}
+	if !errors.Is(err, contracts.ErrSessionNotFound) {
+		return model.GameSession{}, err
+	}
+
+	return u.startSession(ctx, gameID, playerID, 1, now)
+}
+
+// startSession время начала задается часами сервиса, как и время ответов: античит сравнивает их между собой
+func (u *Usecase) startSession(ctx context.Context, gameID uuid.UUID, playerID uuid.UUID, attempt int64, startedAt time.Time) (model.GameSession, error) {
+	return u.games.CreateGameSession(ctx, model.GameSession{
+		ID:        uuid.New(),
+		GameID:    gameID,
+		PlayerID:  playerID,
+		Attempt:   attempt,
+		StartedAt: startedAt,
+	})
+}
+
+// finishIfCompleted фиксирует результат попытки, когда отвечены или пропущены все вопросы.
+// Вызывается и после ответа, и при чтении состояния: параллельные ответы на последние вопросы
+// могут не увидеть друг друга. Возвращает награды за завершение.
+func (u *Usecase) finishIfCompleted(ctx context.Context, specificGame model.Game, session *model.GameSession) (reward, error) {
+	if session.IsFinished() || session.DoneCount() < int64(len(specificGame.Questions)) {
+		return reward{}, nil
+	}
+
+	result, err := calculateResult(specificGame, *session)
+	if err != nil {
+		return reward{}, err
+	}
+
+	finishedAt := time.Now()
+	finished, err := u.games.FinishGameSession(ctx, session.ID, finishedAt, result)
+	if err != nil {
+		return reward{}, err
+	}
+
+	session.FinishedAt = &finishedAt
+	session.Score = &result.TotalScore
+	session.ResultText = &result.ResultText
+
+	// Попытку уже завершил параллельный запрос, он же опубликовал события и выдал награды
+	if !finished {
+		return reward{}, nil
+	}
+
+	if err := u.publishCompleted(ctx, specificGame, *session); err != nil {
+		return reward{}, err
+	}
+
+	return u.grantRewards(ctx, model.PlayTrigger{
+		Type:    model.TriggerGameCompleted,
+		Game:    specificGame,
+		Session: *session,
+	})
+}
+
+// publishCompleted пишет в outbox события завершения в транзакции, завершившей попытку
+func (u *Usecase) publishCompleted(ctx context.Context, specificGame model.Game, session model.GameSession) error {
+	payload := model.GameCompletedPayload{
+		GameID:     specificGame.ID,
+		GameType:   specificGame.Type,
+		PlayerID:   session.PlayerID,
+		SessionID:  session.ID,
+		Attempt:    session.Attempt,
+		Score:      *session.Score,
+		ResultText: *session.ResultText,
+		StartedAt:  session.StartedAt,
+		FinishedAt: *session.FinishedAt,
+	}
+
+	eventTypes := []model.EventType{model.EventGameCompleted}
+	if specificGame.Type == model.GameTypeDaily {
+		eventTypes = append(eventTypes, model.EventDailyCompleted)
+	}
+
+	events := make([]model.Event, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		event, err := model.NewEvent(eventType, payload)
+		if err != nil {
+			return err
+		}
+
+		events = append(events, event)
+	}
+
+	return u.events.InsertEvents(ctx, events)
+}
+
+// sessionResult результат завершенной попытки; для попыток, перенесенных из старой схемы, текст считается заново
+func sessionResult(specificGame model.Game, session model.GameSession) (model.Result, error) {
+	if session.Score != nil && session.ResultText != nil {
+		return model.Result{
+			TotalScore: *session.Score,
+			ResultText: *session.ResultText,
+		}, nil
+	}
+
+	return calculateResult(specificGame, session)
+}
+
+// calculateResult считает по очку за правильный ответ; если игра снижает очки за подсказки,
+// ответ после 50/50 или подсказки очка не приносит
+func calculateResult(specificGame model.Game, session model.GameSession) (model.Result, error) {
+	totalScore := int64(0)
+	for _, ans := range session.Answers {
+		if int(ans.QuestionID) < 0 || int(ans.QuestionID) >= len(specificGame.Questions) {
+			return model.Result{}, errors.New("invalid question id in session answers")
+		}
+		question := specificGame.Questions[ans.QuestionID]
+
+		for _, opt := range question.AnswerOptions {
+			if opt.ID == ans.AnswerID {
+				if opt.IsCorrect && !scoreReduced(specificGame, session, ans.QuestionID) {
+					totalScore++
+				}
+
+				break
+			}
+		}
+	}
+
+	// Находим подходящий результат
+	resultText := specificGame.ResultText(totalScore)
+	if resultText == "" {
+		return model.Result{}, errors.New("no result found for total score")
+	}
+
+	return model.Result{
+		TotalScore: totalScore,
+		ResultText: resultText,
+	}, nil
+}
+
+func scoreReduced(specificGame model.Game, session model.GameSession, questionID int64) bool {
+	if !specificGame.Lifelines.ReduceScore {
+		return false
+	}
+
+	_, fiftyFifty := session.LifelineUsed(questionID, model.LifelineFiftyFifty)
+	_, hint := session.LifelineUsed(questionID, model.LifelineHint)
+	return fiftyFifty || hint
+}
@@ -14,8 +14,12 @@ type (
 	}
 
 	Usecase struct {
-		games repository
-		trm   trm.Manager
+		games        repository
+		users        userRepository
+		events       eventRepository
+		achievements achievementEvaluator
+		progression  xpAwarder
+		trm          trm.Manager
 
 		acceptors map[model.GameType]Acceptor
 	}
@@ -23,13 +27,23 @@ type (
 
 func NewUsecase(
 	games repository,
+	users userRepository,
+	events eventRepository,
+	achievements achievementEvaluator,
+	progression xpAwarder,
 	trm trm.Manager,
 ) contracts.GameUsecase {
 	return &Usecase{
-		games: games,
-		trm:   trm,
+		games:        games,
+		users:        users,
+		events:       events,
+		achievements: achievements,
+		progression:  progression,
+		trm:          trm,
 		acceptors: map[model.GameType]Acceptor{
 			model.GameTypeClassic: acceptor.NewClassicAcceptor(),
+			// Ежедневная игра отвечается и считается как классическая
+			model.GameTypeDaily: acceptor.NewClassicAcceptor(),
 		},
 	}
 }
@@ -0,0 +1,12 @@
+package health
+
+import (
+	"context"
+)
+
+type (
+	schemaRepository interface {
+		Ping(ctx context.Context) error
+		Version(ctx context.Context) (uint, bool, error)
+	}
+)
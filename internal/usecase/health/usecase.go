@@ -0,0 +1,81 @@
+package health
+
+import (
+	"context"
+	"easy-quizy/internal/contracts"
+	"fmt"
+	"sync/atomic"
+)
+
+const (
+	checkShutdown  = "shutdown"
+	checkDatabase  = "database"
+	checkSchema    = "schema"
+	checkDailyGame = "daily_game"
+)
+
+type Usecase struct {
+	schema          schemaRepository
+	games           contracts.GameUsecase
+	expectedVersion uint
+
+	shuttingDown atomic.Bool
+}
+
+func NewUsecase(
+	schema schemaRepository,
+	games contracts.GameUsecase,
+	expectedVersion uint,
+) *Usecase {
+	return &Usecase{
+		schema:          schema,
+		games:           games,
+		expectedVersion: expectedVersion,
+	}
+}
+
+// Shutdown переводит сервис в состояние "не готов", чтобы балансировщик перестал слать трафик до остановки
+func (u *Usecase) Shutdown() {
+	u.shuttingDown.Store(true)
+}
+
+func (u *Usecase) Ready(ctx context.Context) contracts.Readiness {
+	if u.shuttingDown.Load() {
+		return contracts.Readiness{
+			Checks: []contracts.ReadinessCheck{{Name: checkShutdown, Err: contracts.ErrShuttingDown}},
+		}
+	}
+
+	result := contracts.Readiness{Ready: true}
+	check := func(name string, fn func(ctx context.Context) error) {
+		err := fn(ctx)
+		result.Checks = append(result.Checks, contracts.ReadinessCheck{Name: name, Err: err})
+		if err != nil {
+			result.Ready = false
+		}
+	}
+
+	check(checkDatabase, u.schema.Ping)
+	check(checkSchema, u.checkSchema)
+	check(checkDailyGame, func(ctx context.Context) error {
+		_, err := u.games.GetDaily(ctx)
+		return err
+	})
+
+	return result
+}
+
+func (u *Usecase) checkSchema(ctx context.Context) error {
+	version, dirty, err := u.schema.Version(ctx)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("%w: %d", contracts.ErrSchemaVersionIsDirty, version)
+	}
+	if version < u.expectedVersion {
+		return fmt.Errorf("%w: %d < %d", contracts.ErrSchemaIsBehind, version, u.expectedVersion)
+	}
+
+	return nil
+}
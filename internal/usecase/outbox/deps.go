@@ -0,0 +1,21 @@
+package outbox
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"time"
+)
+
+type (
+	repository interface {
+		NextEvent(ctx context.Context, now time.Time) (model.Event, error)
+		MarkProcessed(ctx context.Context, id int64, processedAt time.Time) error
+		ScheduleRetry(ctx context.Context, id int64, availableAt time.Time, lastError string) error
+		MarkFailed(ctx context.Context, id int64, failedAt time.Time, lastError string) error
+		DeleteProcessed(ctx context.Context, before time.Time) (int64, error)
+		GetSubscriberDeliveries(ctx context.Context, eventID int64) ([]model.SubscriberDelivery, error)
+		MarkSubscriberProcessed(ctx context.Context, eventID int64, subscriber string, processedAt time.Time) error
+		ScheduleSubscriberRetry(ctx context.Context, eventID int64, subscriber string, availableAt time.Time, lastError string) error
+		MarkSubscriberFailed(ctx context.Context, eventID int64, subscriber string, failedAt time.Time, lastError string) error
+	}
+)
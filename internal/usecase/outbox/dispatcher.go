@@ -0,0 +1,246 @@
+package outbox
+
+import (
+	"context"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"easy-quizy/pkg/structs"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/settings"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// batchSize сколько событий доставляется за один вызов DispatchPending
+	batchSize = 100
+
+	retryBaseDelay = time.Second
+	retryMaxDelay  = 10 * time.Minute
+)
+
+// nested обработчик подписчика выполняется в точке сохранения внутри транзакции события
+var nested = settings.Must(settings.WithPropagation(trm.PropagationNested))
+
+type (
+	Config struct {
+		// MaxAttempts после стольких неудачных доставок событие снимается с доставки
+		MaxAttempts int64
+		// Retention сколько хранить доставленные события
+		Retention time.Duration
+	}
+
+	Dispatcher struct {
+		repo repository
+		trm  trm.Manager
+		cfg  Config
+
+		mu       sync.RWMutex
+		handlers map[model.EventType][]subscription
+	}
+
+	subscription struct {
+		name    string
+		handler contracts.EventHandler
+	}
+)
+
+func NewDispatcher(
+	repo repository,
+	trm trm.Manager,
+	cfg Config,
+) contracts.EventDispatcher {
+	return &Dispatcher{
+		repo:     repo,
+		trm:      trm,
+		cfg:      cfg,
+		handlers: make(map[model.EventType][]subscription),
+	}
+}
+
+func (d *Dispatcher) Subscribe(eventType model.EventType, name string, handler contracts.EventHandler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.handlers[eventType] = append(d.handlers[eventType], subscription{name: name, handler: handler})
+}
+
+func (d *Dispatcher) DispatchPending(ctx context.Context) error {
+	for range batchSize {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		dispatched, err := d.dispatchNext(ctx)
+		if err != nil {
+			return err
+		}
+		if !dispatched {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (d *Dispatcher) Cleanup(ctx context.Context) error {
+	deleted, err := d.repo.DeleteProcessed(ctx, time.Now().Add(-d.cfg.Retention))
+	if err != nil {
+		return err
+	}
+
+	if deleted > 0 {
+		logrus.Infof("Outbox cleanup removed %d delivered events", deleted)
+	}
+
+	return nil
+}
+
+// dispatchNext доставляет одно событие. Транзакция держит блокировку события, а каждый подписчик
+// обрабатывает его во вложенной транзакции вместе с отметкой о доставке именно ему: ошибка одного
+// подписчика откатывает только его изменения, а повтор и отказ планируются для него одного.
+func (d *Dispatcher) dispatchNext(ctx context.Context) (bool, error) {
+	var found bool
+
+	err := d.trm.Do(ctx, func(ctx context.Context) error {
+		now := time.Now()
+		event, err := d.repo.NextEvent(ctx, now)
+		if errors.Is(err, contracts.ErrEventNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		found = true
+
+		deliveries, err := d.repo.GetSubscriberDeliveries(ctx, event.ID)
+		if err != nil {
+			return err
+		}
+
+		byName := make(map[string]model.SubscriberDelivery, len(deliveries))
+		for _, delivery := range deliveries {
+			byName[delivery.Subscriber] = delivery
+		}
+
+		var (
+			next   time.Time
+			errs   []error
+			failed bool
+		)
+		for _, sub := range d.subscriptions(event.Type) {
+			delivery := byName[sub.name]
+			if delivery.ProcessedAt != nil {
+				continue
+			}
+			if delivery.FailedAt != nil {
+				failed = true
+				continue
+			}
+			if delivery.AvailableAt.After(now) {
+				next = earliest(next, delivery.AvailableAt)
+				continue
+			}
+
+			handlerErr := d.handle(ctx, event, sub)
+			if handlerErr == nil {
+				continue
+			}
+			errs = append(errs, handlerErr)
+
+			retryAt, err := d.retry(ctx, event, delivery, sub.name, handlerErr)
+			if err != nil {
+				return err
+			}
+			if retryAt.IsZero() {
+				failed = true
+				continue
+			}
+			next = earliest(next, retryAt)
+		}
+
+		if !next.IsZero() {
+			// Событие возвращается в очередь к ближайшему повтору; ошибка пуста, если этим проходом
+			// доставлено всем, чья очередь подошла
+			var lastError string
+			if err := errors.Join(errs...); err != nil {
+				lastError = err.Error()
+			}
+
+			return d.repo.ScheduleRetry(ctx, event.ID, next, lastError)
+		}
+		if failed {
+			return d.repo.MarkFailed(ctx, event.ID, now, "some subscribers exhausted their attempts")
+		}
+
+		return d.repo.MarkProcessed(ctx, event.ID, time.Now())
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return found, nil
+}
+
+func (d *Dispatcher) subscriptions(eventType model.EventType) []subscription {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.handlers[eventType]
+}
+
+// handle вызывает обработчик подписчика во вложенной транзакции, которая отмечает событие доставленным ему
+func (d *Dispatcher) handle(ctx context.Context, event model.Event, sub subscription) error {
+	err := d.trm.DoWithSettings(ctx, nested, func(ctx context.Context) error {
+		err := structs.WithRecover(func() error {
+			return sub.handler(ctx, event)
+		})
+		if err != nil {
+			return err
+		}
+
+		return d.repo.MarkSubscriberProcessed(ctx, event.ID, sub.name, time.Now())
+	})
+	if err != nil {
+		return fmt.Errorf("handler '%s': %w", sub.name, err)
+	}
+
+	return nil
+}
+
+// retry откладывает доставку подписчику с экспоненциальной задержкой или снимает ее после MaxAttempts.
+// Возвращает время повтора, нулевое, если подписчик исчерпал попытки.
+func (d *Dispatcher) retry(ctx context.Context, event model.Event, delivery model.SubscriberDelivery, name string, cause error) (time.Time, error) {
+	now := time.Now()
+	attempts := delivery.Attempts + 1
+
+	if attempts >= d.cfg.MaxAttempts {
+		logrus.Errorf("Outbox event %d (%s) failed for '%s' after %d attempts: %v", event.ID, event.Type, name, attempts, cause)
+		return time.Time{}, d.repo.MarkSubscriberFailed(ctx, event.ID, name, now, cause.Error())
+	}
+
+	logrus.Warnf("Outbox event %d (%s) failed for '%s', attempt %d: %v", event.ID, event.Type, name, attempts, cause)
+	retryAt := now.Add(retryDelay(attempts))
+	return retryAt, d.repo.ScheduleSubscriberRetry(ctx, event.ID, name, retryAt, cause.Error())
+}
+
+func earliest(current time.Time, candidate time.Time) time.Time {
+	if current.IsZero() || candidate.Before(current) {
+		return candidate
+	}
+
+	return current
+}
+
+func retryDelay(attempts int64) time.Duration {
+	delay := retryBaseDelay
+	for i := int64(1); i < attempts && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, retryMaxDelay)
+}
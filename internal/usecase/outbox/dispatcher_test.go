@@ -0,0 +1,116 @@
+package outbox
+
+import (
+	"context"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	outboxRepo "easy-quizy/internal/repositories/outbox"
+	"easy-quizy/pkg/transaction"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDispatchPerSubscriber(t *testing.T) {
+	tests := []struct {
+		name          string
+		failures      int
+		maxAttempts   int64
+		wantFlaky     int
+		wantEventDone bool
+		wantFailed    bool
+	}{
+		{name: "all subscribers succeed", failures: 0, maxAttempts: 3, wantFlaky: 1, wantEventDone: true},
+		{name: "retry reaches only the failed subscriber", failures: 1, maxAttempts: 3, wantFlaky: 2, wantEventDone: true},
+		{name: "failed subscriber gives up alone", failures: 2, maxAttempts: 2, wantFlaky: 2, wantEventDone: true, wantFailed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := outboxRepo.NewMemoryRepository()
+			dispatcher := NewDispatcher(repo, transaction.NewNoopManager(), Config{MaxAttempts: tt.maxAttempts, Retention: time.Hour})
+
+			var stableCalls, flakyCalls int
+			dispatcher.Subscribe(model.EventGameCompleted, "stable", func(context.Context, model.Event) error {
+				stableCalls++
+				return nil
+			})
+			dispatcher.Subscribe(model.EventGameCompleted, "flaky", func(context.Context, model.Event) error {
+				flakyCalls++
+				if flakyCalls <= tt.failures {
+					return errors.New("unavailable")
+				}
+				return nil
+			})
+
+			event, err := model.NewEvent(model.EventGameCompleted, model.GameCompletedPayload{})
+			if err != nil {
+				t.Fatalf("NewEvent: %v", err)
+			}
+			if err := repo.InsertEvents(ctx, []model.Event{event}); err != nil {
+				t.Fatalf("InsertEvents: %v", err)
+			}
+
+			// Первый повтор откладывается на retryBaseDelay
+			for pass := 0; pass <= tt.failures; pass++ {
+				if pass > 0 {
+					time.Sleep(retryBaseDelay + 100*time.Millisecond)
+				}
+				if err := dispatcher.DispatchPending(ctx); err != nil {
+					t.Fatalf("DispatchPending: %v", err)
+				}
+			}
+
+			if stableCalls != 1 {
+				t.Errorf("stable subscriber called %d times, want 1", stableCalls)
+			}
+			if flakyCalls != tt.wantFlaky {
+				t.Errorf("flaky subscriber called %d times, want %d", flakyCalls, tt.wantFlaky)
+			}
+
+			_, err = repo.NextEvent(ctx, time.Now().Add(time.Hour))
+			if done := errors.Is(err, contracts.ErrEventNotFound); done != tt.wantEventDone {
+				t.Errorf("event done = %v (NextEvent error %v), want %v", done, err, tt.wantEventDone)
+			}
+
+			deliveries, err := repo.GetSubscriberDeliveries(ctx, 1)
+			if err != nil {
+				t.Fatalf("GetSubscriberDeliveries: %v", err)
+			}
+			for _, delivery := range deliveries {
+				switch delivery.Subscriber {
+				case "stable":
+					if delivery.ProcessedAt == nil || delivery.Attempts != 0 {
+						t.Errorf("stable delivery = %+v, want processed on the first attempt", delivery)
+					}
+				case "flaky":
+					if failed := delivery.FailedAt != nil; failed != tt.wantFailed {
+						t.Errorf("flaky failed = %v, want %v", failed, tt.wantFailed)
+					}
+					if !tt.wantFailed && delivery.ProcessedAt == nil {
+						t.Errorf("flaky delivery = %+v, want processed", delivery)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int64
+		want     time.Duration
+	}{
+		{attempts: 1, want: time.Second},
+		{attempts: 2, want: 2 * time.Second},
+		{attempts: 5, want: 16 * time.Second},
+		{attempts: 30, want: retryMaxDelay},
+	}
+
+	for _, tt := range tests {
+		if got := retryDelay(tt.attempts); got != tt.want {
+			t.Errorf("retryDelay(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package progression
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	repository interface {
+		InsertEntries(ctx context.Context, entries []model.XPEntry) ([]model.XPEntry, error)
+		GetTotalXP(ctx context.Context, userIDs []uuid.UUID, excludeSessions []uuid.UUID) (map[uuid.UUID]int64, error)
+	}
+
+	// flagRepository попытки, помеченные античитом, не учитываются в рейтинге
+	flagRepository interface {
+		GetFlaggedSessions(ctx context.Context, userIDs []uuid.UUID) ([]uuid.UUID, error)
+	}
+
+	gameRepository interface {
+		GetDailyCompletionDays(ctx context.Context, playerID uuid.UUID, since time.Time) ([]time.Time, error)
+	}
+
+	userRepository interface {
+		GetUserChat(ctx context.Context, userID uuid.UUID, chatID int64) (model.UserChat, error)
+		GetChatMembers(ctx context.Context, chatID int64) ([]uuid.UUID, error)
+	}
+)
@@ -0,0 +1,221 @@
+package progression
+
+import (
+	"context"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+	"github.com/google/uuid"
+)
+
+type (
+	Config struct {
+		// CorrectAnswerXP опыт за правильный ответ
+		CorrectAnswerXP int64
+		// CompletionXP бонус за завершение попытки
+		CompletionXP int64
+		// DailyMultiplier множитель опыта за ответы и завершение ежедневной игры
+		DailyMultiplier int64
+		// StreakXP бонус за каждый день серии ежедневных игр сверх первого
+		StreakXP int64
+		// StreakCap сколько дней серии учитывается в бонусе
+		StreakCap  int64
+		LevelCurve model.LevelCurve
+	}
+
+	Usecase struct {
+		ledger repository
+		games  gameRepository
+		users  userRepository
+		flags  flagRepository
+		trm    trm.Manager
+		cfg    Config
+	}
+)
+
+func NewUsecase(
+	ledger repository,
+	games gameRepository,
+	users userRepository,
+	flags flagRepository,
+	trm trm.Manager,
+	cfg Config,
+) contracts.ProgressionUsecase {
+	return &Usecase{
+		ledger: ledger,
+		games:  games,
+		users:  users,
+		flags:  flags,
+		trm:    trm,
+		cfg:    cfg,
+	}
+}
+
+func (u *Usecase) Award(ctx context.Context, trigger model.PlayTrigger) (int64, error) {
+	var total int64
+	return total, u.trm.Do(ctx, func(ctx context.Context) error {
+		entries, err := u.entries(ctx, trigger)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+
+		inserted, err := u.ledger.InsertEntries(ctx, entries)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range inserted {
+			total += entry.Amount
+		}
+
+		return nil
+	})
+}
+
+func (u *Usecase) GetLevel(ctx context.Context, userID uuid.UUID) (model.PlayerLevel, error) {
+	xp, err := u.ledger.GetTotalXP(ctx, []uuid.UUID{userID}, nil)
+	if err != nil {
+		return model.PlayerLevel{}, err
+	}
+
+	return u.cfg.LevelCurve.Level(xp[userID]), nil
+}
+
+func (u *Usecase) GetChatLeaderboard(
+	ctx context.Context,
+	userID uuid.UUID,
+	chatID int64,
+	page model.Page,
+) (model.Leaderboard, error) {
+	if _, err := u.users.GetUserChat(ctx, userID, chatID); err != nil {
+		if errors.Is(err, contracts.ErrUserChatNotFound) {
+			return model.Leaderboard{}, contracts.ErrNotChatMember
+		}
+
+		return model.Leaderboard{}, err
+	}
+
+	members, err := u.users.GetChatMembers(ctx, chatID)
+	if err != nil {
+		return model.Leaderboard{}, err
+	}
+
+	// Опыт за помеченные античитом попытки остается у игрока, но в рейтинг не идет
+	flagged, err := u.flags.GetFlaggedSessions(ctx, members)
+	if err != nil {
+		return model.Leaderboard{}, err
+	}
+
+	xp, err := u.ledger.GetTotalXP(ctx, members, flagged)
+	if err != nil {
+		return model.Leaderboard{}, err
+	}
+
+	// Равный опыт — общий ранг, порядок внутри него стабилен по идентификатору
+	sort.Slice(members, func(i, j int) bool {
+		if xp[members[i]] != xp[members[j]] {
+			return xp[members[i]] > xp[members[j]]
+		}
+
+		return members[i].String() < members[j].String()
+	})
+
+	items := make([]model.LeaderboardEntry, 0, len(members))
+	for i, member := range members {
+		rank := int64(i + 1)
+		if i > 0 && xp[member] == xp[members[i-1]] {
+			rank = items[i-1].Rank
+		}
+
+		items = append(items, model.LeaderboardEntry{
+			Rank:        rank,
+			UserID:      member,
+			PlayerLevel: u.cfg.LevelCurve.Level(xp[member]),
+		})
+	}
+
+	result := model.Leaderboard{Items: []model.LeaderboardEntry{}, Total: int64(len(items))}
+	if page.Offset < int64(len(items)) {
+		end := min(page.Offset+page.Limit, int64(len(items)))
+		result.Items = items[page.Offset:end]
+	}
+
+	return result, nil
+}
+
+// entries начисления за событие: правильный ответ, завершение попытки и серия ежедневных игр
+func (u *Usecase) entries(ctx context.Context, trigger model.PlayTrigger) ([]model.XPEntry, error) {
+	multiplier := int64(1)
+	if trigger.Game.Type == model.GameTypeDaily {
+		multiplier = max(u.cfg.DailyMultiplier, 1)
+	}
+
+	entry := model.XPEntry{
+		UserID:    trigger.Session.PlayerID,
+		GameID:    trigger.Game.ID,
+		SessionID: trigger.Session.ID,
+	}
+
+	switch trigger.Type {
+	case model.TriggerAnswerAccepted:
+		if trigger.Answer == nil || !trigger.Answer.IsCorrect || u.cfg.CorrectAnswerXP <= 0 {
+			return nil, nil
+		}
+
+		questionID := trigger.Answer.QuestionID
+		entry.Reason = model.XPReasonCorrectAnswer
+		entry.Amount = u.cfg.CorrectAnswerXP * multiplier
+		entry.QuestionID = &questionID
+
+		return []model.XPEntry{entry}, nil
+	case model.TriggerGameCompleted:
+		var result []model.XPEntry
+		if u.cfg.CompletionXP > 0 {
+			completion := entry
+			completion.Reason = model.XPReasonCompletion
+			completion.Amount = u.cfg.CompletionXP * multiplier
+			result = append(result, completion)
+		}
+
+		streak, err := u.streakBonus(ctx, trigger)
+		if err != nil {
+			return nil, err
+		}
+		if streak > 0 {
+			bonus := entry
+			bonus.Reason = model.XPReasonDailyStreak
+			bonus.Amount = streak
+			result = append(result, bonus)
+		}
+
+		return result, nil
+	default:
+		return nil, nil
+	}
+}
+
+func (u *Usecase) streakBonus(ctx context.Context, trigger model.PlayTrigger) (int64, error) {
+	if trigger.Game.Type != model.GameTypeDaily || trigger.Session.FinishedAt == nil || u.cfg.StreakXP <= 0 {
+		return 0, nil
+	}
+
+	lastDay := trigger.Session.FinishedAt.UTC().Truncate(24 * time.Hour)
+	days, err := u.games.GetDailyCompletionDays(ctx, trigger.Session.PlayerID, lastDay.AddDate(0, 0, -int(u.cfg.StreakCap)))
+	if err != nil {
+		return 0, err
+	}
+
+	extraDays := min(model.DailyStreak(days, lastDay)-1, u.cfg.StreakCap)
+	if extraDays <= 0 {
+		return 0, nil
+	}
+
+	return extraDays * u.cfg.StreakXP, nil
+}
@@ -0,0 +1,14 @@
+package ratelimit
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"time"
+)
+
+type (
+	repository interface {
+		Take(ctx context.Context, key string, limit model.RateLimit, now time.Time) (model.RateDecision, error)
+		DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+	}
+)
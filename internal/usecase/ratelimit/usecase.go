@@ -0,0 +1,43 @@
+package ratelimit
+
+import (
+	"context"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type (
+	Usecase struct {
+		buckets repository
+	}
+)
+
+func NewUsecase(buckets repository) contracts.RateLimitUsecase {
+	return &Usecase{
+		buckets: buckets,
+	}
+}
+
+func (u *Usecase) Allow(ctx context.Context, key string, limit model.RateLimit) (model.RateDecision, error) {
+	if limit.IsZero() {
+		return model.RateDecision{Allowed: true}, nil
+	}
+
+	return u.buckets.Take(ctx, key, limit, time.Now())
+}
+
+func (u *Usecase) Cleanup(ctx context.Context) error {
+	deleted, err := u.buckets.DeleteExpired(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if deleted > 0 {
+		logrus.Debugf("Rate limit cleanup removed %d buckets", deleted)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package user
+
+import (
+	"easy-quizy/pkg/structs"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// Config кэш пользователей перед репозиторием, нулевой размер отключает кэш
+	Config struct {
+		CacheSize int64
+		CacheTTL  time.Duration
+	}
+
+	// cache запоминает (source, external id) → user id и известные пары (user, chat),
+	// чтобы AuthMiddleware не ходил в базу на каждый запрос
+	cache struct {
+		users       *structs.TTLCache[sourceKey, uuid.UUID]
+		chats       *structs.TTLCache[chatKey, struct{}]
+		userFlights *structs.SingleFlight[sourceKey, uuid.UUID]
+		chatFlights *structs.SingleFlight[chatKey, struct{}]
+
+		// mu и generation не дают поиску, начатому до forget, вернуть в кэш старого пользователя источника
+		mu         sync.Mutex
+		generation uint64
+	}
+
+	sourceKey struct {
+		userIDext string
+		source    string
+	}
+
+	chatKey struct {
+		userID uuid.UUID
+		chatID int64
+	}
+)
+
+func newCache(cfg Config) *cache {
+	return &cache{
+		users:       structs.NewTTLCache[sourceKey, uuid.UUID](int(cfg.CacheSize), cfg.CacheTTL),
+		chats:       structs.NewTTLCache[chatKey, struct{}](int(cfg.CacheSize), cfg.CacheTTL),
+		userFlights: structs.NewSingleFlight[sourceKey, uuid.UUID](),
+		chatFlights: structs.NewSingleFlight[chatKey, struct{}](),
+	}
+}
+
+// userGeneration запоминается до чтения из базы и сверяется в setUser
+func (c *cache) userGeneration() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.generation
+}
+
+// setUser кладет пользователя источника в кэш, если с момента generation источники не забывались
+func (c *cache) setUser(key sourceKey, userID uuid.UUID, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generation != generation {
+		return
+	}
+	c.users.Set(key, userID)
+}
+
+// forgetUsers убирает источники из кэша и отменяет запись поисков, которые уже идут
+func (c *cache) forgetUsers(keys []sourceKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	for _, key := range keys {
+		c.users.Delete(key)
+	}
+}
+
+// forgetAllUsers очищает кэш источников, когда неизвестно, какие из них сменили пользователя
+func (c *cache) forgetAllUsers() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.users.Clear()
+}
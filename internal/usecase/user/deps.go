@@ -9,9 +9,11 @@ import (
 
 type (
 	repository interface {
-		InsertSource(ctx context.Context, user model.UserSource) error
+		CreateSource(ctx context.Context, user model.UserSource) (model.User, error)
 		InsertUserChat(ctx context.Context, user model.UserChat) error
 		GetUserBySource(ctx context.Context, userIDext string, source string) (model.User, error)
 		GetUserChat(ctx context.Context, userID uuid.UUID, chatID int64) (model.UserChat, error)
+		GetUserSources(ctx context.Context, userID uuid.UUID) ([]model.UserSource, error)
+		GetUserChats(ctx context.Context, userID uuid.UUID) ([]model.UserChat, error)
 	}
 )
@@ -0,0 +1,30 @@
+package user
+
+import (
+	"context"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+
+	"github.com/google/uuid"
+)
+
+func (u *Usecase) GetProfile(ctx context.Context, userID uuid.UUID) (model.Profile, error) {
+	sources, err := u.repository.GetUserSources(ctx, userID)
+	if err != nil {
+		return model.Profile{}, err
+	}
+	if len(sources) == 0 {
+		return model.Profile{}, contracts.ErrUserNotFound
+	}
+
+	chats, err := u.repository.GetUserChats(ctx, userID)
+	if err != nil {
+		return model.Profile{}, err
+	}
+
+	return model.Profile{
+		User:    model.User{ID: userID},
+		Sources: sources,
+		Chats:   chats,
+	}, nil
+}
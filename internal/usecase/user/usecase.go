@@ -13,71 +13,121 @@ import (
 type Usecase struct {
 	trm        trm.Manager
 	repository repository
+	cache      *cache
 }
 
 func NewUsecase(
 	repository repository,
 	trm trm.Manager,
+	cfg Config,
 ) *Usecase {
 	return &Usecase{
 		trm:        trm,
 		repository: repository,
+		cache:      newCache(cfg),
 	}
 }
 
 func (u *Usecase) RetrieveUser(ctx context.Context, data contracts.UserData) (model.User, error) {
-	var user model.User
-	err := u.trm.Do(ctx, func(ctx context.Context) error {
-		var err error
-		user, err = u.repository.GetUserBySource(ctx, data.UserIDext, data.Source)
-		if err != nil && !errors.Is(err, contracts.ErrUserNotFound) {
-			return err
+	userID, err := u.retrieveUserID(ctx, data.UserIDext, data.Source)
+	if err != nil {
+		return model.User{}, err
+	}
+	user := model.User{ID: userID}
+
+	if data.ChatID != nil && data.ChatType != nil {
+		if err = u.retrieveUserChat(ctx, user, *data.ChatID, *data.ChatType); err != nil {
+			return model.User{}, err
 		}
+	}
+
+	return user, nil
+}
+
+// retrieveUserID находит или создает пользователя; одновременные первые запросы одного игрока
+// схлопываются в один, а результат попадает в кэш только после коммита
+func (u *Usecase) retrieveUserID(ctx context.Context, userIDext string, source string) (uuid.UUID, error) {
+	key := sourceKey{userIDext: userIDext, source: source}
+	if userID, ok := u.cache.users.Get(key); ok {
+		return userID, nil
+	}
 
-		if errors.Is(err, contracts.ErrUserNotFound) {
-			user = model.User{
-				ID: uuid.New(),
+	// Отмена запроса, начавшего поиск, не должна ломать ожидающих его результат
+	ctx = context.WithoutCancel(ctx)
+	userID, err, _ := u.cache.userFlights.Do(key, func() (uuid.UUID, error) {
+		// Поколение берется до чтения: привязка, закоммиченная после него, не даст записать старый id
+		generation := u.cache.userGeneration()
+
+		var user model.User
+		err := u.trm.Do(ctx, func(ctx context.Context) error {
+			var err error
+			user, err = u.repository.GetUserBySource(ctx, userIDext, source)
+			if !errors.Is(err, contracts.ErrUserNotFound) {
+				return err
 			}
 
-			return u.repository.InsertSource(ctx, model.UserSource{
-				User:   user,
-				IDext:  data.UserIDext,
-				Source: data.Source,
+			// Параллельная реплика могла создать источник раньше, тогда вернется сохраненный ею id
+			user, err = u.repository.CreateSource(ctx, model.UserSource{
+				User:   model.User{ID: uuid.New()},
+				IDext:  userIDext,
+				Source: source,
 			})
+			return err
+		})
+		if err != nil {
+			return uuid.Nil, err
 		}
 
-		return nil
+		u.cache.setUser(key, user.ID, generation)
+		return user.ID, nil
 	})
-	if err != nil {
-		return model.User{}, err
+
+	return userID, err
+}
+
+func (u *Usecase) retrieveUserChat(ctx context.Context, user model.User, chatID int64, chatType string) error {
+	key := chatKey{userID: user.ID, chatID: chatID}
+	if _, ok := u.cache.chats.Get(key); ok {
+		return nil
 	}
 
-	if data.ChatID != nil && data.ChatType != nil {
+	ctx = context.WithoutCancel(ctx)
+	_, err, _ := u.cache.chatFlights.Do(key, func() (struct{}, error) {
 		err := u.trm.Do(ctx, func(ctx context.Context) error {
-			_, err := u.repository.GetUserChat(ctx, user.ID, *data.ChatID)
-			if err != nil && !errors.Is(err, contracts.ErrUserChatNotFound) {
+			_, err := u.repository.GetUserChat(ctx, user.ID, chatID)
+			if !errors.Is(err, contracts.ErrUserChatNotFound) {
 				return err
 			}
 
-			if errors.Is(err, contracts.ErrUserChatNotFound) {
-				err = u.repository.InsertUserChat(ctx, model.UserChat{
-					User:     user,
-					ChatID:   *data.ChatID,
-					ChatType: *data.ChatType,
-				})
-
-				if err != nil {
-					return err
-				}
-			}
-
-			return nil
+			return u.repository.InsertUserChat(ctx, model.UserChat{
+				User:     user,
+				ChatID:   chatID,
+				ChatType: chatType,
+			})
 		})
 		if err != nil {
-			return model.User{}, err
+			return struct{}{}, err
 		}
 
+		u.cache.chats.Set(key, struct{}{})
+		return struct{}{}, nil
+	})
+
+	return err
+}
+
+// Forget убирает источники из кэша после того, как они сменили пользователя.
+// Поиск, начатый до этого, свой результат в кэш уже не запишет
+func (u *Usecase) Forget(sources []model.UserSource) {
+	keys := make([]sourceKey, 0, len(sources))
+	for _, source := range sources {
+		keys = append(keys, sourceKey{userIDext: source.IDext, source: source.Source})
 	}
 
-	return user, nil
+	u.cache.forgetUsers(keys)
+}
+
+// ForgetAll очищает кэш источников, например если уведомления о переносе источников могли потеряться
+func (u *Usecase) ForgetAll() {
+	u.cache.forgetAllUsers()
 }
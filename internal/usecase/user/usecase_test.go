@@ -0,0 +1,141 @@
+package user
+
+import (
+	"context"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	userRepo "easy-quizy/internal/repositories/user"
+	"easy-quizy/pkg/transaction"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// pausingRepository останавливает поиск по источнику после чтения, пока тест не отпустит его
+type pausingRepository struct {
+	*userRepo.MemoryRepository
+	read    chan struct{}
+	release chan struct{}
+}
+
+func (r *pausingRepository) GetUserBySource(ctx context.Context, userIDext string, source string) (model.User, error) {
+	user, err := r.MemoryRepository.GetUserBySource(ctx, userIDext, source)
+	if r.read != nil {
+		r.read <- struct{}{}
+		<-r.release
+	}
+
+	return user, err
+}
+
+func TestForgetDuringLookup(t *testing.T) {
+	tests := []struct {
+		name     string
+		forget   func(usecase *Usecase, source model.UserSource)
+		wantUser func(before uuid.UUID, after uuid.UUID) uuid.UUID
+	}{
+		{
+			name:     "without forget the lookup result stays cached",
+			wantUser: func(before uuid.UUID, _ uuid.UUID) uuid.UUID { return before },
+		},
+		{
+			name: "forget during lookup keeps the stale user out of the cache",
+			forget: func(usecase *Usecase, source model.UserSource) {
+				usecase.Forget([]model.UserSource{source})
+			},
+			wantUser: func(_ uuid.UUID, after uuid.UUID) uuid.UUID { return after },
+		},
+		{
+			name:     "forget all during lookup keeps the stale user out of the cache",
+			forget:   func(usecase *Usecase, _ model.UserSource) { usecase.ForgetAll() },
+			wantUser: func(_ uuid.UUID, after uuid.UUID) uuid.UUID { return after },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := &pausingRepository{
+				MemoryRepository: userRepo.NewMemoryRepository(),
+				read:             make(chan struct{}),
+				release:          make(chan struct{}),
+			}
+			usecase := NewUsecase(repo, transaction.NewNoopManager(), Config{CacheSize: 10, CacheTTL: time.Minute})
+
+			source := model.UserSource{User: model.User{ID: uuid.New()}, IDext: "42", Source: "telegram"}
+			if _, err := repo.CreateSource(ctx, source); err != nil {
+				t.Fatalf("CreateSource: %v", err)
+			}
+			data := contracts.UserData{UserIDext: source.IDext, Source: source.Source}
+
+			done := make(chan error, 1)
+			go func() {
+				_, err := usecase.RetrieveUser(ctx, data)
+				done <- err
+			}()
+
+			// Поиск уже прочитал старого пользователя, источник в это время переходит к другому
+			<-repo.read
+			after := uuid.New()
+			if err := repo.MoveSource(ctx, source, after); err != nil {
+				t.Fatalf("MoveSource: %v", err)
+			}
+			if tt.forget != nil {
+				tt.forget(usecase, source)
+			}
+			close(repo.release)
+			if err := <-done; err != nil {
+				t.Fatalf("RetrieveUser: %v", err)
+			}
+
+			repo.read = nil
+			got, err := usecase.RetrieveUser(ctx, data)
+			if err != nil {
+				t.Fatalf("RetrieveUser: %v", err)
+			}
+			if want := tt.wantUser(source.ID, after); got.ID != want {
+				t.Errorf("user = %s, want %s", got.ID, want)
+			}
+		})
+	}
+}
+
+func TestForgetAll(t *testing.T) {
+	ctx := context.Background()
+	repo := userRepo.NewMemoryRepository()
+	usecase := NewUsecase(repo, transaction.NewNoopManager(), Config{CacheSize: 10, CacheTTL: time.Minute})
+
+	// Кэш наполняется источниками двух игроков, потом оба источника переходят к другим пользователям
+	sources := []model.UserSource{
+		{User: model.User{ID: uuid.New()}, IDext: "42", Source: "telegram"},
+		{User: model.User{ID: uuid.New()}, IDext: "43", Source: "vk"},
+	}
+	afters := make([]uuid.UUID, 0, len(sources))
+	for _, source := range sources {
+		if _, err := repo.CreateSource(ctx, source); err != nil {
+			t.Fatalf("CreateSource: %v", err)
+		}
+		if _, err := usecase.RetrieveUser(ctx, contracts.UserData{UserIDext: source.IDext, Source: source.Source}); err != nil {
+			t.Fatalf("RetrieveUser: %v", err)
+		}
+
+		after := uuid.New()
+		if err := repo.MoveSource(ctx, source, after); err != nil {
+			t.Fatalf("MoveSource: %v", err)
+		}
+		afters = append(afters, after)
+	}
+
+	usecase.ForgetAll()
+
+	for i, source := range sources {
+		got, err := usecase.RetrieveUser(ctx, contracts.UserData{UserIDext: source.IDext, Source: source.Source})
+		if err != nil {
+			t.Fatalf("RetrieveUser: %v", err)
+		}
+		if got.ID != afters[i] {
+			t.Errorf("user of %s = %s, want %s", source.Source, got.ID, afters[i])
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// batchSize сколько доставок выполняется за один вызов DeliverPending
+	batchSize = 50
+
+	retryBaseDelay = 5 * time.Second
+	retryMaxDelay  = time.Hour
+
+	// responseSnippet сколько байт ответа партнера сохраняется в журнал при ошибке
+	responseSnippet = 512
+
+	HeaderEvent     = "X-Easy-Quizy-Event"
+	HeaderDelivery  = "X-Easy-Quizy-Delivery"
+	HeaderTimestamp = "X-Easy-Quizy-Timestamp"
+	// HeaderSignature "sha256=" + hex(HMAC-SHA256(secret, timestamp + "." + body))
+	HeaderSignature = "X-Easy-Quizy-Signature"
+)
+
+func (u *Usecase) DeliverPending(ctx context.Context) error {
+	for range batchSize {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		delivered, err := u.deliverNext(ctx)
+		if err != nil {
+			return err
+		}
+		if !delivered {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// deliverNext забирает доставку с арендой на время запроса: если процесс упадет посреди запроса,
+// доставка вернется в очередь после истечения аренды
+func (u *Usecase) deliverNext(ctx context.Context) (bool, error) {
+	now := time.Now()
+	delivery, err := u.webhooks.ClaimDelivery(ctx, now, now.Add(2*u.cfg.Timeout))
+	if errors.Is(err, contracts.ErrNoPendingDelivery) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	webhook, err := u.webhooks.GetWebhook(ctx, delivery.WebhookID)
+	if err != nil {
+		return true, err
+	}
+	if !webhook.Active {
+		return true, u.webhooks.MarkFailed(ctx, delivery.ID, nil, "webhook is disabled")
+	}
+
+	statusCode, sendErr := u.send(ctx, webhook, delivery)
+	if sendErr == nil {
+		return true, u.webhooks.MarkDelivered(ctx, delivery.ID, *statusCode, time.Now())
+	}
+
+	attempts := delivery.Attempts + 1
+	if attempts >= u.cfg.MaxAttempts {
+		logrus.Errorf("Webhook delivery %d to %s failed after %d attempts: %v", delivery.ID, webhook.URL, attempts, sendErr)
+		return true, u.webhooks.MarkFailed(ctx, delivery.ID, statusCode, sendErr.Error())
+	}
+
+	logrus.Warnf("Webhook delivery %d to %s failed, attempt %d: %v", delivery.ID, webhook.URL, attempts, sendErr)
+	return true, u.webhooks.ScheduleRetry(ctx, delivery.ID, time.Now().Add(retryDelay(attempts)), statusCode, sendErr.Error())
+}
+
+// send отправляет тело доставки, успехом считается любой ответ 2xx
+func (u *Usecase) send(ctx context.Context, webhook model.Webhook, delivery model.WebhookDelivery) (*int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Body))
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(delivery.EventType))
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, timestamp, delivery.Body))
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	statusCode := int64(resp.StatusCode)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, responseSnippet))
+		return &statusCode, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(snippet))
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return &statusCode, nil
+}
+
+// Sign подпись запроса: партнер проверяет ее тем же секретом, отметка времени защищает от повторной отправки
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func retryDelay(attempts int64) time.Duration {
+	delay := retryBaseDelay
+	for i := int64(1); i < attempts && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, retryMaxDelay)
+}
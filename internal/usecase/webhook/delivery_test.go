@@ -0,0 +1,216 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	gameRepo "easy-quizy/internal/repositories/game"
+	webhookRepo "easy-quizy/internal/repositories/webhook"
+	"easy-quizy/pkg/transaction"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const testSecret = "partner-secret"
+
+type capturedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// newPartner сервер партнера, который отвечает статусом status и запоминает запросы
+func newPartner(t *testing.T, status int) (*httptest.Server, chan capturedRequest) {
+	t.Helper()
+
+	requests := make(chan capturedRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- capturedRequest{header: r.Header.Clone(), body: body}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("partner says hi"))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, requests
+}
+
+// newTestUsecase usecase на репозиториях в памяти с одним вебхуком на партнера url
+func newTestUsecase(t *testing.T, url string, maxAttempts int64) (*Usecase, *webhookRepo.MemoryRepository, model.Webhook) {
+	t.Helper()
+
+	games := gameRepo.NewMemoryRepository()
+	game := model.Game{ID: uuid.New(), Type: model.GameTypeClassic, Title: "quiz"}
+	games.AddGame(game)
+
+	webhooks := webhookRepo.NewMemoryRepository()
+	usecase := NewUsecase(webhooks, games, transaction.NewNoopManager(), Config{MaxAttempts: maxAttempts, Timeout: time.Second}).(*Usecase)
+
+	secret := testSecret
+	webhook, err := usecase.CreateWebhook(context.Background(), contracts.CreateWebhookIn{
+		GameID: game.ID,
+		URL:    url,
+		Secret: &secret,
+	})
+	if err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+
+	event, err := model.NewEvent(model.EventGameCompleted, model.GameCompletedPayload{GameID: game.ID, Score: 3})
+	if err != nil {
+		t.Fatalf("NewEvent: %v", err)
+	}
+	event.ID = 7
+	if err := usecase.HandleEvent(context.Background(), event); err != nil {
+		t.Fatalf("HandleEvent: %v", err)
+	}
+
+	return usecase, webhooks, webhook
+}
+
+func lastDelivery(t *testing.T, webhooks *webhookRepo.MemoryRepository, webhookID uuid.UUID) model.WebhookDelivery {
+	t.Helper()
+
+	deliveries, err := webhooks.GetDeliveries(context.Background(), webhookID, model.Page{Limit: 1})
+	if err != nil {
+		t.Fatalf("GetDeliveries: %v", err)
+	}
+	if len(deliveries.Items) != 1 {
+		t.Fatalf("GetDeliveries = %+v, want one delivery", deliveries)
+	}
+
+	return deliveries.Items[0]
+}
+
+func TestDeliverSignsRequest(t *testing.T) {
+	server, requests := newPartner(t, http.StatusNoContent)
+	usecase, webhooks, webhook := newTestUsecase(t, server.URL, 3)
+
+	before := time.Now().Unix()
+	if err := usecase.DeliverPending(context.Background()); err != nil {
+		t.Fatalf("DeliverPending: %v", err)
+	}
+	after := time.Now().Unix()
+	request := <-requests
+
+	timestamp := request.header.Get(HeaderTimestamp)
+	sentAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		t.Fatalf("timestamp %q is not unix seconds: %v", timestamp, err)
+	}
+
+	// Подпись считается независимо от Sign, как это сделает партнер
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(request.body)
+	wantSignature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if sentAt < before || sentAt > after {
+		t.Errorf("timestamp = %d, want the send time within %d..%d", sentAt, before, after)
+	}
+
+	delivery := lastDelivery(t, webhooks, webhook.ID)
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: HeaderSignature, want: wantSignature},
+		{header: HeaderEvent, want: string(model.EventGameCompleted)},
+		{header: HeaderDelivery, want: strconv.FormatInt(delivery.ID, 10)},
+		{header: "Content-Type", want: "application/json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := request.header.Get(tt.header); got != tt.want {
+				t.Errorf("%s = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+
+	if delivery.Status != model.DeliveryStatusSucceeded || delivery.LastStatusCode == nil || *delivery.LastStatusCode != http.StatusNoContent {
+		t.Errorf("delivery = %+v, want succeeded with status 204", delivery)
+	}
+}
+
+func TestDeliverRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		maxAttempts  int64
+		wantStatus   model.DeliveryStatus
+		wantAttempts int64
+		wantRetry    bool
+	}{
+		{name: "server error is retried with backoff", status: http.StatusBadGateway, maxAttempts: 3, wantStatus: model.DeliveryStatusPending, wantAttempts: 1, wantRetry: true},
+		{name: "client error is retried too", status: http.StatusGone, maxAttempts: 3, wantStatus: model.DeliveryStatusPending, wantAttempts: 1, wantRetry: true},
+		{name: "last attempt fails the delivery", status: http.StatusInternalServerError, maxAttempts: 1, wantStatus: model.DeliveryStatusFailed, wantAttempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newPartner(t, tt.status)
+			usecase, webhooks, webhook := newTestUsecase(t, server.URL, tt.maxAttempts)
+
+			start := time.Now()
+			if err := usecase.DeliverPending(context.Background()); err != nil {
+				t.Fatalf("DeliverPending: %v", err)
+			}
+			<-requests
+
+			delivery := lastDelivery(t, webhooks, webhook.ID)
+			if delivery.Status != tt.wantStatus || delivery.Attempts != tt.wantAttempts {
+				t.Errorf("delivery %s after %d attempts, want %s after %d", delivery.Status, delivery.Attempts, tt.wantStatus, tt.wantAttempts)
+			}
+			if delivery.LastStatusCode == nil || *delivery.LastStatusCode != int64(tt.status) {
+				t.Errorf("LastStatusCode = %v, want %d", delivery.LastStatusCode, tt.status)
+			}
+			if delivery.LastError == nil {
+				t.Error("LastError is empty, want the partner response")
+			}
+
+			if !tt.wantRetry {
+				return
+			}
+			earliest, latest := start.Add(retryDelay(1)), time.Now().Add(retryDelay(1))
+			if delivery.NextAttemptAt.Before(earliest) || delivery.NextAttemptAt.After(latest) {
+				t.Errorf("NextAttemptAt = %s, want within %s..%s", delivery.NextAttemptAt, earliest, latest)
+			}
+
+			// До срока повтора доставка не отправляется снова
+			if err := usecase.DeliverPending(context.Background()); err != nil {
+				t.Fatalf("DeliverPending: %v", err)
+			}
+			select {
+			case <-requests:
+				t.Error("delivery was sent again before its retry time")
+			default:
+			}
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int64
+		want     time.Duration
+	}{
+		{attempts: 1, want: 5 * time.Second},
+		{attempts: 2, want: 10 * time.Second},
+		{attempts: 4, want: 40 * time.Second},
+		{attempts: 20, want: retryMaxDelay},
+	}
+
+	for _, tt := range tests {
+		if got := retryDelay(tt.attempts); got != tt.want {
+			t.Errorf("retryDelay(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package webhook
+
+import (
+	"context"
+	"easy-quizy/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	repository interface {
+		InsertWebhook(ctx context.Context, webhook model.Webhook) (model.Webhook, error)
+		GetWebhook(ctx context.Context, id uuid.UUID) (model.Webhook, error)
+		GetWebhooks(ctx context.Context, gameID uuid.UUID) ([]model.Webhook, error)
+		DisableWebhook(ctx context.Context, id uuid.UUID) error
+
+		InsertDeliveries(ctx context.Context, deliveries []model.WebhookDelivery) error
+		ClaimDelivery(ctx context.Context, now, leaseUntil time.Time) (model.WebhookDelivery, error)
+		MarkDelivered(ctx context.Context, id int64, statusCode int64, deliveredAt time.Time) error
+		ScheduleRetry(ctx context.Context, id int64, nextAttemptAt time.Time, statusCode *int64, lastError string) error
+		MarkFailed(ctx context.Context, id int64, statusCode *int64, lastError string) error
+		ResetDelivery(ctx context.Context, id int64, now time.Time) (model.WebhookDelivery, error)
+		GetDeliveries(ctx context.Context, webhookID uuid.UUID, page model.Page) (model.WebhookDeliveries, error)
+	}
+
+	gameRepository interface {
+		GetGamesByIDs(ctx context.Context, ids []uuid.UUID) ([]model.Game, error)
+	}
+)
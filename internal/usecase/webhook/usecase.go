@@ -0,0 +1,212 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+	"github.com/google/uuid"
+)
+
+// secretBytes длина сгенерированного секрета подписи
+const secretBytes = 32
+
+type (
+	Config struct {
+		// MaxAttempts после стольких неудачных попыток доставка снимается с очереди
+		MaxAttempts int64
+		// Timeout ограничение на один запрос партнеру
+		Timeout time.Duration
+	}
+
+	Usecase struct {
+		webhooks repository
+		games    gameRepository
+		trm      trm.Manager
+		cfg      Config
+		client   *http.Client
+	}
+)
+
+func NewUsecase(
+	webhooks repository,
+	games gameRepository,
+	trm trm.Manager,
+	cfg Config,
+) contracts.WebhookUsecase {
+	return &Usecase{
+		webhooks: webhooks,
+		games:    games,
+		trm:      trm,
+		cfg:      cfg,
+		client:   &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+func (u *Usecase) CreateWebhook(ctx context.Context, in contracts.CreateWebhookIn) (model.Webhook, error) {
+	if err := validateURL(in.URL); err != nil {
+		return model.Webhook{}, err
+	}
+
+	eventTypes := slices.Compact(slices.Sorted(slices.Values(in.EventTypes)))
+	if len(eventTypes) == 0 {
+		eventTypes = []model.EventType{model.EventGameCompleted}
+	}
+	for _, eventType := range eventTypes {
+		if !model.IsWebhookEventType(eventType) {
+			return model.Webhook{}, fmt.Errorf("%w: %s", contracts.ErrInvalidWebhookEvent, eventType)
+		}
+	}
+
+	secret, err := newSecret(in.Secret)
+	if err != nil {
+		return model.Webhook{}, err
+	}
+
+	var result model.Webhook
+	return result, u.trm.Do(ctx, func(ctx context.Context) error {
+		games, err := u.games.GetGamesByIDs(ctx, []uuid.UUID{in.GameID})
+		if err != nil {
+			return err
+		}
+		if len(games) == 0 {
+			return contracts.ErrGameNotFound
+		}
+
+		result, err = u.webhooks.InsertWebhook(ctx, model.Webhook{
+			ID:         uuid.New(),
+			GameID:     in.GameID,
+			URL:        in.URL,
+			Secret:     secret,
+			EventTypes: eventTypes,
+		})
+		return err
+	})
+}
+
+func (u *Usecase) GetWebhooks(ctx context.Context, gameID uuid.UUID) ([]model.Webhook, error) {
+	return u.webhooks.GetWebhooks(ctx, gameID)
+}
+
+func (u *Usecase) DisableWebhook(ctx context.Context, webhookID uuid.UUID) error {
+	return u.webhooks.DisableWebhook(ctx, webhookID)
+}
+
+func (u *Usecase) GetDeliveries(
+	ctx context.Context,
+	webhookID uuid.UUID,
+	page model.Page,
+) (model.WebhookDeliveries, error) {
+	var result model.WebhookDeliveries
+	return result, u.trm.Do(ctx, func(ctx context.Context) error {
+		if _, err := u.webhooks.GetWebhook(ctx, webhookID); err != nil {
+			return err
+		}
+
+		var err error
+		result, err = u.webhooks.GetDeliveries(ctx, webhookID, page.Normalize())
+		return err
+	})
+}
+
+func (u *Usecase) ReplayDelivery(ctx context.Context, deliveryID int64) (model.WebhookDelivery, error) {
+	return u.webhooks.ResetDelivery(ctx, deliveryID, time.Now())
+}
+
+// HandleEvent выполняется в транзакции диспетчера outbox: доставки создаются ровно один раз на событие,
+// а сами запросы партнерам отправляет DeliverPending вне транзакции
+func (u *Usecase) HandleEvent(ctx context.Context, event model.Event) error {
+	var payload model.GameCompletedPayload
+	if err := event.Decode(&payload); err != nil {
+		return fmt.Errorf("decode %s payload: %w", event.Type, err)
+	}
+
+	webhooks, err := u.webhooks.GetWebhooks(ctx, payload.GameID)
+	if err != nil {
+		return err
+	}
+
+	deliveries := make([]model.WebhookDelivery, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		if !webhook.IsSubscribed(event.Type) {
+			continue
+		}
+
+		body, err := json.Marshal(model.WebhookBody{
+			EventID:   event.ID,
+			Type:      event.Type,
+			CreatedAt: event.CreatedAt,
+			Data: model.WebhookCompleted{
+				GameID:     payload.GameID,
+				GameType:   payload.GameType,
+				PlayerID:   playerAlias(webhook, payload.PlayerID),
+				SessionID:  payload.SessionID,
+				Attempt:    payload.Attempt,
+				Score:      payload.Score,
+				ResultText: payload.ResultText,
+				StartedAt:  payload.StartedAt,
+				FinishedAt: payload.FinishedAt,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		deliveries = append(deliveries, model.WebhookDelivery{
+			WebhookID: webhook.ID,
+			EventID:   event.ID,
+			EventType: event.Type,
+			Body:      body,
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	return u.webhooks.InsertDeliveries(ctx, deliveries)
+}
+
+// playerAlias псевдоним игрока для партнера: HMAC внутреннего id на секрете вебхука. Он постоянен для
+// вебхука, но не совпадает у разных партнеров и не раскрывает сам id.
+func playerAlias(webhook model.Webhook, playerID uuid.UUID) string {
+	mac := hmac.New(sha256.New, []byte(webhook.Secret))
+	mac.Write(playerID[:])
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func validateURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil || !parsed.IsAbs() || parsed.Host == "" {
+		return contracts.ErrInvalidWebhookURL
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return contracts.ErrInvalidWebhookURL
+	}
+
+	return nil
+}
+
+func newSecret(secret *string) (string, error) {
+	if secret != nil && *secret != "" {
+		return *secret, nil
+	}
+
+	buf := make([]byte, secretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
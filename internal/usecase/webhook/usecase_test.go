@@ -0,0 +1,79 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"easy-quizy/internal/contracts"
+	"easy-quizy/internal/model"
+	gameRepo "easy-quizy/internal/repositories/game"
+	webhookRepo "easy-quizy/internal/repositories/webhook"
+	"easy-quizy/pkg/transaction"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestHandleEventPlayerAlias(t *testing.T) {
+	ctx := context.Background()
+	games := gameRepo.NewMemoryRepository()
+	game := model.Game{ID: uuid.New(), Type: model.GameTypeClassic, Title: "quiz"}
+	games.AddGame(game)
+
+	webhooks := webhookRepo.NewMemoryRepository()
+	usecase := NewUsecase(webhooks, games, transaction.NewNoopManager(), Config{MaxAttempts: 3, Timeout: time.Second}).(*Usecase)
+
+	var partners []model.Webhook
+	for _, secret := range []string{"first-partner", "second-partner"} {
+		webhook, err := usecase.CreateWebhook(ctx, contracts.CreateWebhookIn{GameID: game.ID, URL: "https://partner.example/hook", Secret: &secret})
+		if err != nil {
+			t.Fatalf("CreateWebhook: %v", err)
+		}
+		partners = append(partners, webhook)
+	}
+
+	alice, bob := uuid.New(), uuid.New()
+	for i, playerID := range []uuid.UUID{alice, alice, bob} {
+		event, err := model.NewEvent(model.EventGameCompleted, model.GameCompletedPayload{GameID: game.ID, PlayerID: playerID, Score: 3})
+		if err != nil {
+			t.Fatalf("NewEvent: %v", err)
+		}
+		event.ID = int64(i + 1)
+		if err := usecase.HandleEvent(ctx, event); err != nil {
+			t.Fatalf("HandleEvent: %v", err)
+		}
+	}
+
+	// aliases[i][eventID] псевдоним игрока в доставке события вебхуку i
+	aliases := make([]map[int64]string, len(partners))
+	for i, webhook := range partners {
+		deliveries, err := webhooks.GetDeliveries(ctx, webhook.ID, model.Page{Limit: 10})
+		if err != nil {
+			t.Fatalf("GetDeliveries: %v", err)
+		}
+
+		aliases[i] = make(map[int64]string)
+		for _, delivery := range deliveries.Items {
+			if bytes.Contains(delivery.Body, []byte(alice.String())) || bytes.Contains(delivery.Body, []byte(bob.String())) {
+				t.Errorf("delivery %d body %s contains an internal player id", delivery.ID, delivery.Body)
+			}
+
+			var body model.WebhookBody
+			if err := json.Unmarshal(delivery.Body, &body); err != nil {
+				t.Fatalf("unmarshal body: %v", err)
+			}
+			aliases[i][body.EventID] = body.Data.PlayerID
+		}
+	}
+
+	if aliases[0][1] == "" || aliases[0][1] != aliases[0][2] {
+		t.Errorf("aliases of one player = %q and %q, want one non-empty alias", aliases[0][1], aliases[0][2])
+	}
+	if aliases[0][1] == aliases[0][3] {
+		t.Errorf("aliases of two players = %q, want different", aliases[0][1])
+	}
+	if aliases[0][1] == aliases[1][1] {
+		t.Errorf("aliases of one player for two webhooks = %q, want different", aliases[0][1])
+	}
+}
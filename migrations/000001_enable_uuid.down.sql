@@ -0,0 +1,2 @@
+-- uuid-ossp may be used by other schemas of the same database, so it is kept on rollback
+select 1;
@@ -0,0 +1 @@
+drop table if exists easy_quizy_game;
\ No newline at end of file
@@ -0,0 +1 @@
+drop table if exists easy_quizy_game_session;
\ No newline at end of file
@@ -1,4 +1,4 @@
-create table if not exists game_session (
+create table if not exists easy_quizy_game_session (
     id bigint generated by default as identity primary key not null,
     game_id UUID not null,
     player_id UUID not null,
@@ -7,5 +7,5 @@ create table if not exists game_session (
     is_correct bool not null,
     created_at TIMESTAMPTZ not null default NOW(),
 
-    foreign key (game_id) references game (id)
+    foreign key (game_id) references easy_quizy_game (id)
 )
\ No newline at end of file
@@ -0,0 +1 @@
+alter table easy_quizy_game drop column if exists "type";
\ No newline at end of file
@@ -1 +1 @@
-alter table game add column if not exists "type" text default 'classic';
\ No newline at end of file
+alter table easy_quizy_game add column if not exists "type" text default 'classic';
\ No newline at end of file
@@ -0,0 +1 @@
+drop table if exists easy_quizy_game_daily;
\ No newline at end of file
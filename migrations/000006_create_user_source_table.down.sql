@@ -0,0 +1 @@
+drop table if exists easy_quizy_user_source;
\ No newline at end of file
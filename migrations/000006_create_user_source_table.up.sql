@@ -1,4 +1,4 @@
-create table if not exists user_source (
+create table if not exists easy_quizy_user_source (
     id bigint generated by default as identity primary key not null,
     user_id_int UUID not null,
     user_id_ext text not null,
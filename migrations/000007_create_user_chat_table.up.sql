@@ -1,4 +1,4 @@
-create table if not exists user_chat (
+create table if not exists easy_quizy_user_chat (
     id bigint generated by default as identity primary key not null,
     user_id UUID not null,
     chat_id bigint not null,
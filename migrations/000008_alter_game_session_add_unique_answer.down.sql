@@ -0,0 +1,3 @@
+drop index if exists easy_quizy_game_session_idempotency_key_uidx;
+drop index if exists easy_quizy_game_session_answer_uidx;
+alter table easy_quizy_game_session drop column if exists idempotency_key;
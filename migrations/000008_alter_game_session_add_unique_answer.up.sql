@@ -0,0 +1,16 @@
+-- keep the first recorded answer for every question, later duplicates come from double submits
+delete from easy_quizy_game_session s
+using easy_quizy_game_session d
+where s.game_id = d.game_id
+  and s.player_id = d.player_id
+  and s.question_id = d.question_id
+  and s.id > d.id;
+
+alter table easy_quizy_game_session add column if not exists idempotency_key text default null;
+
+create unique index if not exists easy_quizy_game_session_answer_uidx
+    on easy_quizy_game_session (game_id, player_id, question_id);
+
+create unique index if not exists easy_quizy_game_session_idempotency_key_uidx
+    on easy_quizy_game_session (player_id, idempotency_key)
+    where idempotency_key is not null;
@@ -0,0 +1,19 @@
+-- only the latest attempt survives the rollback, the old schema keeps one answer per question
+delete from easy_quizy_game_session a
+using easy_quizy_session s
+where s.id = a.session_id
+  and exists (
+      select 1 from easy_quizy_session newer
+      where newer.game_id = s.game_id
+        and newer.player_id = s.player_id
+        and newer.attempt > s.attempt
+  );
+
+drop index if exists easy_quizy_game_session_answer_uidx;
+create unique index if not exists easy_quizy_game_session_answer_uidx
+    on easy_quizy_game_session (game_id, player_id, question_id);
+
+alter table easy_quizy_game_session drop constraint if exists fk_game_session_session;
+alter table easy_quizy_game_session drop column if exists session_id;
+
+drop table if exists easy_quizy_session;
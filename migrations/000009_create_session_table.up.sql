@@ -0,0 +1,45 @@
+create table if not exists easy_quizy_session (
+    id UUID primary key not null,
+    game_id UUID not null,
+    player_id UUID not null,
+    attempt int not null,
+    started_at TIMESTAMPTZ not null default NOW(),
+    finished_at TIMESTAMPTZ default null,
+    score bigint default null,
+    result_text text default null,
+
+    foreign key (game_id) references easy_quizy_game (id),
+    constraint unique_session_attempt unique (game_id, player_id, attempt)
+);
+
+create index if not exists easy_quizy_session_player_idx on easy_quizy_session (player_id, started_at desc);
+
+-- every player already had exactly one attempt per game, it is finished when all questions are answered
+insert into easy_quizy_session (id, game_id, player_id, attempt, started_at, finished_at, score)
+select
+    uuid_generate_v4(),
+    a.game_id,
+    a.player_id,
+    1,
+    min(a.created_at),
+    case when count(*) >= jsonb_array_length(g.payload -> 'questions') then max(a.created_at) end,
+    case when count(*) >= jsonb_array_length(g.payload -> 'questions') then count(*) filter (where a.is_correct) end
+from easy_quizy_game_session a
+inner join easy_quizy_game g on g.id = a.game_id
+group by a.game_id, a.player_id, g.payload;
+
+alter table easy_quizy_game_session add column if not exists session_id UUID;
+
+update easy_quizy_game_session a
+set session_id = s.id
+from easy_quizy_session s
+where s.game_id = a.game_id and s.player_id = a.player_id;
+
+alter table easy_quizy_game_session alter column session_id set not null;
+alter table easy_quizy_game_session
+    add constraint fk_game_session_session foreign key (session_id) references easy_quizy_session (id);
+
+-- answers are unique per attempt now, a new attempt answers the same questions again
+drop index if exists easy_quizy_game_session_answer_uidx;
+create unique index if not exists easy_quizy_game_session_answer_uidx
+    on easy_quizy_game_session (session_id, question_id);
@@ -0,0 +1,6 @@
+drop index if exists easy_quizy_game_created_idx;
+drop index if exists easy_quizy_game_category_idx;
+drop index if exists easy_quizy_game_tags_idx;
+drop index if exists easy_quizy_game_search_idx;
+
+alter table easy_quizy_game drop column if exists search;
@@ -0,0 +1,11 @@
+-- search vector over the quiz title and description, 'simple' config because quizzes are written in several languages
+alter table easy_quizy_game add column if not exists search tsvector
+    generated always as (
+        setweight(to_tsvector('simple', coalesce(payload ->> 'name', '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(payload ->> 'description', '')), 'B')
+    ) stored;
+
+create index if not exists easy_quizy_game_search_idx on easy_quizy_game using gin (search);
+create index if not exists easy_quizy_game_tags_idx on easy_quizy_game using gin ((payload -> 'tags'));
+create index if not exists easy_quizy_game_category_idx on easy_quizy_game ((payload ->> 'category'));
+create index if not exists easy_quizy_game_created_idx on easy_quizy_game (created_at desc, id);
@@ -0,0 +1,2 @@
+drop table if exists easy_quizy_question_flag;
+drop table if exists easy_quizy_game_rating;
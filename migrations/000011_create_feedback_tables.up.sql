@@ -0,0 +1,26 @@
+create table if not exists easy_quizy_game_rating (
+    game_id UUID not null,
+    player_id UUID not null,
+    rating smallint not null check (rating between 1 and 5),
+    created_at TIMESTAMPTZ not null default NOW(),
+    updated_at TIMESTAMPTZ not null default NOW(),
+
+    primary key (game_id, player_id),
+    foreign key (game_id) references easy_quizy_game (id)
+);
+
+create table if not exists easy_quizy_question_flag (
+    id bigint generated by default as identity primary key not null,
+    game_id UUID not null,
+    player_id UUID not null,
+    question_id bigint not null,
+    reason text not null check (reason in ('wrong', 'ambiguous')),
+    comment text default null,
+    created_at TIMESTAMPTZ not null default NOW(),
+    updated_at TIMESTAMPTZ not null default NOW(),
+
+    foreign key (game_id) references easy_quizy_game (id),
+    constraint unique_question_flag unique (game_id, player_id, question_id)
+);
+
+create index if not exists easy_quizy_question_flag_question_idx on easy_quizy_question_flag (game_id, question_id);
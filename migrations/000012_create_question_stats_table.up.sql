@@ -0,0 +1,15 @@
+-- precomputed per-question analytics, fully rebuilt by the analytics job
+create table if not exists easy_quizy_question_stats (
+    game_id UUID not null,
+    question_id bigint not null,
+    option_counts jsonb not null default '{}',
+    answers bigint not null default 0,
+    correct bigint not null default 0,
+    median_answer_seconds float8 default null,
+    reached bigint not null default 0,
+    dropped bigint not null default 0,
+    computed_at TIMESTAMPTZ not null default NOW(),
+
+    primary key (game_id, question_id),
+    foreign key (game_id) references easy_quizy_game (id)
+);
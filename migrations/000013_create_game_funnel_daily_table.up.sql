@@ -0,0 +1,15 @@
+-- daily funnel rollup per game, days are UTC dates; recent days are rebuilt by the analytics job
+create table if not exists easy_quizy_game_funnel_daily (
+    game_id UUID not null,
+    day date not null,
+    opened bigint not null default 0,
+    answered bigint not null default 0,
+    completed bigint not null default 0,
+    resets bigint not null default 0,
+    computed_at TIMESTAMPTZ not null default NOW(),
+
+    primary key (game_id, day),
+    foreign key (game_id) references easy_quizy_game (id)
+);
+
+create index if not exists easy_quizy_game_funnel_daily_day_idx on easy_quizy_game_funnel_daily (day);
@@ -0,0 +1,16 @@
+-- transactional outbox: events are written in the same transaction as the change that caused them
+create table if not exists easy_quizy_outbox (
+    id bigint generated by default as identity primary key not null,
+    event_type text not null,
+    payload jsonb not null,
+    created_at TIMESTAMPTZ not null default NOW(),
+    available_at TIMESTAMPTZ not null default NOW(),
+    attempts int not null default 0,
+    last_error text default null,
+    processed_at TIMESTAMPTZ default null,
+    failed_at TIMESTAMPTZ default null
+);
+
+create index if not exists easy_quizy_outbox_pending_idx
+    on easy_quizy_outbox (available_at, id)
+    where processed_at is null and failed_at is null;
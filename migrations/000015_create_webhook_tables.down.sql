@@ -0,0 +1,2 @@
+drop table if exists easy_quizy_webhook_delivery;
+drop table if exists easy_quizy_webhook;
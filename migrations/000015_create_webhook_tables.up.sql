@@ -0,0 +1,38 @@
+create table if not exists easy_quizy_webhook (
+    id UUID primary key not null,
+    game_id UUID not null,
+    url text not null,
+    secret text not null,
+    event_types text[] not null,
+    active boolean not null default true,
+    created_at TIMESTAMPTZ not null default NOW(),
+
+    foreign key (game_id) references easy_quizy_game (id)
+);
+
+create index if not exists easy_quizy_webhook_game_idx on easy_quizy_webhook (game_id) where active;
+
+-- delivery log: one row per webhook and outbox event, the body is frozen so replays send the same payload
+create table if not exists easy_quizy_webhook_delivery (
+    id bigint generated by default as identity primary key not null,
+    webhook_id UUID not null,
+    event_id bigint not null,
+    event_type text not null,
+    body jsonb not null,
+    status text not null default 'pending' check (status in ('pending', 'succeeded', 'failed')),
+    attempts int not null default 0,
+    next_attempt_at TIMESTAMPTZ not null default NOW(),
+    last_status_code int default null,
+    last_error text default null,
+    created_at TIMESTAMPTZ not null default NOW(),
+    delivered_at TIMESTAMPTZ default null,
+
+    foreign key (webhook_id) references easy_quizy_webhook (id),
+    constraint unique_webhook_delivery unique (webhook_id, event_id)
+);
+
+create index if not exists easy_quizy_webhook_delivery_pending_idx
+    on easy_quizy_webhook_delivery (next_attempt_at, id)
+    where status = 'pending';
+create index if not exists easy_quizy_webhook_delivery_webhook_idx
+    on easy_quizy_webhook_delivery (webhook_id, id desc);
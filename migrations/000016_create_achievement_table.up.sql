@@ -0,0 +1,14 @@
+-- полученные игроками достижения, каждое выдается один раз
+create table if not exists easy_quizy_user_achievement (
+    user_id UUID not null,
+    code text not null,
+    game_id UUID default null,
+    session_id UUID default null,
+    unlocked_at TIMESTAMPTZ not null default NOW(),
+
+    primary key (user_id, code)
+);
+
+create index if not exists easy_quizy_user_achievement_session_idx
+    on easy_quizy_user_achievement (session_id)
+    where session_id is not null;
@@ -0,0 +1,18 @@
+-- журнал начисления опыта, опыт игрока — сумма его записей
+create table if not exists easy_quizy_xp_ledger (
+    id bigint generated by default as identity primary key not null,
+    user_id UUID not null,
+    amount bigint not null,
+    reason text not null check (reason in ('correct_answer', 'completion', 'daily_streak')),
+    game_id UUID not null,
+    session_id UUID not null,
+    question_id bigint default null,
+    created_at TIMESTAMPTZ not null default NOW(),
+
+    foreign key (game_id) references easy_quizy_game (id)
+);
+
+-- одно начисление каждого вида на попытку и вопрос
+create unique index if not exists easy_quizy_xp_ledger_unique_idx
+    on easy_quizy_xp_ledger (session_id, reason, coalesce(question_id, -1));
+create index if not exists easy_quizy_xp_ledger_user_idx on easy_quizy_xp_ledger (user_id);
@@ -0,0 +1,16 @@
+-- использованные в попытке подсказки
+create table if not exists easy_quizy_session_lifeline (
+    id bigint generated by default as identity primary key not null,
+    session_id UUID not null,
+    question_id bigint not null,
+    lifeline text not null check (lifeline in ('fifty_fifty', 'skip', 'hint')),
+    -- варианты, убранные 50/50, чтобы после перезагрузки показать те же
+    removed_answer_ids bigint[] not null default '{}',
+    created_at TIMESTAMPTZ not null default NOW(),
+
+    foreign key (session_id) references easy_quizy_session (id)
+);
+
+-- каждая подсказка на вопрос используется не больше одного раза
+create unique index if not exists easy_quizy_session_lifeline_unique_idx
+    on easy_quizy_session_lifeline (session_id, question_id, lifeline);
@@ -0,0 +1,16 @@
+-- подозрения на нечестную игру, помеченные попытки не учитываются в рейтингах
+create table if not exists easy_quizy_session_flag (
+    id bigint generated by default as identity primary key not null,
+    session_id UUID not null,
+    user_id UUID not null,
+    game_id UUID not null,
+    reason text not null check (reason in ('answer_rate', 'fast_answer', 'reset_then_perfect', 'shared_answers')),
+    details text not null default '',
+    created_at TIMESTAMPTZ not null default NOW(),
+
+    foreign key (session_id) references easy_quizy_session (id)
+);
+
+-- одна пометка каждого вида на попытку
+create unique index if not exists easy_quizy_session_flag_unique_idx on easy_quizy_session_flag (session_id, reason);
+create index if not exists easy_quizy_session_flag_user_idx on easy_quizy_session_flag (user_id);
@@ -0,0 +1 @@
+drop table if exists easy_quizy_rate_bucket;
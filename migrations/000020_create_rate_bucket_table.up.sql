@@ -0,0 +1,10 @@
+-- бакеты ограничения частоты запросов; состояние не ценное, поэтому таблица без журнала
+create unlogged table if not exists easy_quizy_rate_bucket (
+    key text primary key not null,
+    tokens double precision not null,
+    updated_at TIMESTAMPTZ not null,
+    -- к этому моменту бакет полностью восстановится и его можно удалить
+    expires_at TIMESTAMPTZ not null
+);
+
+create index if not exists easy_quizy_rate_bucket_expires_idx on easy_quizy_rate_bucket (expires_at);
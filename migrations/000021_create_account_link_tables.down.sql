@@ -0,0 +1,2 @@
+drop table if exists easy_quizy_user_merge;
+drop table if exists easy_quizy_link_code;
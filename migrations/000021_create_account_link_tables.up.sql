@@ -0,0 +1,18 @@
+-- одноразовые коды привязки: игрок получает код и вводит его из другого источника
+create table if not exists easy_quizy_link_code (
+    code text primary key not null,
+    user_id UUID not null,
+    created_at TIMESTAMPTZ not null default NOW(),
+    expires_at TIMESTAMPTZ not null,
+    used_at TIMESTAMPTZ default null,
+    used_by UUID default null
+);
+
+create index if not exists easy_quizy_link_code_user_idx on easy_quizy_link_code (user_id);
+
+-- слитые пользователи: записи под старым id, сделанные репликами с устаревшим кэшем, находит repair-orphans
+create table if not exists easy_quizy_user_merge (
+    from_user_id UUID primary key not null,
+    to_user_id UUID not null,
+    merged_at TIMESTAMPTZ not null default NOW()
+);
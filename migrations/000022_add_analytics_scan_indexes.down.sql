@@ -0,0 +1,4 @@
+drop index if exists easy_quizy_game_session_player_idx;
+drop index if exists easy_quizy_game_session_created_idx;
+drop index if exists easy_quizy_session_finished_idx;
+drop index if exists easy_quizy_session_started_idx;
@@ -0,0 +1,6 @@
+-- индексы для пересборки аналитики только по недавним событиям
+create index if not exists easy_quizy_session_started_idx on easy_quizy_session (started_at);
+create index if not exists easy_quizy_session_finished_idx on easy_quizy_session (finished_at) where finished_at is not null;
+create index if not exists easy_quizy_game_session_created_idx on easy_quizy_game_session (created_at);
+-- первый ответ игрока в игре ищется без просмотра всех ответов
+create index if not exists easy_quizy_game_session_player_idx on easy_quizy_game_session (game_id, player_id, created_at);
@@ -0,0 +1 @@
+drop table if exists easy_quizy_outbox_subscriber;
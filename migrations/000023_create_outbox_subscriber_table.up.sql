@@ -0,0 +1,13 @@
+-- доставка события каждому подписчику отдельно: успех одного подписчика не повторяется из-за ошибки другого
+create table if not exists easy_quizy_outbox_subscriber (
+    event_id bigint not null,
+    subscriber text not null,
+    attempts int not null default 0,
+    available_at TIMESTAMPTZ not null default NOW(),
+    last_error text default null,
+    processed_at TIMESTAMPTZ default null,
+    failed_at TIMESTAMPTZ default null,
+
+    primary key (event_id, subscriber),
+    foreign key (event_id) references easy_quizy_outbox (id) on delete cascade
+);
@@ -0,0 +1 @@
+alter table easy_quizy_session drop column if exists merged_from;
@@ -0,0 +1,2 @@
+-- игрок, от которого попытка перешла при слиянии аккаунтов; такие попытки не расходуют лимит повторов
+alter table easy_quizy_session add column if not exists merged_from UUID default null;
@@ -0,0 +1,8 @@
+package migrations
+
+import "embed"
+
+// FS миграции схемы в формате golang-migrate, встроенные в бинарник сервиса
+//
+//go:embed *.sql
+var FS embed.FS
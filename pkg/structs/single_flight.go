@@ -0,0 +1,49 @@
+package structs
+
+import "sync"
+
+type (
+	// SingleFlight схлопывает одновременные вызовы с одинаковым ключом в один
+	SingleFlight[K comparable, V any] struct {
+		mu    sync.Mutex
+		calls map[K]*flightCall[V]
+	}
+
+	flightCall[V any] struct {
+		done  chan struct{}
+		value V
+		err   error
+	}
+)
+
+func NewSingleFlight[K comparable, V any]() *SingleFlight[K, V] {
+	return &SingleFlight[K, V]{calls: make(map[K]*flightCall[V])}
+}
+
+// Do выполняет fn, если для key еще нет выполняющегося вызова, иначе ждет его результат.
+// shared показывает, что результат получен от чужого вызова
+func (s *SingleFlight[K, V]) Do(key K, fn func() (V, error)) (value V, err error, shared bool) {
+	s.mu.Lock()
+	if call, ok := s.calls[key]; ok {
+		s.mu.Unlock()
+		<-call.done
+		return call.value, call.err, true
+	}
+
+	call := &flightCall[V]{done: make(chan struct{})}
+	s.calls[key] = call
+	s.mu.Unlock()
+
+	call.err = WithRecover(func() error {
+		var err error
+		call.value, err = fn()
+		return err
+	})
+
+	s.mu.Lock()
+	delete(s.calls, key)
+	s.mu.Unlock()
+	close(call.done)
+
+	return call.value, call.err, false
+}
@@ -0,0 +1,28 @@
+package transaction
+
+import (
+	"context"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+)
+
+var (
+	_ trm.Manager = &NoopManager{}
+)
+
+type (
+	// NoopManager выполняет замыкание без транзакции, для хранилищ в памяти
+	NoopManager struct{}
+)
+
+func NewNoopManager() *NoopManager {
+	return &NoopManager{}
+}
+
+func (m *NoopManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (m *NoopManager) DoWithSettings(ctx context.Context, _ trm.Settings, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
@@ -4,8 +4,7 @@ import (
 	"easy-quizy/pkg/structs"
 	"errors"
 	"fmt"
-	"os"
-	"strings"
+	"slices"
 )
 
 var (
@@ -18,9 +17,10 @@ type (
 	}
 )
 
-func NewConfiguration() (*Configuration, error) {
-	if err := validate(); err != nil {
-		return nil, fmt.Errorf("failed to validate variables: %w", err)
+// NewConfiguration валидирует все зарегистрированные переменные, кроме переменных из excludeGroups
+func NewConfiguration(excludeGroups ...string) (*Configuration, error) {
+	if err := validate(excludeGroups); err != nil {
+		return nil, fmt.Errorf("failed to validate variables:\n%w", err)
 	}
 
 	return &Configuration{
@@ -30,20 +30,31 @@ func NewConfiguration() (*Configuration, error) {
 	}, nil
 }
 
-func validate() error {
+// Effective возвращает эффективные значения всех зарегистрированных переменных в порядке объявления
+func Effective() []structs.Pair[string, string] {
+	result := make([]structs.Pair[string, string], 0, len(registry))
 	for _, v := range registry {
-		name := strings.TrimSpace(v.Name())
-		if name == "" {
-			return errEmptyVariableName
+		result = append(result, structs.Pair[string, string]{
+			Left:  v.Name(),
+			Right: v.Effective(),
+		})
+	}
+
+	return result
+}
+
+// validate проверяет все переменные сразу и возвращает полный список ошибок
+func validate(excludeGroups []string) error {
+	var errs []error
+	for _, v := range registry {
+		if v.Group() != "" && slices.Contains(excludeGroups, v.Group()) {
+			continue
 		}
 
-		if v.Type() == VariableTypeEnvironment {
-			_, ok := os.LookupEnv(name)
-			if !ok {
-				return fmt.Errorf("environment variable '%s' is absent", name)
-			}
+		if err := v.Validate(); err != nil {
+			errs = append(errs, err)
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
@@ -14,5 +14,7 @@ type (
 		GetStrings(t StringVariable) []string
 		GetDuration(t StringVariable) time.Duration
 		GetConvertedDuration(t StringVariable) (time.Duration, error)
+		GetRateLimit(t StringVariable) RateLimitValue
+		GetRateLimitRoutes(t StringVariable) map[string]RateLimitValue
 	}
 )
@@ -1,9 +1,5 @@
 package variables
 
-import (
-	"os"
-)
-
 type (
 	EnvironmentValue string
 )
@@ -27,8 +23,8 @@ var (
 )
 
 func AppEnvironment() EnvironmentValue {
-	env, exists := os.LookupEnv(AppEnvironmentVariable.name)
-	if !exists {
+	env, exists, err := lookup(AppEnvironmentVariable.name)
+	if err != nil || !exists {
 		return EnvironmentProd
 	}
 
@@ -2,6 +2,8 @@ package variables
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 	"time"
@@ -16,6 +18,12 @@ var (
 	Bool       = &boolExtractor{}
 	Duration   = &durationExtractor{}
 	Dictionary = &dictionaryExtractor{}
+	// IncreasingInt64s список положительных чисел по возрастанию, например пороги уровней
+	IncreasingInt64s = &increasingInt64sExtractor{}
+	// Rate лимит вида "30/1m", пустое значение — лимит отключен
+	Rate = &rateLimitExtractor{}
+	// RouteRates лимиты маршрутов через запятую вида "POST /api/game/:game_id/accept-answer=30/1m"
+	RouteRates = &rateLimitRoutesExtractor{}
 )
 
 type (
@@ -32,6 +40,16 @@ type (
 	stringsExtractor    struct{}
 	durationExtractor   struct{}
 	dictionaryExtractor struct{}
+
+	increasingInt64sExtractor struct{}
+	rateLimitExtractor        struct{}
+	rateLimitRoutesExtractor  struct{}
+
+	// RateLimitValue Requests запросов за Period; нулевое значение — лимит отключен
+	RateLimitValue struct {
+		Requests int64
+		Period   time.Duration
+	}
 )
 
 func (s *stringExtractor) Extract(value string) (string, error) {
@@ -78,10 +96,16 @@ func (i *int64sExtractor) TargetType() string {
 }
 
 func (i *stringsExtractor) Extract(value string) ([]string, error) {
-	if value == "" {
+	if strings.TrimSpace(value) == "" {
 		return nil, nil
 	}
-	return strings.Split(value, ","), nil
+
+	items := strings.Split(value, ",")
+	for i, item := range items {
+		items[i] = strings.TrimSpace(item)
+	}
+
+	return items, nil
 }
 
 func (i *stringsExtractor) TargetType() string {
@@ -129,3 +153,86 @@ func (d *dictionaryExtractor) Extract(value string) (map[string]any, error) {
 func (d *dictionaryExtractor) TargetType() string {
 	return "map[string]any"
 }
+
+func (i *increasingInt64sExtractor) Extract(value string) ([]int64, error) {
+	result, err := Int64s.Extract(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result) == 0 {
+		return nil, errors.New("list is empty")
+	}
+
+	prev := int64(0)
+	for _, v := range result {
+		if v <= prev {
+			return nil, errors.New("values must be positive and strictly increasing")
+		}
+		prev = v
+	}
+
+	return result, nil
+}
+
+func (i *increasingInt64sExtractor) TargetType() string {
+	return "increasing []int64"
+}
+
+func (r *rateLimitExtractor) Extract(value string) (RateLimitValue, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return RateLimitValue{}, nil
+	}
+
+	requestsStr, periodStr, ok := strings.Cut(value, "/")
+	if !ok {
+		return RateLimitValue{}, fmt.Errorf("invalid rate limit %q: expected <requests>/<period>", value)
+	}
+
+	requests, err := strconv.ParseInt(requestsStr, 10, 64)
+	if err != nil || requests <= 0 {
+		return RateLimitValue{}, fmt.Errorf("invalid rate limit %q: requests must be a positive integer", value)
+	}
+
+	period, err := time.ParseDuration(periodStr)
+	if err != nil || period <= 0 {
+		return RateLimitValue{}, fmt.Errorf("invalid rate limit %q: period must be a positive duration", value)
+	}
+
+	return RateLimitValue{Requests: requests, Period: period}, nil
+}
+
+func (r *rateLimitExtractor) TargetType() string {
+	return "<requests>/<period>"
+}
+
+// Extract ключ результата "METHOD /path", метод в верхнем регистре
+func (r *rateLimitRoutesExtractor) Extract(value string) (map[string]RateLimitValue, error) {
+	items, err := Strings.Extract(value)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]RateLimitValue, len(items))
+	for _, item := range items {
+		route, limitStr, ok := strings.Cut(strings.TrimSpace(item), "=")
+		fields := strings.Fields(route)
+		if !ok || len(fields) != 2 {
+			return nil, fmt.Errorf("invalid route rate limit %q: expected METHOD /path=<requests>/<period>", item)
+		}
+
+		limit, err := Rate.Extract(limitStr)
+		if err != nil {
+			return nil, err
+		}
+
+		result[strings.ToUpper(fields[0])+" "+fields[1]] = limit
+	}
+
+	return result, nil
+}
+
+func (r *rateLimitRoutesExtractor) TargetType() string {
+	return "METHOD /path=<requests>/<period> list"
+}
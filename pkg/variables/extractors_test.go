@@ -0,0 +1,123 @@
+package variables
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStrings(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  []string
+	}{
+		{name: "list", value: "a,b", want: []string{"a", "b"}},
+		{name: "spaces around items", value: " a, b ,c ", want: []string{"a", "b", "c"}},
+		{name: "empty", value: "", want: nil},
+		{name: "only spaces", value: "  ", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Strings.Extract(tt.value)
+			if err != nil {
+				t.Fatalf("Extract(%q) error = %v", tt.value, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Extract(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIncreasingInt64s(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []int64
+		wantErr bool
+	}{
+		{name: "increasing", value: "100,250,450", want: []int64{100, 250, 450}},
+		{name: "empty", value: "", wantErr: true},
+		{name: "not a number", value: "100,x", wantErr: true},
+		{name: "zero", value: "0,100", wantErr: true},
+		{name: "equal", value: "100,100", wantErr: true},
+		{name: "decreasing", value: "250,100", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IncreasingInt64s.Extract(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Extract(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Extract(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRate(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    RateLimitValue
+		wantErr bool
+	}{
+		{name: "limit", value: "30/1m", want: RateLimitValue{Requests: 30, Period: time.Minute}},
+		{name: "spaces", value: " 5/10s ", want: RateLimitValue{Requests: 5, Period: 10 * time.Second}},
+		{name: "empty disables", value: "", want: RateLimitValue{}},
+		{name: "no period", value: "30", wantErr: true},
+		{name: "zero requests", value: "0/1m", wantErr: true},
+		{name: "negative period", value: "30/-1m", wantErr: true},
+		{name: "bad period", value: "30/minute", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Rate.Extract(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Extract(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Fatalf("Extract(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouteRates(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    map[string]RateLimitValue
+		wantErr bool
+	}{
+		{
+			name:  "routes",
+			value: "post /api/game/:game_id/accept-answer=30/1m,POST /api/me/link=5/1m",
+			want: map[string]RateLimitValue{
+				"POST /api/game/:game_id/accept-answer": {Requests: 30, Period: time.Minute},
+				"POST /api/me/link":                     {Requests: 5, Period: time.Minute},
+			},
+		},
+		{name: "empty", value: "", want: map[string]RateLimitValue{}},
+		{name: "no limit", value: "POST /api/me/link", wantErr: true},
+		{name: "no method", value: "/api/me/link=5/1m", wantErr: true},
+		{name: "bad limit", value: "POST /api/me/link=5", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RouteRates.Extract(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Extract(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Extract(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -3,7 +3,6 @@ package variables
 import (
 	"context"
 	"fmt"
-	"os"
 	"time"
 )
 
@@ -38,8 +37,8 @@ func (d *DefaultRepository) GetBool(v DefaultVariable[bool]) bool {
 		return v.defaultValue
 	}
 
-	env, ok := os.LookupEnv(v.name)
-	if !ok {
+	env, ok, err := lookup(v.name)
+	if err != nil || !ok {
 		return v.defaultValue
 	}
 
@@ -96,6 +95,24 @@ func (d *DefaultRepository) GetDuration(v DefaultVariable[string]) time.Duration
 	return result
 }
 
+func (d *DefaultRepository) GetRateLimit(v DefaultVariable[string]) RateLimitValue {
+	result, err := getConverted[RateLimitValue](v, Rate)
+	if err != nil {
+		return RateLimitValue{}
+	}
+
+	return result
+}
+
+func (d *DefaultRepository) GetRateLimitRoutes(v DefaultVariable[string]) map[string]RateLimitValue {
+	result, err := getConverted[map[string]RateLimitValue](v, RouteRates)
+	if err != nil {
+		return nil
+	}
+
+	return result
+}
+
 func (d *DefaultRepository) GetConvertedDuration(
 	v DefaultVariable[string],
 ) (time.Duration, error) {
@@ -107,11 +124,15 @@ func getConverted[T any](v DefaultVariable[string], e extractor[T]) (T, error) {
 		t   T
 		raw string
 		ok  bool
+		err error
 	)
 
 	switch v.Type() {
 	case VariableTypeEnvironment:
-		raw, ok = os.LookupEnv(v.Name())
+		raw, ok, err = lookup(v.Name())
+		if err != nil {
+			return t, err
+		}
 	default:
 		return t, fmt.Errorf("variable type '%s' is unsupproted", v.Type())
 	}
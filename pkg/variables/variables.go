@@ -1,9 +1,21 @@
 package variables
 
-import "fmt"
+import (
+	"fmt"
+	"os"
+	"strings"
+)
 
 const (
 	VariableTypeEnvironment VariableType = "environment"
+
+	// fileSuffix суффикс переменной, в которой лежит путь до файла со значением (docker/k8s secrets)
+	fileSuffix = "_FILE"
+
+	redactedValue = "******"
+
+	// GroupDatabase переменные подключения к Postgres, не нужны при хранении в памяти
+	GroupDatabase = "database"
 )
 
 var registry []Variable
@@ -12,31 +24,135 @@ var (
 	// AppEnvironmentVariable окружение, в котором запущено приложение
 	AppEnvironmentVariable = Environment[string]("ENV", "prod")
 
-	AuthSecretKey       = Environment[string]("AUTH_SECRET_KEY", "")
-	AuthCookieBlockKey  = Environment[string]("AUTH_COOKIE_BLOCK_KEY", "")
+	AuthSecretKey       = Environment[string]("AUTH_SECRET_KEY", "", Secret())
+	AuthCookieBlockKey  = Environment[string]("AUTH_COOKIE_BLOCK_KEY", "", Secret())
 	AuthSenderFromEmail = Environment[string]("AUTH_SENDER_FROM_EMAIL", "")
 	AuthSenderHost      = Environment[string]("AUTH_SENDER_HOST", "")
 	AuthSenderPort      = Environment[string]("AUTH_SENDER_PORT", "")
 	AuthSenderUser      = Environment[string]("AUTH_SENDER_USER", "")
-	AuthSenderPassword  = Environment[string]("AUTH_SENDER_PASSWORD", "")
+	AuthSenderPassword  = Environment[string]("AUTH_SENDER_PASSWORD", "", Secret())
 
 	S3Endpoint  = Environment[string]("S3_ENDPOINT", "")
 	S3AccessKey = Environment[string]("S3_ACCESS_KEY", "")
-	S3SecretKey = Environment[string]("S3_SECRET_KEY", "")
+	S3SecretKey = Environment[string]("S3_SECRET_KEY", "", Secret())
 	S3Bucket    = Environment[string]("S3_BUCKET", "")
 	S3UseSSL    = Environment[bool]("S3_USE_SSL", false)
 
 	MetricsUser     = Environment[string]("METRICS_USER", "")
-	MetricsPassword = Environment[string]("METRICS_PASSWORD", "")
+	MetricsPassword = Environment[string]("METRICS_PASSWORD", "", Secret())
 
 	SupabaseReference = Environment[string]("SUPABASE_REFERENCE", "")
 	SupabaseAnonKey   = Environment[string]("SUPABASE_ANON_KEY", "")
+
+	ServerPort            = Environment[string]("SERVER_PORT", "8080", Check(Int64))
+	ServerReadTimeout     = Environment[string]("SERVER_READ_TIMEOUT", "15s", Check(Duration))
+	ServerWriteTimeout    = Environment[string]("SERVER_WRITE_TIMEOUT", "15s", Check(Duration))
+	ServerIdleTimeout     = Environment[string]("SERVER_IDLE_TIMEOUT", "60s", Check(Duration))
+	ServerShutdownTimeout = Environment[string]("SERVER_SHUTDOWN_TIMEOUT", "10s", Check(Duration))
+	// ServerDrainDelay сколько после остановки /readyz сервер еще принимает запросы, пока балансировщик его не исключит
+	ServerDrainDelay = Environment[string]("SERVER_DRAIN_DELAY", "5s", Check(Duration))
+	// ServerTrustedProxies адреса и подсети прокси через запятую, которым верим X-Forwarded-For; пустое значение — никому.
+	// По умолчанию это loopback: в контейнере /api проксирует SSR-сервер и передает адрес клиента в X-Forwarded-For.
+	ServerTrustedProxies = Environment[string]("SERVER_TRUSTED_PROXIES", "127.0.0.1,::1")
+
+	// CORSAllowedOrigins список разрешенных origin через запятую, пустое значение — поведение по умолчанию для режима gin
+	CORSAllowedOrigins = Environment[string]("CORS_ALLOWED_ORIGINS", "")
+
+	DBUser            = Environment[string]("DB_USER", "", Required(), InGroup(GroupDatabase))
+	DBPassword        = Environment[string]("DB_PASSWORD", "", Required(), Secret(), InGroup(GroupDatabase))
+	DBHost            = Environment[string]("DB_HOST", "", Required(), InGroup(GroupDatabase))
+	DBPort            = Environment[string]("DB_PORT", "", Required(), Check(Int64), InGroup(GroupDatabase))
+	DBName            = Environment[string]("DB_NAME", "", Required(), InGroup(GroupDatabase))
+	DBSSL             = Environment[string]("DB_SSL", "", Required(), InGroup(GroupDatabase))
+	DBMaxOpenConns    = Environment[string]("DB_MAX_OPEN_CONNS", "10", Check(Int64), InGroup(GroupDatabase))
+	DBMaxIdleConns    = Environment[string]("DB_MAX_IDLE_CONNS", "5", Check(Int64), InGroup(GroupDatabase))
+	DBConnMaxLifetime = Environment[string]("DB_CONN_MAX_LIFETIME", "30m", Check(Duration), InGroup(GroupDatabase))
+	DBConnectTimeout  = Environment[string]("DB_CONNECT_TIMEOUT", "10s", Check(Duration), InGroup(GroupDatabase))
+
+	// MigrateOnStart применять встроенные миграции при старте вместо отказа стартовать на отстающей схеме
+	MigrateOnStart = Environment[bool]("MIGRATE_ON_START", false, InGroup(GroupDatabase))
+
+	TelegramBotToken = Environment[string]("TELEGRAM_BOT_TOKEN", "", Secret())
+
+	// AnalyticsInterval период пересчета статистики по вопросам
+	AnalyticsInterval = Environment[string]("ANALYTICS_INTERVAL", "15m", Check(Duration))
+	// AnalyticsAbandonAfter через сколько без активности незавершенная попытка считается брошенной
+	AnalyticsAbandonAfter = Environment[string]("ANALYTICS_ABANDON_AFTER", "24h", Check(Duration))
+	// AnalyticsFunnelLookback за какой период задача аналитики пересобирает дневную воронку
+	AnalyticsFunnelLookback = Environment[string]("ANALYTICS_FUNNEL_LOOKBACK", "48h", Check(Duration))
+
+	// OutboxPollInterval как часто диспетчер забирает события из outbox
+	OutboxPollInterval = Environment[string]("OUTBOX_POLL_INTERVAL", "1s", Check(Duration))
+	// OutboxMaxAttempts после стольких неудачных доставок событие снимается с доставки
+	OutboxMaxAttempts = Environment[string]("OUTBOX_MAX_ATTEMPTS", "10", Check(Int64))
+	// OutboxRetention сколько хранить доставленные события
+	OutboxRetention = Environment[string]("OUTBOX_RETENTION", "168h", Check(Duration))
+
+	// WebhookPollInterval как часто отправляются доставки вебхуков
+	WebhookPollInterval = Environment[string]("WEBHOOK_POLL_INTERVAL", "1s", Check(Duration))
+	// WebhookMaxAttempts после стольких неудачных попыток доставка вебхука снимается с очереди
+	WebhookMaxAttempts = Environment[string]("WEBHOOK_MAX_ATTEMPTS", "8", Check(Int64))
+	// WebhookTimeout ограничение на один запрос к партнеру
+	WebhookTimeout = Environment[string]("WEBHOOK_TIMEOUT", "10s", Check(Duration))
+
+	// XPCorrectAnswer опыт за правильный ответ
+	XPCorrectAnswer = Environment[string]("XP_CORRECT_ANSWER", "10", Check(Int64))
+	// XPCompletion бонус опыта за завершение попытки
+	XPCompletion = Environment[string]("XP_COMPLETION", "50", Check(Int64))
+	// XPDailyMultiplier множитель опыта в ежедневной игре
+	XPDailyMultiplier = Environment[string]("XP_DAILY_MULTIPLIER", "2", Check(Int64))
+	// XPStreakBonus опыт за каждый день серии ежедневных игр сверх первого
+	XPStreakBonus = Environment[string]("XP_STREAK_BONUS", "10", Check(Int64))
+	// XPStreakCap сколько дней серии учитывается в бонусе
+	XPStreakCap = Environment[string]("XP_STREAK_CAP", "7", Check(Int64))
+	// XPLevelCurve накопленный опыт, с которого начинаются уровни 2, 3, ...
+	XPLevelCurve = Environment[string]("XP_LEVEL_CURVE", "100,250,450,700,1000,1400,1900,2500,3200,4000", Check(IncreasingInt64s))
+	// AntiCheatRateWindow окно, в котором считается частота ответов игрока
+	AntiCheatRateWindow = Environment[string]("ANTICHEAT_RATE_WINDOW", "1m", Check(Duration))
+	// AntiCheatMaxAnswers больше ответов за окно помечается, 0 отключает проверку
+	AntiCheatMaxAnswers = Environment[string]("ANTICHEAT_MAX_ANSWERS", "30", Check(Int64))
+	// AntiCheatMinAnswerTime ответ быстрее помечается, 0 отключает проверку
+	AntiCheatMinAnswerTime = Environment[string]("ANTICHEAT_MIN_ANSWER_TIME", "1s", Check(Duration))
+	// AntiCheatResetWindow окно, в котором считаются попытки перед идеальной
+	AntiCheatResetWindow = Environment[string]("ANTICHEAT_RESET_WINDOW", "1h", Check(Duration))
+	// AntiCheatMaxResets идеальная попытка после стольких попыток помечается, 0 отключает проверку
+	AntiCheatMaxResets = Environment[string]("ANTICHEAT_MAX_RESETS", "3", Check(Int64))
+	// AntiCheatSharedWindow окно, в котором ищутся совпадающие ответы соседей по чату
+	AntiCheatSharedWindow = Environment[string]("ANTICHEAT_SHARED_WINDOW", "10m", Check(Duration))
+	// AntiCheatMinSharedAnswers со скольких одинаковых неправильных ответов с соседом по чату помечается попытка, 0 отключает проверку
+	AntiCheatMinSharedAnswers = Environment[string]("ANTICHEAT_MIN_SHARED_ANSWERS", "3", Check(Int64))
+
+	// RateLimitStore где хранятся бакеты: memory — у каждой реплики свои, postgres — общие для всех реплик
+	RateLimitStore = Environment[string]("RATE_LIMIT_STORE", "memory")
+	// RateLimitIP лимит запросов с одного адреса вида "600/1m", пустое значение отключает
+	RateLimitIP = Environment[string]("RATE_LIMIT_IP", "600/1m", Check(Rate))
+	// RateLimitPlayer лимит запросов игрока по маршрутам без своего правила, пустое значение отключает
+	RateLimitPlayer = Environment[string]("RATE_LIMIT_PLAYER", "120/1m", Check(Rate))
+	// RateLimitRoutes лимиты игрока по маршрутам через запятую вида "POST /api/game/:game_id/accept-answer=30/1m"
+	RateLimitRoutes = Environment[string](
+		"RATE_LIMIT_ROUTES",
+		"POST /api/game/:game_id/accept-answer=30/1m,POST /api/game/:game_id/reset=10/1m,POST /api/me/link=5/1m",
+		Check(RouteRates),
+	)
+
+	// UserCacheSize сколько игроков и их чатов помнит кэш перед базой, 0 отключает кэш
+	UserCacheSize = Environment[string]("USER_CACHE_SIZE", "10000", Check(Int64))
+	// UserCacheTTL сколько живет запись кэша пользователей
+	UserCacheTTL = Environment[string]("USER_CACHE_TTL", "10m", Check(Duration))
+	// LinkCodeTTL сколько действует одноразовый код привязки источника
+	LinkCodeTTL = Environment[string]("LINK_CODE_TTL", "10m", Check(Duration))
+
+	// AdminToken bearer-токен админских эндпоинтов /api/admin, пустое значение отключает их
+	AdminToken = Environment[string]("ADMIN_TOKEN", "", Secret())
 )
 
 type (
 	Variable interface {
 		Name() string
 		Type() VariableType
+		Group() string
+		Validate() error
+		Effective() string
 	}
 
 	VariableType string
@@ -45,12 +161,60 @@ type (
 		name         string
 		defaultValue T
 		t            VariableType
+		group        string
+		required     bool
+		secret       bool
+		check        func(raw string) error
+	}
+
+	Option func(o *options)
+
+	options struct {
+		group    string
+		required bool
+		secret   bool
+		check    func(raw string) error
 	}
 
 	StringVariable = DefaultVariable[string]
 	BoolVariable   = DefaultVariable[bool]
 )
 
+// Required переменная обязана быть задана в окружении (или через NAME_FILE)
+func Required() Option {
+	return func(o *options) {
+		o.required = true
+	}
+}
+
+// Secret значение переменной не выводится в логах и в --print-config
+func Secret() Option {
+	return func(o *options) {
+		o.secret = true
+	}
+}
+
+// InGroup относит переменную к группе, которую можно исключить из валидации
+func InGroup(group string) Option {
+	return func(o *options) {
+		o.group = group
+	}
+}
+
+// Check при валидации проверяет, что значение переменной разбирается экстрактором
+func Check[T any](e extractor[T]) Option {
+	return func(o *options) {
+		o.check = func(raw string) error {
+			_, err := e.Extract(raw)
+			if err != nil {
+				return fmt.Errorf("expected %s: %w", e.TargetType(), err)
+			}
+
+			return nil
+		}
+	}
+}
+
 func (v DefaultVariable[T]) Name() string {
 	return v.name
 }
@@ -59,12 +223,89 @@ func (v DefaultVariable[T]) Type() VariableType {
 	return v.t
 }
 
+func (v DefaultVariable[T]) Group() string {
+	return v.group
+}
+
 func (v DefaultVariable[T]) String() string {
+	if v.secret {
+		return fmt.Sprintf("variable '%s', default '%s'", v.name, redactedValue)
+	}
+
 	return fmt.Sprintf("variable '%s', default '%v'", v.name, v.defaultValue)
 }
 
-func Environment[T any](name string, defaultValue T) DefaultVariable[T] {
-	v := DefaultVariable[T]{name: name, defaultValue: defaultValue, t: VariableTypeEnvironment}
+// Validate проверяет наличие обязательной переменной и корректность ее значения
+func (v DefaultVariable[T]) Validate() error {
+	if strings.TrimSpace(v.name) == "" {
+		return errEmptyVariableName
+	}
+
+	if v.t != VariableTypeEnvironment {
+		return nil
+	}
+
+	raw, ok, err := lookup(v.name)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		if v.required {
+			return fmt.Errorf("environment variable '%s' is absent", v.name)
+		}
+
+		return nil
+	}
+
+	if v.check == nil {
+		return nil
+	}
+
+	if err := v.check(raw); err != nil {
+		return fmt.Errorf("environment variable '%s' is invalid: %w", v.name, err)
+	}
+
+	return nil
+}
+
+// Effective возвращает значение переменной с учетом значения по умолчанию, секреты маскируются
+func (v DefaultVariable[T]) Effective() string {
+	raw, ok, err := lookup(v.name)
+	if err != nil {
+		return fmt.Sprintf("<%s>", err)
+	}
+
+	if !ok {
+		raw = fmt.Sprintf("%v", v.defaultValue)
+	}
+
+	if v.secret && raw != "" {
+		return redactedValue
+	}
+
+	return raw
+}
+
+func Environment[T any](name string, defaultValue T, opts ...Option) DefaultVariable[T] {
+	o := options{}
+	if _, ok := any(defaultValue).(bool); ok {
+		Check[bool](Bool)(&o)
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	v := DefaultVariable[T]{
+		name:         name,
+		defaultValue: defaultValue,
+		t:            VariableTypeEnvironment,
+		group:        o.group,
+		required:     o.required,
+		secret:       o.secret,
+		check:        o.check,
+	}
 	register(v)
 
 	return v
@@ -73,3 +314,22 @@ func Environment[T any](name string, defaultValue T) DefaultVariable[T] {
 func register(v Variable) {
 	registry = append(registry, v)
 }
+
+// lookup ищет значение переменной в окружении, а если его нет — в файле по пути из NAME_FILE
+func lookup(name string) (string, bool, error) {
+	if value, ok := os.LookupEnv(name); ok {
+		return value, true, nil
+	}
+
+	path, ok := os.LookupEnv(name + fileSuffix)
+	if !ok {
+		return "", false, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read '%s%s': %w", name, fileSuffix, err)
+	}
+
+	return strings.TrimSpace(string(content)), true, nil
+}
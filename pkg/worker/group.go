@@ -0,0 +1,93 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"easy-quizy/pkg/structs"
+)
+
+type (
+	// Func фоновая задача, обязана завершиться после отмены ctx
+	Func func(ctx context.Context) error
+
+	// Group запускает фоновые задачи с общим контекстом и дожидается их остановки
+	Group struct {
+		ctx    context.Context
+		cancel context.CancelFunc
+		wg     sync.WaitGroup
+
+		mu   sync.Mutex
+		errs []error
+	}
+)
+
+func NewGroup(ctx context.Context) *Group {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Group{
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Go запускает задачу; паника и ошибка задачи не останавливают остальные задачи группы
+func (g *Group) Go(name string, fn Func) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+
+		err := structs.WithRecover(func() error {
+			return fn(g.ctx)
+		})
+		if err != nil && !errors.Is(err, context.Canceled) {
+			g.mu.Lock()
+			g.errs = append(g.errs, fmt.Errorf("worker '%s': %w", name, err))
+			g.mu.Unlock()
+		}
+	}()
+}
+
+// Stop отменяет общий контекст и ждет завершения задач, но не дольше ctx
+func (g *Group) Stop(ctx context.Context) error {
+	g.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return fmt.Errorf("workers did not stop in time: %w", ctx.Err())
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return errors.Join(g.errs...)
+}
+
+// Every оборачивает fn в задачу, которая выполняется с периодом interval до отмены контекста.
+// Ошибки отдельных запусков передаются в onError и не прерывают цикл.
+func Every(interval time.Duration, fn Func, onError func(err error)) Func {
+	return func(ctx context.Context) error {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := fn(ctx); err != nil && onError != nil && ctx.Err() == nil {
+				onError(err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+	}
+}
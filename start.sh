@@ -15,58 +15,80 @@ NC='\033[0m' # No Color
 # Configuration
 BACKEND_PORT=${SERVER_PORT:-8080}
 FRONTEND_PORT=${FRONTEND_PORT:-3000}
-SHUTDOWN_TIMEOUT=10
+FRONTEND_SHUTDOWN_TIMEOUT=10
+SHUTDOWN_MARGIN=5
 
 # Process IDs
 BACKEND_PID=""
 FRONTEND_PID=""
 
-# Cleanup function for graceful shutdown
-cleanup() {
-    echo -e "${YELLOW}Received shutdown signal, initiating graceful shutdown...${NC}"
-    
-    if [ -n "$BACKEND_PID" ] && kill -0 "$BACKEND_PID" 2>/dev/null; then
-        echo -e "${BLUE}Stopping Go backend server (PID: $BACKEND_PID)...${NC}"
-        kill -TERM "$BACKEND_PID" 2>/dev/null || true
-    fi
-    
-    if [ -n "$FRONTEND_PID" ] && kill -0 "$FRONTEND_PID" 2>/dev/null; then
-        echo -e "${BLUE}Stopping SvelteKit SSR server (PID: $FRONTEND_PID)...${NC}"
-        kill -TERM "$FRONTEND_PID" 2>/dev/null || true
+# Convert a Go duration (5s, 1m30s, 500ms) to whole seconds, rounding up
+duration_seconds() {
+    echo "$1" | awk '{
+        rest = $0; total = 0
+        while (match(rest, /^[0-9.]+(ns|us|ms|s|m|h)/)) {
+            token = substr(rest, 1, RLENGTH)
+            rest = substr(rest, RLENGTH + 1)
+            match(token, /^[0-9.]+/)
+            value = substr(token, 1, RLENGTH)
+            unit = substr(token, RLENGTH + 1)
+            if (unit == "h") total += value * 3600
+            else if (unit == "m") total += value * 60
+            else if (unit == "s") total += value
+            else if (unit == "ms") total += value / 1000
+        }
+        printf "%d", (total == int(total)) ? total : int(total) + 1
+    }'
+}
+
+# The backend first fails /readyz for SERVER_DRAIN_DELAY, then gets SERVER_SHUTDOWN_TIMEOUT
+# to finish requests and workers, so it is given both plus a margin before a force kill
+BACKEND_SHUTDOWN_TIMEOUT=$(( $(duration_seconds "${SERVER_DRAIN_DELAY:-5s}") + $(duration_seconds "${SERVER_SHUTDOWN_TIMEOUT:-10s}") + SHUTDOWN_MARGIN ))
+
+# Send TERM to a process and wait for it to exit, force killing it after the timeout
+stop_process() {
+    local pid=$1
+    local name=$2
+    local timeout=$3
+
+    if [ -z "$pid" ] || ! kill -0 "$pid" 2>/dev/null; then
+        return 0
     fi
-    
-    # Wait for graceful shutdown with timeout
-    local timeout=$SHUTDOWN_TIMEOUT
+
+    echo -e "${BLUE}Stopping $name (PID: $pid, timeout: ${timeout}s)...${NC}"
+    kill -TERM "$pid" 2>/dev/null || true
+
     while [ $timeout -gt 0 ]; do
-        local running=0
-        
-        if [ -n "$BACKEND_PID" ] && kill -0 "$BACKEND_PID" 2>/dev/null; then
-            running=$((running + 1))
-        fi
-        
-        if [ -n "$FRONTEND_PID" ] && kill -0 "$FRONTEND_PID" 2>/dev/null; then
-            running=$((running + 1))
-        fi
-        
-        if [ $running -eq 0 ]; then
-            echo -e "${GREEN}All services stopped gracefully${NC}"
-            exit 0
+        if ! kill -0 "$pid" 2>/dev/null; then
+            echo -e "${GREEN}$name stopped gracefully${NC}"
+            return 0
         fi
-        
+
         sleep 1
         timeout=$((timeout - 1))
     done
-    
-    # Force kill if graceful shutdown failed
-    echo -e "${RED}Graceful shutdown timeout, force killing processes...${NC}"
-    if [ -n "$BACKEND_PID" ]; then
-        kill -KILL "$BACKEND_PID" 2>/dev/null || true
-    fi
-    if [ -n "$FRONTEND_PID" ]; then
-        kill -KILL "$FRONTEND_PID" 2>/dev/null || true
+
+    echo -e "${RED}$name did not stop in time, force killing...${NC}"
+    kill -KILL "$pid" 2>/dev/null || true
+    return 1
+}
+
+# Cleanup function for graceful shutdown
+cleanup() {
+    echo -e "${YELLOW}Received shutdown signal, initiating graceful shutdown...${NC}"
+
+    local status=0
+
+    # The SSR server proxies /api to the backend, so it keeps running until the backend
+    # has drained and finished in-flight requests
+    stop_process "$BACKEND_PID" "Go backend server" "$BACKEND_SHUTDOWN_TIMEOUT" || status=1
+    stop_process "$FRONTEND_PID" "SvelteKit SSR server" "$FRONTEND_SHUTDOWN_TIMEOUT" || status=1
+
+    if [ $status -eq 0 ]; then
+        echo -e "${GREEN}All services stopped gracefully${NC}"
     fi
-    
-    exit 1
+
+    exit $status
 }
 
 # Set up signal handlers
@@ -12,7 +12,18 @@ export const handle: Handle = async ({ event, resolve }) => {
 		try {
 			// Filter out problematic headers and ensure proper forwarding
 			const filteredHeaders: Record<string, string> = {};
-			const skipHeaders = new Set(['host', 'connection', 'upgrade', 'expect', 'te']);
+			// Client-sent forwarding headers are dropped: the backend trusts them from this proxy
+			// and uses them for per-IP rate limits
+			const skipHeaders = new Set([
+				'host',
+				'connection',
+				'upgrade',
+				'expect',
+				'te',
+				'x-forwarded-for',
+				'x-real-ip',
+				'forwarded'
+			]);
 
 			for (const [key, value] of event.request.headers) {
 				if (!skipHeaders.has(key.toLowerCase())) {
@@ -25,6 +36,7 @@ export const handle: Handle = async ({ event, resolve }) => {
 				headers: {
 					...filteredHeaders,
 					'host': new URL(backendUrl).host,
+					'x-forwarded-for': event.getClientAddress(),
 				}
 			};
 
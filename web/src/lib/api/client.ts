@@ -38,6 +38,9 @@ async function apiRequest(endpoint: string, options: RequestInit = {}): Promise<
 				case 404:
 					errorMessage = 'Запрашиваемый ресурс не найден.';
 					break;
+				case 409:
+					errorMessage = 'Действие сейчас недоступно.';
+					break;
 				case 429:
 					errorMessage = 'Слишком много запросов. Попробуйте позже.';
 					break;
@@ -132,7 +135,7 @@ export async function getGameState(gameId: string): Promise<ApiGameState> {
 
 export async function resetGame(gameId: string): Promise<void> {
 	await apiRequest(`/api/game/${gameId}/reset`, {
-		method: 'GET',
+		method: 'POST',
 	});
 }
 export async function submitAnswer(gameId: string, questionId: number, answerId: number): Promise<ApiAnswerResponse> {